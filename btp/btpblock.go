@@ -126,3 +126,52 @@ func NewBTPBlockHeader(
 	}
 	return bb, nil
 }
+
+// NewBTPBlockHeaderFromBytes returns the BTPBlockHeader from the bytes
+// returned by HeaderBytes.
+func NewBTPBlockHeaderFromBytes(bs []byte) (module.BTPBlockHeader, error) {
+	bb := &btpBlockHeader{}
+	if _, err := codec.UnmarshalFromBytes(bs, &bb.format); err != nil {
+		return nil, err
+	}
+	return bb, nil
+}
+
+// NetworkSectionHashOf returns the hash of the network section of the
+// header.
+func NetworkSectionHashOf(mod module.NetworkTypeModule, bh module.BTPBlockHeader) []byte {
+	return mod.Hash(codec.MustMarshalToBytes(&networkSectionFormat{
+		NetworkID:    bh.NetworkID(),
+		UpdateNumber: bh.UpdateNumber(),
+		PrevHash:     bh.PrevNetworkSectionHash(),
+		MessageCount: bh.MessageCount(),
+		MessagesRoot: bh.MessagesRoot(),
+	}))
+}
+
+// NetworkSectionsRootOf returns the root of network sections of the network
+// type section calculated with the merkle path of the header.
+func NetworkSectionsRootOf(mod module.NetworkTypeModule, bh module.BTPBlockHeader) []byte {
+	root := NetworkSectionHashOf(mod, bh)
+	for _, node := range bh.NetworkSectionToRoot() {
+		if node.Value == nil {
+			continue
+		}
+		if node.Dir == module.DirLeft {
+			root = mod.Hash(append(append([]byte{}, node.Value...), root...))
+		} else {
+			root = mod.Hash(append(root, node.Value...))
+		}
+	}
+	return root
+}
+
+// NetworkTypeSectionHashOf returns the hash of the network type section of
+// the header. The proof of the header is the signature for the decision
+// with the hash.
+func NetworkTypeSectionHashOf(mod module.NetworkTypeModule, bh module.BTPBlockHeader) []byte {
+	return mod.Hash(codec.MustMarshalToBytes(&networkTypeSectionFormat{
+		NextProofContextHash: bh.NextProofContextHash(),
+		NetworkSectionsRoot:  NetworkSectionsRootOf(mod, bh),
+	}))
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/icon-project/goloop/btp/ntm"
 	"github.com/icon-project/goloop/common/codec"
 	"github.com/icon-project/goloop/module"
 )
@@ -52,4 +53,12 @@ func TestBTPBlockHeader_Basics(t *testing.T) {
 	var bb2 btpBlockHeader
 	codec.MustUnmarshalFromBytes(bs, &bb2.format)
 	assert.EqualValues(bb.(*btpBlockHeader).format, bb2.format)
+
+	bb3, err := NewBTPBlockHeaderFromBytes(bs)
+	assert.NoError(err)
+	assert.EqualValues(bs, bb3.HeaderBytes())
+	mod := ntm.ForUID(nts.NextProofContext().UID())
+	assert.EqualValues(ns.Hash(), NetworkSectionHashOf(mod, bb3))
+	assert.EqualValues(nts.NetworkSectionsRoot(), NetworkSectionsRootOf(mod, bb3))
+	assert.EqualValues(nts.Hash(), NetworkTypeSectionHashOf(mod, bb3))
 }
@@ -0,0 +1,227 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/gofrs/uuid"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+)
+
+// BLS signatures with public keys in G1 and signatures in G2.
+const (
+	blsDSA = "bls/bls12-381"
+
+	BLSPublicKeyLen = bls12381.G1SizeCompressed
+	BLSSignatureLen = bls12381.G2SizeCompressed
+	BLSSecretKeyLen = bls12381.ScalarSize
+)
+
+var blsSignatureDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_")
+
+func parseBLSPublicKey(pubKey []byte) (*bls12381.G1, error) {
+	if len(pubKey) != bls12381.G1SizeCompressed && len(pubKey) != bls12381.G1Size {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidPublicKeyLength(len=%d)", len(pubKey))
+	}
+	pk := new(bls12381.G1)
+	if err := pk.SetBytes(pubKey); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidPublicKey")
+	}
+	if pk.IsIdentity() {
+		return nil, errors.IllegalArgumentError.New("IdentityPublicKey")
+	}
+	return pk, nil
+}
+
+func parseBLSSignature(sig []byte) (*bls12381.G2, error) {
+	if len(sig) != bls12381.G2SizeCompressed {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidSignatureLength(len=%d)", len(sig))
+	}
+	s := new(bls12381.G2)
+	if err := s.SetBytes(sig); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidSignature")
+	}
+	return s, nil
+}
+
+func blsHashToPoint(msg []byte) *bls12381.G2 {
+	h := new(bls12381.G2)
+	h.Hash(msg, blsSignatureDST)
+	return h
+}
+
+// blsVerify returns whether sig is the signature of msg for pk.
+func blsVerify(pk *bls12381.G1, msg []byte, sig *bls12381.G2) bool {
+	res := bls12381.ProdPairFrac(
+		[]*bls12381.G1{bls12381.G1Generator(), pk},
+		[]*bls12381.G2{sig, blsHashToPoint(msg)},
+		[]int{1, -1},
+	)
+	return res.IsIdentity()
+}
+
+type blsWallet struct {
+	sk     *bls12381.Scalar
+	pubKey []byte
+}
+
+func (w *blsWallet) Sign(data []byte) ([]byte, error) {
+	sig := new(bls12381.G2)
+	sig.ScalarMult(w.sk, blsHashToPoint(data))
+	return sig.BytesCompressed(), nil
+}
+
+func (w *blsWallet) PublicKey() []byte {
+	return w.pubKey
+}
+
+// NewBLSWallet returns the wallet for BLS12-381 network types with the
+// secret key in big-endian order.
+func NewBLSWallet(secret []byte) (module.BaseWallet, error) {
+	if len(secret) != BLSSecretKeyLen {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidSecretKeyLength(len=%d)", len(secret))
+	}
+	sk := new(bls12381.Scalar)
+	if err := sk.UnmarshalBinary(secret); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidSecretKey")
+	}
+	if sk.IsZero() == 1 {
+		return nil, errors.IllegalArgumentError.New("ZeroSecretKey")
+	}
+	pk := new(bls12381.G1)
+	pk.ScalarMult(sk, bls12381.G1Generator())
+	return &blsWallet{
+		sk:     sk,
+		pubKey: pk.BytesCompressed(),
+	}, nil
+}
+
+type blsKeyStoreData struct {
+	PublicKey common.HexBytes   `json:"publicKey"`
+	ID        string            `json:"id"`
+	Version   int               `json:"version"`
+	DSA       string            `json:"dsa"`
+	Crypto    wallet.CryptoData `json:"crypto"`
+}
+
+// EncryptBLSKeyStore returns the keystore with the secret key encrypted
+// by the password. It's encrypted in the same way as the keystore of
+// secp256k1 keys.
+func EncryptBLSKeyStore(secret, pw []byte) ([]byte, error) {
+	w, err := NewBLSWallet(secret)
+	if err != nil {
+		return nil, err
+	}
+	cd, err := wallet.EncryptSecret(secret, pw)
+	if err != nil {
+		return nil, err
+	}
+	ks := &blsKeyStoreData{
+		PublicKey: w.PublicKey(),
+		ID:        uuid.Must(uuid.NewV4()).String(),
+		Version:   3,
+		DSA:       blsDSA,
+		Crypto:    *cd,
+	}
+	return json.Marshal(ks)
+}
+
+// NewBLSWalletFromKeyStore returns the wallet with the secret key in the
+// keystore made by EncryptBLSKeyStore.
+func NewBLSWalletFromKeyStore(data, pw []byte) (module.BaseWallet, error) {
+	var ks blsKeyStoreData
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidKeyStore")
+	}
+	if ks.DSA != blsDSA {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidDSA(dsa=%s)", ks.DSA)
+	}
+	secret, err := wallet.DecryptSecret(&ks.Crypto, pw)
+	if err != nil {
+		return nil, err
+	}
+	w, err := NewBLSWallet(secret)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(w.PublicKey(), ks.PublicKey) {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"PublicKeyMismatch(exp=%x,real=%x)", ks.PublicKey, w.PublicKey())
+	}
+	return w, nil
+}
+
+// LoadBLSWallet returns the wallet with the secret key in the keystore
+// file.
+func LoadBLSWallet(path string, pw []byte) (module.BaseWallet, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewBLSWalletFromKeyStore(bs, pw)
+}
+
+// GenerateBLSSecret returns a new random secret key for NewBLSWallet.
+func GenerateBLSSecret() []byte {
+	sk := new(bls12381.Scalar)
+	for {
+		if err := sk.Random(rand.Reader); err != nil {
+			panic(err)
+		}
+		if sk.IsZero() == 0 {
+			break
+		}
+	}
+	bs, _ := sk.MarshalBinary()
+	return bs
+}
+
+type blsDSAModule struct {
+}
+
+func (m blsDSAModule) Name() string {
+	return blsDSA
+}
+
+func (m blsDSAModule) Verify(pubKey []byte) error {
+	_, err := parseBLSPublicKey(pubKey)
+	return err
+}
+
+func (m blsDSAModule) Canonicalize(pubKey []byte) ([]byte, error) {
+	pk, err := parseBLSPublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return pk.BytesCompressed(), nil
+}
+
+var blsDSAModuleInstance blsDSAModule
+
+func init() {
+	registerDSAModule(blsDSAModuleInstance)
+	enableOnRevision(blsDSA, module.BLSNetworkType)
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+)
+
+func TestBLSDSAModule_Verify(t *testing.T) {
+	assert := assert.New(t)
+
+	w, err := NewBLSWallet(GenerateBLSSecret())
+	assert.NoError(err)
+	dsam := DSAModuleForName(blsDSA)
+	assert.NoError(dsam.Verify(w.PublicKey()))
+
+	pkBytes := w.PublicKey()
+	assert.Error(dsam.Verify(pkBytes[:len(pkBytes)-1]))
+
+	pk, err := parseBLSPublicKey(pkBytes)
+	assert.NoError(err)
+	assert.NoError(dsam.Verify(pk.Bytes()))
+	cpk, err := dsam.Canonicalize(pk.Bytes())
+	assert.NoError(err)
+	assert.Equal(pkBytes, cpk)
+
+	id := new(bls12381.G1)
+	id.SetIdentity()
+	assert.Error(dsam.Verify(id.BytesCompressed()))
+}
+
+func TestBLSWallet_Sign(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewBLSWallet(make([]byte, BLSSecretKeyLen))
+	assert.Error(err)
+
+	secret := GenerateBLSSecret()
+	w, err := NewBLSWallet(secret)
+	assert.NoError(err)
+	w2, err := NewBLSWallet(secret)
+	assert.NoError(err)
+	assert.Equal(w.PublicKey(), w2.PublicKey())
+
+	msg := keccak256([]byte("abc"))
+	sig, err := w.Sign(msg)
+	assert.NoError(err)
+	assert.Len(sig, BLSSignatureLen)
+
+	pk, err := parseBLSPublicKey(w.PublicKey())
+	assert.NoError(err)
+	s, err := parseBLSSignature(sig)
+	assert.NoError(err)
+	assert.True(blsVerify(pk, msg, s))
+	assert.False(blsVerify(pk, keccak256([]byte("abcd")), s))
+}
+
+func TestBLSWallet_KeyStore(t *testing.T) {
+	assert := assert.New(t)
+
+	secret := GenerateBLSSecret()
+	w, err := NewBLSWallet(secret)
+	assert.NoError(err)
+
+	ks, err := EncryptBLSKeyStore(secret, []byte("password"))
+	assert.NoError(err)
+	assert.NotContains(string(ks), hex.EncodeToString(secret))
+
+	w2, err := NewBLSWalletFromKeyStore(ks, []byte("password"))
+	assert.NoError(err)
+	assert.Equal(w.PublicKey(), w2.PublicKey())
+
+	_, err = NewBLSWalletFromKeyStore(ks, []byte("invalid"))
+	assert.Error(err)
+
+	var ksData map[string]interface{}
+	assert.NoError(json.Unmarshal(ks, &ksData))
+	ksData["dsa"] = "ecdsa/secp256k1"
+	ks2, err := json.Marshal(ksData)
+	assert.NoError(err)
+	_, err = NewBLSWalletFromKeyStore(ks2, []byte("password"))
+	assert.Error(err)
+
+	path := filepath.Join(t.TempDir(), "bls_keystore.json")
+	assert.NoError(os.WriteFile(path, ks, 0600))
+	w3, err := LoadBLSWallet(path, []byte("password"))
+	assert.NoError(err)
+	assert.Equal(w.PublicKey(), w3.PublicKey())
+}
+
+func TestBLS_IsEnabled(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, name := range []string{blsUID, blsDSA} {
+		assert.False(IsEnabled(name, module.NoRevision))
+		assert.False(IsEnabled(name, module.LatestRevision^module.BLSNetworkType))
+		assert.True(IsEnabled(name, module.BLSNetworkType))
+	}
+	assert.True(IsEnabled("eth", module.NoRevision))
+	assert.True(IsEnabled(secp256k1DSA, module.NoRevision))
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+)
+
+// BLS module uses keccak256 like ETH module, and the proof is the aggregated
+// BLS12-381 signature of validators with the bitmap of signers.
+
+const (
+	blsUID        = "bls"
+	blsAddressLen = 20
+
+	blsBytesByHash = "b" + db.BytesByHash
+	blsListByRoot  = "b" + db.ListByMerkleRootBase
+)
+
+var blsModuleInstance *networkTypeModule
+
+type blsModuleCore struct{}
+
+func (m *blsModuleCore) UID() string {
+	return blsUID
+}
+
+func (m *blsModuleCore) AppendHash(out []byte, data []byte) []byte {
+	return appendKeccak256(out, data)
+}
+
+func (m *blsModuleCore) DSAModule() module.DSAModule {
+	return blsDSAModuleInstance
+}
+
+func (m *blsModuleCore) NewProofContextFromBytes(bs []byte) (proofContextCore, error) {
+	return newBLSProofContextFromBytes(blsModuleInstance, bs)
+}
+
+func (m *blsModuleCore) NewProofContext(keys [][]byte) (proofContextCore, error) {
+	return newBLSProofContext(blsModuleInstance, keys)
+}
+
+func (m *blsModuleCore) AddressFromPubKey(pubKey []byte) ([]byte, error) {
+	pk, err := blsDSAModuleInstance.Canonicalize(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	digest := keccak256(pk)
+	return digest[len(digest)-blsAddressLen:], nil
+}
+
+func (m *blsModuleCore) BytesByHashBucket() db.BucketID {
+	return blsBytesByHash
+}
+
+func (m *blsModuleCore) ListByMerkleRootBucket() db.BucketID {
+	return blsListByRoot
+}
+
+func (m *blsModuleCore) NewProofFromBytes(bs []byte) (module.BTPProof, error) {
+	return newBLSProofFromBytes(bs)
+}
+
+func (m *blsModuleCore) NetworkTypeKeyFromDSAKey(key []byte) ([]byte, error) {
+	return key, nil
+}
+
+func init() {
+	blsModuleInstance = register(blsUID, &blsModuleCore{})
+	enableOnRevision(blsUID, module.BLSNetworkType)
+}
@@ -0,0 +1,408 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"bytes"
+	"math/bits"
+	"sync"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+
+	"github.com/icon-project/goloop/common/cache"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+const blsCoefficientLen = 16
+
+// blsBitmap is the set of validator indexes. Bit i%8 of byte i/8 is for
+// the validator i.
+type blsBitmap []byte
+
+func newBLSBitmap(n int) blsBitmap {
+	return make(blsBitmap, (n+7)/8)
+}
+
+func (b blsBitmap) get(i int) bool {
+	return i >= 0 && i/8 < len(b) && b[i/8]&(1<<(i%8)) != 0
+}
+
+func (b blsBitmap) set(i int) {
+	b[i/8] |= 1 << (i % 8)
+}
+
+func (b blsBitmap) count() int {
+	c := 0
+	for _, v := range b {
+		c += bits.OnesCount8(v)
+	}
+	return c
+}
+
+func (b blsBitmap) isSubsetOf(o blsBitmap) bool {
+	for i, v := range b {
+		if v&^o[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (b blsBitmap) intersects(o blsBitmap) bool {
+	for i, v := range b {
+		if v&o[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// blsProofPart is the signature of a validator multiplied by the coefficient
+// of the validator. If Signers is not nil, Signature is the aggregated
+// signature of Signers including the validator, which is taken from a
+// proof.
+type blsProofPart struct {
+	Index     int
+	Signature []byte
+	Signers   blsBitmap
+}
+
+func (pp *blsProofPart) Bytes() []byte {
+	return codec.MustMarshalToBytes(pp)
+}
+
+// blsProof is the aggregated signature of validators in Signers.
+type blsProof struct {
+	Count     int
+	Signers   blsBitmap
+	Signature []byte
+	bytes     []byte
+}
+
+func newBLSProofFromBytes(bs []byte) (*blsProof, error) {
+	var p blsProof
+	_, err := codec.UnmarshalFromBytes(bs, &p)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (p *blsProof) Bytes() []byte {
+	if p.bytes == nil {
+		p.bytes = codec.MustMarshalToBytes(p)
+	}
+	return p.bytes
+}
+
+func (p *blsProof) addSignature(sig []byte) bool {
+	s, err := parseBLSSignature(sig)
+	if err != nil {
+		return false
+	}
+	if p.Signature != nil {
+		agg, err := parseBLSSignature(p.Signature)
+		if err != nil {
+			return false
+		}
+		s.Add(s, agg)
+	}
+	p.Signature = s.BytesCompressed()
+	return true
+}
+
+// Add adds the signature of the proof part. Proof parts taken from a proof
+// are merged only if they don't overlap with signers already added.
+func (p *blsProof) Add(pp module.BTPProofPart) {
+	bpp := pp.(*blsProofPart)
+	if len(p.Signers) != (p.Count+7)/8 {
+		return
+	}
+	if bpp.Signers == nil {
+		if bpp.Index < 0 || bpp.Index >= p.Count || p.Signers.get(bpp.Index) {
+			return
+		}
+		if p.addSignature(bpp.Signature) {
+			p.Signers.set(bpp.Index)
+			p.bytes = nil
+		}
+		return
+	}
+	if len(bpp.Signers) != len(p.Signers) || bpp.Signers.isSubsetOf(p.Signers) {
+		return
+	}
+	if p.Signers.intersects(bpp.Signers) {
+		if bpp.Signers.count() <= p.Signers.count() {
+			return
+		}
+		p.Signature = nil
+		p.Signers = newBLSBitmap(p.Count)
+	}
+	if p.addSignature(bpp.Signature) {
+		for i, v := range bpp.Signers {
+			p.Signers[i] |= v
+		}
+		p.bytes = nil
+	}
+}
+
+func (p *blsProof) ValidatorCount() int {
+	return p.Count
+}
+
+// ProofPartAt returns the proof part with the aggregated signature, because
+// signatures of each validator can't be taken from the aggregated one.
+func (p *blsProof) ProofPartAt(i int) module.BTPProofPart {
+	if !p.Signers.get(i) || i >= p.Count {
+		return nil
+	}
+	return &blsProofPart{
+		Index:     i,
+		Signature: p.Signature,
+		Signers:   p.Signers,
+	}
+}
+
+// blsProofContext verifies aggregated signatures of validators. To prevent
+// rogue key attacks without proof of possession, public key of each
+// validator is multiplied by the coefficient derived from the public key
+// and all the public keys of the context.
+type blsProofContext struct {
+	Validators [][]byte
+	mod        *networkTypeModule
+	bytes      cache.ByteSlice
+	keys       []*bls12381.G1
+	coeffs     []*bls12381.Scalar
+	keyToIndex map[string]int
+
+	lock     sync.Mutex
+	verified []byte
+}
+
+func (pc *blsProofContext) init() error {
+	h := crypto.SHA3Sum256(bytes.Join(pc.Validators, nil))
+	pc.keys = make([]*bls12381.G1, len(pc.Validators))
+	pc.coeffs = make([]*bls12381.Scalar, len(pc.Validators))
+	pc.keyToIndex = make(map[string]int, len(pc.Validators))
+	for i, key := range pc.Validators {
+		if len(key) == 0 {
+			continue
+		}
+		pk, err := parseBLSPublicKey(key)
+		if err != nil {
+			return errors.Wrapf(err, "invalid public key index=%d key=%x", i, key)
+		}
+		coeff := new(bls12381.Scalar)
+		coeff.SetBytes(crypto.SHA3Sum256(bytes.Join([][]byte{h, key}, nil))[:blsCoefficientLen])
+		pk.ScalarMult(coeff, pk)
+		pc.keys[i] = pk
+		pc.coeffs[i] = coeff
+		pc.keyToIndex[string(key)] = i
+	}
+	return nil
+}
+
+func newBLSProofContext(
+	mod *networkTypeModule,
+	keys [][]byte,
+) (*blsProofContext, error) {
+	pc := &blsProofContext{
+		Validators: make([][]byte, 0, len(keys)),
+		mod:        mod,
+	}
+	for i, key := range keys {
+		if key != nil {
+			pk, err := parseBLSPublicKey(key)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid public key index=%d key=%x", i, key)
+			}
+			key = pk.BytesCompressed()
+		}
+		pc.Validators = append(pc.Validators, key)
+	}
+	if err := pc.init(); err != nil {
+		return nil, err
+	}
+	return pc, nil
+}
+
+func newBLSProofContextFromBytes(
+	mod *networkTypeModule,
+	bytes []byte,
+) (*blsProofContext, error) {
+	pc := &blsProofContext{
+		mod: mod,
+	}
+	if bytes != nil {
+		_, err := codec.UnmarshalFromBytes(bytes, pc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := pc.init(); err != nil {
+		return nil, err
+	}
+	return pc, nil
+}
+
+func (pc *blsProofContext) NetworkTypeModule() module.NetworkTypeModule {
+	return pc.mod
+}
+
+func (pc *blsProofContext) Bytes() []byte {
+	return pc.bytes.Get(func() []byte {
+		if pc.Validators == nil {
+			return nil
+		}
+		return codec.MustMarshalToBytes(pc)
+	})
+}
+
+func (pc *blsProofContext) verifyAggregate(dHash []byte, signers blsBitmap, sig []byte) error {
+	if len(signers) != (len(pc.Validators)+7)/8 {
+		return errors.Errorf("invalid signers length=%d numValidators=%d", len(signers), len(pc.Validators))
+	}
+	key := bytes.Join([][]byte{dHash, signers, sig}, nil)
+	pc.lock.Lock()
+	verified := bytes.Equal(pc.verified, key)
+	pc.lock.Unlock()
+	if verified {
+		return nil
+	}
+
+	apk := new(bls12381.G1)
+	apk.SetIdentity()
+	for i := range pc.Validators {
+		if !signers.get(i) {
+			continue
+		}
+		if pc.keys[i] == nil {
+			return errors.Errorf("signer without public key index=%d", i)
+		}
+		apk.Add(apk, pc.keys[i])
+	}
+	for i := len(pc.Validators); i < len(signers)*8; i++ {
+		if signers.get(i) {
+			return errors.Errorf("invalid signer index=%d numValidators=%d", i, len(pc.Validators))
+		}
+	}
+	s, err := parseBLSSignature(sig)
+	if err != nil {
+		return err
+	}
+	if !blsVerify(apk, dHash, s) {
+		return errors.Errorf("invalid aggregated signature signers=%x", []byte(signers))
+	}
+	pc.lock.Lock()
+	pc.verified = key
+	pc.lock.Unlock()
+	return nil
+}
+
+// VerifyPart returns validator index and error
+func (pc *blsProofContext) VerifyPart(dHash []byte, pp module.BTPProofPart) (int, error) {
+	bpp := pp.(*blsProofPart)
+	if bpp.Index < 0 || bpp.Index >= len(pc.Validators) {
+		return -1, errors.Errorf("invalid proof part index=%d numValidators=%d", bpp.Index, len(pc.Validators))
+	}
+	if pc.keys[bpp.Index] == nil {
+		return -1, errors.Errorf("invalid proof part. no public key index=%d", bpp.Index)
+	}
+	if bpp.Signers != nil {
+		if !bpp.Signers.get(bpp.Index) {
+			return -1, errors.Errorf("invalid proof part. not a signer index=%d signers=%x", bpp.Index, []byte(bpp.Signers))
+		}
+		if err := pc.verifyAggregate(dHash, bpp.Signers, bpp.Signature); err != nil {
+			return -1, err
+		}
+		return bpp.Index, nil
+	}
+	sig, err := parseBLSSignature(bpp.Signature)
+	if err != nil {
+		return -1, err
+	}
+	if !blsVerify(pc.keys[bpp.Index], dHash, sig) {
+		return -1, errors.Errorf("invalid proof part. bad signature index=%d key=%x", bpp.Index, pc.Validators[bpp.Index])
+	}
+	return bpp.Index, nil
+}
+
+func (pc *blsProofContext) NewProofPartFromBytes(ppBytes []byte) (module.BTPProofPart, error) {
+	var pp blsProofPart
+	_, err := codec.UnmarshalFromBytes(ppBytes, &pp)
+	if err != nil {
+		return nil, err
+	}
+	return &pp, nil
+}
+
+func (pc *blsProofContext) Verify(dHash []byte, p module.BTPProof) error {
+	bp := p.(*blsProof)
+	if bp.Count != len(pc.Validators) {
+		return errors.Errorf("invalid validator count numValidators=%d count=%d", len(pc.Validators), bp.Count)
+	}
+	valid := bp.Signers.count()
+	if valid <= 2*len(pc.Validators)/3 {
+		return errors.Errorf("not enough proof parts numValidator=%d numProofParts=%d", len(pc.Validators), valid)
+	}
+	return pc.verifyAggregate(dHash, bp.Signers, bp.Signature)
+}
+
+func (pc *blsProofContext) NewProofFromBytes(proofBytes []byte) (module.BTPProof, error) {
+	return newBLSProofFromBytes(proofBytes)
+}
+
+func (pc *blsProofContext) NewProofPart(
+	dHash []byte,
+	wp module.WalletProvider,
+) (module.BTPProofPart, error) {
+	w := wp.WalletFor(blsDSA)
+	if w == nil {
+		return nil, errors.Errorf("no wallet for uid=%s dsa=%s", pc.mod.UID(), blsDSA)
+	}
+	idx, ok := pc.keyToIndex[string(w.PublicKey())]
+	if !ok {
+		return nil, errors.Errorf("not validator key=%x", w.PublicKey())
+	}
+	sig, err := w.Sign(dHash)
+	if err != nil {
+		return nil, err
+	}
+	s, err := parseBLSSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	s.ScalarMult(pc.coeffs[idx], s)
+	return &blsProofPart{
+		Index:     idx,
+		Signature: s.BytesCompressed(),
+	}, nil
+}
+
+func (pc *blsProofContext) DSA() string {
+	return blsDSA
+}
+
+func (pc *blsProofContext) NewProof() module.BTPProof {
+	return &blsProof{
+		Count:   len(pc.Validators),
+		Signers: newBLSBitmap(len(pc.Validators)),
+	}
+}
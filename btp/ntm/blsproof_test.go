@@ -0,0 +1,189 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/module"
+)
+
+func newBLSWalletProvider(t *testing.T) (*walletProvider, module.BaseWallet) {
+	w, err := NewBLSWallet(GenerateBLSSecret())
+	assert.NoError(t, err)
+	wp := walletProvider{
+		wallets: map[string]module.BaseWallet{
+			blsDSA: w,
+		},
+	}
+	return &wp, w
+}
+
+func newBLSTestSetup(t *testing.T, count int) *testSetup {
+	s := &testSetup{
+		assert:  assert.New(t),
+		count:   count,
+		wallets: make([]*walletProvider, 0, count),
+		pubKeys: make([][]byte, 0, count),
+	}
+	for i := 0; i < count; i++ {
+		wp, w := newBLSWalletProvider(t)
+		s.wallets = append(s.wallets, wp)
+		s.pubKeys = append(s.pubKeys, w.PublicKey())
+	}
+	var err error
+	s.pc, err = blsModuleInstance.NewProofContext(s.pubKeys)
+	assert.NoError(t, err)
+	return s
+}
+
+func TestBLSProofContext_NewProofPart(t *testing.T) {
+	s := newBLSTestSetup(t, 4)
+	msgHash := keccak256([]byte("abc"))
+	for i := 0; i < s.count; i++ {
+		pp, err := s.pc.NewProofPart(msgHash, s.wallets[i])
+		s.assert.NoError(err)
+		idx, err := s.pc.VerifyPart(msgHash, pp)
+		s.assert.NoError(err)
+		s.assert.Equal(i, idx)
+
+		pp2, err := s.pc.NewProofPartFromBytes(pp.Bytes())
+		s.assert.NoError(err)
+		_, err = s.pc.VerifyPart(msgHash, pp2)
+		s.assert.NoError(err)
+		_, err = s.pc.VerifyPart(keccak256([]byte("abcd")), pp2)
+		s.assert.Error(err)
+	}
+
+	wp, _ := newBLSWalletProvider(t)
+	_, err := s.pc.NewProofPart(msgHash, wp)
+	s.assert.Error(err)
+
+	wp2, _ := newSecp256k1WalletProvider()
+	_, err = s.pc.NewProofPart(msgHash, wp2)
+	s.assert.Error(err)
+}
+
+func TestBLSProofContext_Verify(t *testing.T) {
+	msgHash := keccak256([]byte("abc"))
+	testCase := []struct {
+		ok      bool
+		ppCount int
+		pkCount int
+	}{
+		{false, 0, 1},
+		{true, 1, 1},
+		{false, 2, 3},
+		{true, 3, 3},
+		{false, 2, 4},
+		{true, 3, 4},
+		{false, 4, 7},
+		{true, 5, 7},
+	}
+	for _, c := range testCase {
+		s := newBLSTestSetup(t, c.pkCount)
+		p := s.newProofOfLen(c.ppCount, msgHash)
+		s.assert.Equal(c.pkCount, p.ValidatorCount())
+		err := s.pc.Verify(msgHash, p)
+		if c.ok {
+			s.assert.NoError(err, "Verify ppCount=%d pkCount=%d", c.ppCount, c.pkCount)
+		} else {
+			s.assert.Error(err, "Verify ppCount=%d pkCount=%d", c.ppCount, c.pkCount)
+		}
+		p2, err := blsModuleInstance.NewProofFromBytes(p.Bytes())
+		s.assert.NoError(err)
+		err = s.pc.Verify(msgHash, p2)
+		if c.ok {
+			s.assert.NoError(err, "VerifyByProofBytes ppCount=%d pkCount=%d", c.ppCount, c.pkCount)
+		} else {
+			s.assert.Error(err, "VerifyByProofBytes ppCount=%d pkCount=%d", c.ppCount, c.pkCount)
+		}
+	}
+}
+
+func TestBLSProofContext_Verify_Fail(t *testing.T) {
+	s := newBLSTestSetup(t, 4)
+	s2 := newBLSTestSetup(t, 4)
+	msgHash := keccak256([]byte("abc"))
+
+	p := s.newProofOfLen(3, msgHash)
+	s.assert.Error(s.pc.Verify(keccak256([]byte("abcd")), p))
+
+	// signature of other validator set
+	p = s.newProofOfLen(2, msgHash)
+	pp, err := s2.pc.NewProofPart(msgHash, s2.wallets[3])
+	s.assert.NoError(err)
+	p.Add(pp)
+	s.assert.Error(s.pc.Verify(msgHash, p))
+
+	// duplicated proof parts are ignored
+	p = s.newProofOfLen(2, msgHash)
+	pp, err = s.pc.NewProofPart(msgHash, s.wallets[0])
+	s.assert.NoError(err)
+	p.Add(pp)
+	s.assert.Error(s.pc.Verify(msgHash, p))
+}
+
+func TestBLSProof_ProofPartAt(t *testing.T) {
+	s := newBLSTestSetup(t, 4)
+	msgHash := keccak256([]byte("abc"))
+	p := s.newProofOfLen(3, msgHash)
+	s.assert.NoError(s.pc.Verify(msgHash, p))
+
+	p2 := s.pc.NewProof()
+	for i := 0; i < p.ValidatorCount(); i++ {
+		pp := p.ProofPartAt(i)
+		if i >= 3 {
+			s.assert.Nil(pp)
+			continue
+		}
+		pp, err := s.pc.NewProofPartFromBytes(pp.Bytes())
+		s.assert.NoError(err)
+		idx, err := s.pc.VerifyPart(msgHash, pp)
+		s.assert.NoError(err)
+		s.assert.Equal(i, idx)
+		p2.Add(pp)
+	}
+	s.assert.Equal(p.Bytes(), p2.Bytes())
+
+	pp := p.ProofPartAt(0).(*blsProofPart)
+	pp.Index = 3
+	_, err := s.pc.VerifyPart(msgHash, pp)
+	s.assert.Error(err)
+}
+
+func TestBLSProofContext_codec(t *testing.T) {
+	s := newBLSTestSetup(t, 4)
+	msgHash := keccak256([]byte("abc"))
+	p := s.newProofOfLen(3, msgHash)
+
+	bp := p.(*blsProof)
+	var bp2 blsProof
+	codec.MustUnmarshalFromBytes(p.Bytes(), &bp2)
+	s.assert.Equal(bp.Signature, bp2.Signature)
+
+	pc2, err := blsModuleInstance.NewProofContextFromBytes(s.pc.Bytes())
+	s.assert.NoError(err)
+	s.assert.Equal(s.pc.Hash(), pc2.Hash())
+	s.assert.NoError(pc2.Verify(msgHash, p))
+	s.pc = pc2
+	p2 := s.newProofOfLen(3, msgHash)
+	s.assert.Equal(p.Bytes(), p2.Bytes())
+}
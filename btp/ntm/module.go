@@ -198,6 +198,22 @@ func ForUID(uid string) module.NetworkTypeModule {
 	return modules[uid]
 }
 
+// revisions has flags of the revision enabling modules added later.
+var revisions = make(map[string]module.Revision)
+
+// IsEnabled returns whether new networks and public keys of the network type
+// module or the DSA module of the name are allowed in the revision.
+func IsEnabled(name string, rev module.Revision) bool {
+	if flag, ok := revisions[name]; ok {
+		return rev.Has(flag)
+	}
+	return true
+}
+
+func enableOnRevision(name string, flag module.Revision) {
+	revisions[name] = flag
+}
+
 type simpleHasher struct {
 	mod module.NetworkTypeModule
 }
@@ -0,0 +1,183 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package verifier verifies outputs of btp_getHeader, btp_getProof and
+// btp_getMessages for a BTP network as a relay does. A Verifier follows
+// BTP blocks of a network in order and tracks the proof context (validators)
+// of the network.
+package verifier
+
+import (
+	"bytes"
+
+	"github.com/icon-project/goloop/btp"
+	"github.com/icon-project/goloop/btp/ntm"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+// State is the verified state of a BTP network. It can be stored and loaded
+// to resume verification.
+type State struct {
+	SrcNetworkUID  string `json:"srcNetworkUID"`
+	NetworkTypeID  int64  `json:"networkTypeID"`
+	NetworkTypeUID string `json:"networkTypeUID"`
+	NetworkID      int64  `json:"networkID"`
+
+	// Height is the main height of the last verified BTP block. Zero if
+	// no BTP block is verified.
+	Height                 int64           `json:"height"`
+	NextMessageSN          int64           `json:"nextMessageSN"`
+	LastNetworkSectionHash common.HexBytes `json:"lastNetworkSectionHash,omitempty"`
+	ProofContext           common.HexBytes `json:"proofContext,omitempty"`
+}
+
+type Verifier struct {
+	state State
+	mod   module.NetworkTypeModule
+	pc    module.BTPProofContext
+}
+
+// NewVerifier returns a new verifier for the state. If the state has no
+// proof context, the first BTP block of the network given to Verify is
+// trusted as an anchor.
+func NewVerifier(s *State) (*Verifier, error) {
+	mod := ntm.ForUID(s.NetworkTypeUID)
+	if mod == nil {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"UnknownNetworkTypeUID(uid=%s)", s.NetworkTypeUID)
+	}
+	v := &Verifier{
+		state: *s,
+		mod:   mod,
+	}
+	if len(s.ProofContext) > 0 {
+		pc, err := mod.NewProofContextFromBytes(s.ProofContext)
+		if err != nil {
+			return nil, err
+		}
+		v.pc = pc
+	}
+	return v, nil
+}
+
+// State returns the state after the last verified BTP block.
+func (v *Verifier) State() *State {
+	s := v.state
+	return &s
+}
+
+func (v *Verifier) NetworkTypeModule() module.NetworkTypeModule {
+	return v.mod
+}
+
+// Verify verifies the header and the proof of the next BTP block of the
+// network, then updates the state. It returns the verified header.
+func (v *Verifier) Verify(headerBytes []byte, proofBytes []byte) (module.BTPBlockHeader, error) {
+	bh, err := btp.NewBTPBlockHeaderFromBytes(headerBytes)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidHeader")
+	}
+	if bh.NetworkID() != v.state.NetworkID {
+		return nil, errors.InvalidStateError.Errorf(
+			"InvalidNetworkID(exp=%d,got=%d)", v.state.NetworkID, bh.NetworkID())
+	}
+	if bh.MainHeight() <= v.state.Height {
+		return nil, errors.InvalidStateError.Errorf(
+			"InvalidHeight(last=%d,got=%d)", v.state.Height, bh.MainHeight())
+	}
+	nsHash := btp.NetworkSectionHashOf(v.mod, bh)
+	var nextPC module.BTPProofContext
+	if bh.NextProofContextChanged() {
+		if len(bh.NextProofContext()) == 0 {
+			return nil, errors.InvalidStateError.Errorf(
+				"NoNextProofContext(height=%d)", bh.MainHeight())
+		}
+		nextPC, err = v.mod.NewProofContextFromBytes(bh.NextProofContext())
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(nextPC.Hash(), bh.NextProofContextHash()) {
+			return nil, errors.InvalidStateError.Errorf(
+				"InvalidNextProofContext(height=%d)", bh.MainHeight())
+		}
+	}
+
+	if v.pc == nil {
+		if nextPC == nil || len(bh.PrevNetworkSectionHash()) > 0 {
+			return nil, errors.InvalidStateError.Errorf(
+				"NotFirstBTPBlock(height=%d)", bh.MainHeight())
+		}
+		v.state.NextMessageSN = bh.FirstMessageSN()
+	} else {
+		if nextPC == nil && !bytes.Equal(v.pc.Hash(), bh.NextProofContextHash()) {
+			return nil, errors.InvalidStateError.Errorf(
+				"InvalidNextProofContextHash(height=%d)", bh.MainHeight())
+		}
+		if !bytes.Equal(bh.PrevNetworkSectionHash(), v.state.LastNetworkSectionHash) {
+			return nil, errors.InvalidStateError.Errorf(
+				"InvalidPrevNetworkSectionHash(height=%d,exp=%x,got=%x)",
+				bh.MainHeight(), []byte(v.state.LastNetworkSectionHash),
+				bh.PrevNetworkSectionHash())
+		}
+		if bh.FirstMessageSN() != v.state.NextMessageSN {
+			return nil, errors.InvalidStateError.Errorf(
+				"InvalidFirstMessageSN(height=%d,exp=%d,got=%d)",
+				bh.MainHeight(), v.state.NextMessageSN, bh.FirstMessageSN())
+		}
+		proof, err := v.pc.NewProofFromBytes(proofBytes)
+		if err != nil {
+			return nil, errors.IllegalArgumentError.Wrap(err, "InvalidProof")
+		}
+		decision := v.pc.NewDecision(
+			[]byte(v.state.SrcNetworkUID),
+			v.state.NetworkTypeID,
+			bh.MainHeight(),
+			bh.Round(),
+			btp.NetworkTypeSectionHashOf(v.mod, bh),
+		)
+		if err := v.pc.Verify(decision.Hash(), proof); err != nil {
+			return nil, err
+		}
+	}
+
+	if nextPC != nil {
+		v.pc = nextPC
+		v.state.ProofContext = nextPC.Bytes()
+	}
+	v.state.Height = bh.MainHeight()
+	v.state.NextMessageSN += bh.MessageCount()
+	v.state.LastNetworkSectionHash = nsHash
+	return bh, nil
+}
+
+// VerifyMessages verifies that msgs are the messages of the verified header.
+func (v *Verifier) VerifyMessages(bh module.BTPBlockHeader, msgs [][]byte) error {
+	if int64(len(msgs)) != bh.MessageCount() {
+		return errors.InvalidStateError.Errorf(
+			"InvalidMessageCount(exp=%d,got=%d)", bh.MessageCount(), len(msgs))
+	}
+	hashes := make(module.BytesSlice, 0, len(msgs))
+	for _, msg := range msgs {
+		hashes = append(hashes, v.mod.Hash(msg))
+	}
+	if root := v.mod.MerkleRoot(&hashes); !bytes.Equal(root, bh.MessagesRoot()) {
+		return errors.InvalidStateError.Errorf(
+			"InvalidMessagesRoot(exp=%x,got=%x)", bh.MessagesRoot(), root)
+	}
+	return nil
+}
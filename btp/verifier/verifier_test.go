@@ -0,0 +1,250 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package verifier
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/btp"
+	"github.com/icon-project/goloop/btp/ntm"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	testSrcUID = "0x1.icon"
+	testNTID   = 1
+	testNID    = 1
+)
+
+// headerFormat has the same layout as the header returned by btp_getHeader.
+type headerFormat struct {
+	MainHeight             int64
+	Round                  int32
+	NextProofContextHash   []byte
+	NetworkSectionToRoot   []module.MerkleNode
+	NetworkID              int64
+	UpdateNumber           int64
+	PrevNetworkSectionHash []byte
+	MessageCount           int64
+	MessagesRoot           []byte
+	NextProofContext       []byte
+}
+
+type walletProvider struct {
+	w module.BaseWallet
+}
+
+func (wp *walletProvider) WalletFor(dsa string) module.BaseWallet {
+	return wp.w
+}
+
+type testNetwork struct {
+	t       *testing.T
+	mod     module.NetworkTypeModule
+	wallets []module.WalletProvider
+	pc      module.BTPProofContext
+	height  int64
+	nextSN  int64
+	prev    []byte
+}
+
+func newTestNetwork(t *testing.T) *testNetwork {
+	return &testNetwork{
+		t:      t,
+		mod:    ntm.ForUID("eth"),
+		height: 10,
+	}
+}
+
+func (n *testNetwork) newValidators(count int) module.BTPProofContext {
+	wallets := make([]module.WalletProvider, 0, count)
+	keys := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		w := wallet.New()
+		keys = append(keys, w.PublicKey())
+		wallets = append(wallets, &walletProvider{w})
+	}
+	pc, err := n.mod.NewProofContext(keys)
+	assert.NoError(n.t, err)
+	n.wallets = wallets
+	return pc
+}
+
+// next returns the header and the proof of the next BTP block. If nextPC is
+// not nil, validators are changed to nextPC after the block.
+func (n *testNetwork) next(msgs [][]byte, nextPC module.BTPProofContext, oldWallets []module.WalletProvider) ([]byte, []byte) {
+	hashes := make(module.BytesSlice, 0, len(msgs))
+	for _, msg := range msgs {
+		hashes = append(hashes, n.mod.Hash(msg))
+	}
+	f := &headerFormat{
+		MainHeight: n.height,
+		NetworkSectionToRoot: []module.MerkleNode{
+			{Dir: module.DirRight, Value: n.mod.Hash([]byte("right"))},
+			{Dir: module.DirLeft, Value: n.mod.Hash([]byte("left"))},
+			{Dir: module.DirRight, Value: nil},
+		},
+		NetworkID:              testNID,
+		UpdateNumber:           n.nextSN << 1,
+		PrevNetworkSectionHash: n.prev,
+		MessageCount:           int64(len(msgs)),
+		MessagesRoot:           n.mod.MerkleRoot(&hashes),
+	}
+	if nextPC != nil {
+		f.UpdateNumber |= 1
+		f.NextProofContextHash = nextPC.Hash()
+		f.NextProofContext = nextPC.Bytes()
+	} else {
+		f.NextProofContextHash = n.pc.Hash()
+	}
+	hb := codec.MustMarshalToBytes(f)
+	bh, err := btp.NewBTPBlockHeaderFromBytes(hb)
+	assert.NoError(n.t, err)
+
+	var pb []byte
+	if n.pc != nil {
+		pb = n.proofFor(bh, oldWallets)
+	}
+	if nextPC != nil {
+		n.pc = nextPC
+	}
+	n.height += 3
+	n.nextSN += int64(len(msgs))
+	n.prev = btp.NetworkSectionHashOf(n.mod, bh)
+	return hb, pb
+}
+
+func (n *testNetwork) proofFor(bh module.BTPBlockHeader, wallets []module.WalletProvider) []byte {
+	decision := n.pc.NewDecision(
+		[]byte(testSrcUID), testNTID, bh.MainHeight(), bh.Round(),
+		btp.NetworkTypeSectionHashOf(n.mod, bh),
+	)
+	proof := n.pc.NewProof()
+	for _, wp := range wallets {
+		pp, err := n.pc.NewProofPart(decision.Hash(), wp)
+		assert.NoError(n.t, err)
+		proof.Add(pp)
+	}
+	return proof.Bytes()
+}
+
+func newTestState() *State {
+	return &State{
+		SrcNetworkUID:  testSrcUID,
+		NetworkTypeID:  testNTID,
+		NetworkTypeUID: "eth",
+		NetworkID:      testNID,
+	}
+}
+
+func TestVerifier_Basics(t *testing.T) {
+	assert := assert.New(t)
+	n := newTestNetwork(t)
+
+	v, err := NewVerifier(newTestState())
+	assert.NoError(err)
+
+	// anchor
+	pc := n.newValidators(4)
+	hb, pb := n.next(nil, pc, nil)
+	_, err = v.Verify(hb, pb)
+	assert.NoError(err)
+
+	// messages
+	msgs := [][]byte{[]byte("m0"), []byte("m1"), []byte("m2")}
+	hb, pb = n.next(msgs, nil, n.wallets)
+	bh, err := v.Verify(hb, pb)
+	assert.NoError(err)
+	assert.EqualValues(0, bh.FirstMessageSN())
+	assert.NoError(v.VerifyMessages(bh, msgs))
+	assert.Error(v.VerifyMessages(bh, msgs[:2]))
+	assert.Error(v.VerifyMessages(bh, [][]byte{msgs[0], msgs[2], msgs[1]}))
+	assert.EqualValues(3, v.State().NextMessageSN)
+
+	// replay
+	_, err = v.Verify(hb, pb)
+	assert.Error(err)
+
+	// validator change, signed by old validators
+	oldWallets := n.wallets
+	pc = n.newValidators(3)
+	hb, pb = n.next([][]byte{[]byte("m3")}, pc, oldWallets)
+	_, err = v.Verify(hb, pb)
+	assert.NoError(err)
+
+	// resume with the saved state
+	js, err := json.Marshal(v.State())
+	assert.NoError(err)
+	var s State
+	assert.NoError(json.Unmarshal(js, &s))
+	v2, err := NewVerifier(&s)
+	assert.NoError(err)
+
+	hb, pb = n.next([][]byte{[]byte("m4")}, nil, n.wallets)
+	bh, err = v2.Verify(hb, pb)
+	assert.NoError(err)
+	assert.EqualValues(4, bh.FirstMessageSN())
+	assert.EqualValues(5, v2.State().NextMessageSN)
+}
+
+func TestVerifier_Fail(t *testing.T) {
+	assert := assert.New(t)
+	n := newTestNetwork(t)
+	v, err := NewVerifier(newTestState())
+	assert.NoError(err)
+
+	pc := n.newValidators(4)
+	hb, pb := n.next(nil, pc, nil)
+	_, err = v.Verify(hb, pb)
+	assert.NoError(err)
+
+	// not enough signatures
+	hb, pb = n.next(nil, nil, n.wallets)
+	bh, err := btp.NewBTPBlockHeaderFromBytes(hb)
+	assert.NoError(err)
+	_, err = v.Verify(hb, n.proofFor(bh, n.wallets[:2]))
+	assert.Error(err)
+
+	// signed by unknown validators
+	n2 := newTestNetwork(t)
+	n2.pc = n2.newValidators(4)
+	_, err = v.Verify(hb, n2.proofFor(bh, n2.wallets))
+	assert.Error(err)
+
+	_, err = v.Verify(hb, pb)
+	assert.NoError(err)
+
+	// missing BTP block
+	n.next(nil, nil, n.wallets)
+	hb, pb = n.next(nil, nil, n.wallets)
+	_, err = v.Verify(hb, pb)
+	assert.Error(err)
+
+	// anchor must be the first BTP block
+	v2, err := NewVerifier(newTestState())
+	assert.NoError(err)
+	_, err = v2.Verify(hb, pb)
+	assert.Error(err)
+
+	_, err = NewVerifier(&State{NetworkTypeUID: "unknown"})
+	assert.Error(err)
+}
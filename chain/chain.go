@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/btp/ntm"
 	"github.com/icon-project/goloop/chain/base"
 	"github.com/icon-project/goloop/chain/gs"
 	"github.com/icon-project/goloop/common/db"
@@ -82,7 +83,8 @@ type simpleTask interface {
 }
 
 type singleChain struct {
-	wallet module.Wallet
+	wallet    module.Wallet
+	blsWallet module.BaseWallet
 
 	dbLock   sync.RWMutex
 	database db.Database
@@ -121,6 +123,8 @@ const (
 	DefaultContractDir = "contract"
 	DefaultCacheDir    = "cache"
 	DefaultTmpDBDir    = "tmp"
+
+	chainGenesisZipFileName = "genesis.zip"
 )
 
 func (c *singleChain) Database() db.Database {
@@ -266,6 +270,27 @@ func (c *singleChain) NephewsLimit() int {
 	return ConfigDefaultNephewLimit
 }
 
+func (c *singleChain) PeerBanDuration() time.Duration {
+	if c.cfg.BanDuration > 0 {
+		return time.Duration(c.cfg.BanDuration) * time.Millisecond
+	}
+	return ConfigDefaultPeerBanDuration
+}
+
+func (c *singleChain) PeerBanThreshold() int {
+	if c.cfg.BanThreshold > 0 {
+		return c.cfg.BanThreshold
+	}
+	return ConfigDefaultPeerBanThreshold
+}
+
+func (c *singleChain) PeerPenaltyDecayTime() time.Duration {
+	if c.cfg.PenaltyDecay > 0 {
+		return time.Duration(c.cfg.PenaltyDecay) * time.Millisecond
+	}
+	return ConfigDefaultPeerPenaltyDecay
+}
+
 func (c *singleChain) ValidateTxOnSend() bool {
 	return c.cfg.ValidateTxOnSend
 }
@@ -475,6 +500,21 @@ func (c *singleChain) _init() error {
 		return err
 	}
 
+	if c.cfg.BLSKey != "" {
+		if c.cfg.BLSKeySecret == "" {
+			return errors.IllegalArgumentError.New("NoSecretForBLSKey")
+		}
+		pw, err := os.ReadFile(c.cfg.ResolveAbsolute(c.cfg.BLSKeySecret))
+		if err != nil {
+			return errors.Wrap(err, "fail to read BLS key secret")
+		}
+		w, err := ntm.LoadBLSWallet(c.cfg.ResolveAbsolute(c.cfg.BLSKey), pw)
+		if err != nil {
+			return errors.Wrap(err, "fail to load BLS key")
+		}
+		c.blsWallet = w
+	}
+
 	c.vld = c.plt.CommitVoteSetDecoder()
 	if c.vld == nil {
 		c.vld = consensus.NewCommitVoteSetFromBytes
@@ -487,6 +527,10 @@ func (c *singleChain) _init() error {
 func (c *singleChain) prepareManagers() error {
 	pr := network.PeerRoleFlag(c.cfg.Role)
 	c.nm = network.NewManager(c, c.nt, c.cfg.SeedAddr, pr.ToRoles()...)
+	if err := c.nm.SetBandwidthLimits(c.cfg.BandwidthLimits); err != nil {
+		c.logger.Warnf("fail to set bandwidth limits=%q err=%+v",
+			c.cfg.BandwidthLimits, err)
+	}
 
 	chainDir := c.cfg.AbsBaseDir()
 	ContractDir := path.Join(chainDir, DefaultContractDir)
@@ -693,7 +737,6 @@ func (c *singleChain) Verify() error {
 func (c *singleChain) Reset(gs string, height int64, blockHash []byte) error {
 	if len(gs) == 0 {
 		chainDir := c.cfg.AbsBaseDir()
-		const chainGenesisZipFileName = "genesis.zip"
 		gs = path.Join(chainDir, chainGenesisZipFileName)
 	}
 	task := newTaskReset(c, gs, height, blockHash)
@@ -725,6 +768,8 @@ func (c *singleChain) WalletFor(dsa string) module.BaseWallet {
 	switch dsa {
 	case "ecdsa/secp256k1":
 		return c.wallet
+	case "bls/bls12-381":
+		return c.blsWallet
 	}
 	return nil
 }
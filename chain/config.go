@@ -20,6 +20,9 @@ const (
 	ConfigDefaultTxTimeout        = 5000 * time.Millisecond
 	ConfigDefaultChildrenLimit    = 10
 	ConfigDefaultNephewLimit      = 10
+	ConfigDefaultPeerBanDuration  = time.Hour
+	ConfigDefaultPeerBanThreshold = 100
+	ConfigDefaultPeerPenaltyDecay = time.Second
 	ConfigDefaultAPIInfoCacheSize  = 2048
 )
 
@@ -49,6 +52,8 @@ type Config struct {
 	ChildrenLimit    *int   `json:"children_limit,omitempty"`
 	NephewsLimit     *int   `json:"nephews_limit,omitempty"`
 	ValidateTxOnSend bool   `json:"validate_tx_on_send,omitempty"`
+	BLSKey           string `json:"bls_key,omitempty"`
+	BLSKeySecret     string `json:"bls_key_secret,omitempty"`
 
 	// runtime
 	Channel        string `json:"channel"`
@@ -57,6 +62,11 @@ type Config struct {
 	DefWaitTimeout int64  `json:"waitTimeout"`
 	MaxWaitTimeout int64  `json:"maxTimeout"`
 	TxTimeout      int64  `json:"txTimeout"`
+	BanDuration    int64  `json:"banDuration,omitempty"`
+	BanThreshold   int    `json:"banThreshold,omitempty"`
+	PenaltyDecay   int64  `json:"penaltyDecay,omitempty"`
+
+	BandwidthLimits string `json:"bandwidthLimits,omitempty"`
 
 	GenesisStorage module.GenesisStorage `json:"-"`
 	Genesis        json.RawMessage       `json:"genesis"`
@@ -0,0 +1,504 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package snapshot handles snapshot files of the chain. A snapshot file is
+// a zip file with the manifest, blocks for the height (and two previous
+// blocks for validators and voters), votes for the block, and entries of
+// the world state for the blocks.
+//
+// Entries of the world state are stored in content-addressed way, and they
+// are written in batches of limited size. So they are verified by hashes
+// while they are imported with merkle.Builder. Blocks are verified by
+// comparing their IDs with the trusted block hash.
+//
+// Both of the writer and the reader keep the index of entries in
+// a temporary database instead of the memory, so memory usage doesn't grow
+// with the size of the world state.
+package snapshot
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	Version          = 2
+	ManifestFileName = "snapshot.json"
+	VotesFileName    = "votes"
+
+	blocksDir = "blocks"
+	dataDir   = "data"
+)
+
+// size of entries for a batch file
+var configBatchSize = 4 * 1024 * 1024
+
+type Manifest struct {
+	Version int             `json:"version"`
+	NID     common.HexInt32 `json:"nid"`
+	CID     common.HexInt32 `json:"cid"`
+	Height  common.HexInt64 `json:"height"`
+	Block   common.HexBytes `json:"block"`
+	Codec   string          `json:"codec"`
+	Items   common.HexInt64 `json:"items"`
+	Batches common.HexInt64 `json:"batches"`
+}
+
+func blockFileName(height int64) string {
+	return path.Join(blocksDir, strconv.FormatInt(height, 10))
+}
+
+func batchFileName(seq int64) string {
+	return path.Join(dataDir, strconv.FormatInt(seq, 10))
+}
+
+type entry struct {
+	ID    []byte
+	Key   []byte
+	Value []byte
+}
+
+// tempDatabase is the database in the temporary directory, which is
+// removed on release.
+type tempDatabase struct {
+	db.Database
+	dir string
+}
+
+func openTempDatabase(dir, pattern string) (*tempDatabase, error) {
+	tmpDir, err := os.MkdirTemp(dir, pattern)
+	if err != nil {
+		return nil, err
+	}
+	dbase, err := db.Open(tmpDir, string(db.GoLevelDBBackend), "index")
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, err
+	}
+	return &tempDatabase{dbase, tmpDir}, nil
+}
+
+func (d *tempDatabase) release() {
+	if err := d.Database.Close(); err != nil {
+		log.Warnf("fail to close temporal database err=%+v", err)
+	}
+	if err := os.RemoveAll(d.dir); err != nil {
+		log.Warnf("fail to remove temporal database dir=%s err=%+v", d.dir, err)
+	}
+}
+
+type Writer struct {
+	zw    *zip.Writer
+	src   db.Database
+	index *tempDatabase
+
+	batch     []entry
+	batchSize int
+	batches   int64
+	items     int64
+}
+
+func (w *Writer) create(name string, value []byte) error {
+	f, err := w.zw.CreateHeader(&zip.FileHeader{
+		Name:   name,
+		Method: zip.Deflate,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(value)
+	return err
+}
+
+// WriteBlock writes the block.
+func (w *Writer) WriteBlock(blk module.BlockData) error {
+	buf := bytes.NewBuffer(nil)
+	if err := blk.Marshal(buf); err != nil {
+		return err
+	}
+	return w.create(blockFileName(blk.Height()), buf.Bytes())
+}
+
+// WriteVotes writes votes for the block of the snapshot.
+func (w *Writer) WriteVotes(votes module.CommitVoteSet) error {
+	return w.create(VotesFileName, votes.Bytes())
+}
+
+// Items returns the number of entries written through the database. It's
+// safe to call it while it's writing.
+func (w *Writer) Items() int64 {
+	return atomic.LoadInt64(&w.items)
+}
+
+// Database returns the database for exporting entries of the world state.
+// It can be used as a destination of module.ServiceManager.ExportResult.
+// It returns values of written entries from the source database.
+func (w *Writer) Database() db.Database {
+	return &writerDatabase{w}
+}
+
+func (w *Writer) add(id db.BucketID, key, value []byte) error {
+	w.batch = append(w.batch, entry{[]byte(id), key, value})
+	w.batchSize += len(id) + len(key) + len(value)
+	if w.batchSize >= configBatchSize {
+		return w.flushBatch()
+	}
+	return nil
+}
+
+func (w *Writer) flushBatch() error {
+	if len(w.batch) == 0 {
+		return nil
+	}
+	bs, err := codec.BC.MarshalToBytes(w.batch)
+	if err != nil {
+		return err
+	}
+	if err := w.create(batchFileName(w.batches), bs); err != nil {
+		return err
+	}
+	w.batches += 1
+	w.batch = w.batch[:0]
+	w.batchSize = 0
+	return nil
+}
+
+// Close writes the manifest and closes the writer.
+func (w *Writer) Close(m *Manifest) error {
+	defer w.Release()
+	if err := w.flushBatch(); err != nil {
+		return err
+	}
+	m.Version = Version
+	m.Items.Value = w.Items()
+	m.Batches.Value = w.batches
+	bs, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := w.create(ManifestFileName, bs); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
+
+// Release removes the temporary index of the writer. It's called by Close,
+// and it should be called if the writer isn't closed because of failure.
+func (w *Writer) Release() {
+	if w.index != nil {
+		w.index.release()
+		w.index = nil
+	}
+}
+
+// NewWriter returns the writer. The temporary index of written entries is
+// made under tmpDir.
+func NewWriter(w io.Writer, src db.Database, tmpDir string) (*Writer, error) {
+	index, err := openTempDatabase(tmpDir, "snapshot-index")
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{
+		zw:    zip.NewWriter(w),
+		src:   src,
+		index: index,
+	}, nil
+}
+
+type writerDatabase struct {
+	w *Writer
+}
+
+func (d *writerDatabase) GetBucket(id db.BucketID) (db.Bucket, error) {
+	bk, err := d.w.index.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	return &writerBucket{w: d.w, id: id, index: bk}, nil
+}
+
+func (d *writerDatabase) Close() error {
+	return nil
+}
+
+type writerBucket struct {
+	w     *Writer
+	id    db.BucketID
+	index db.Bucket
+}
+
+func (b *writerBucket) Get(key []byte) ([]byte, error) {
+	if ok, err := b.Has(key); err != nil || !ok {
+		return nil, err
+	}
+	bk, err := b.w.src.GetBucket(b.id)
+	if err != nil {
+		return nil, err
+	}
+	return bk.Get(key)
+}
+
+func (b *writerBucket) Has(key []byte) (bool, error) {
+	return b.index.Has(key)
+}
+
+func (b *writerBucket) Set(key []byte, value []byte) error {
+	if ok, err := b.Has(key); err != nil || ok {
+		return err
+	}
+	if err := b.w.add(b.id, key, value); err != nil {
+		return err
+	}
+	if err := b.index.Set(key, []byte{}); err != nil {
+		return err
+	}
+	atomic.AddInt64(&b.w.items, 1)
+	return nil
+}
+
+func (b *writerBucket) Delete(key []byte) error {
+	return errors.UnsupportedError.New("SnapshotWriterDeleteUnsupported")
+}
+
+type Reader struct {
+	closer   io.Closer
+	manifest Manifest
+	files    map[string]*zip.File
+	data     *tempDatabase
+}
+
+func readAll(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func (r *Reader) get(name string) ([]byte, error) {
+	if f, ok := r.files[name]; ok {
+		return readAll(f)
+	}
+	return nil, nil
+}
+
+func (r *Reader) Manifest() *Manifest {
+	return &r.manifest
+}
+
+// Block returns the bytes of the block at the height.
+func (r *Reader) Block(height int64) ([]byte, error) {
+	bs, err := r.get(blockFileName(height))
+	if err != nil {
+		return nil, err
+	}
+	if bs == nil {
+		return nil, errors.NotFoundError.Errorf("NoBlockInSnapshot(height=%d)", height)
+	}
+	return bs, nil
+}
+
+// Votes returns the bytes of votes for the block of the snapshot.
+func (r *Reader) Votes() ([]byte, error) {
+	bs, err := r.get(VotesFileName)
+	if err != nil {
+		return nil, err
+	}
+	if bs == nil {
+		return nil, errors.NotFoundError.New("NoVotesInSnapshot")
+	}
+	return bs, nil
+}
+
+func (r *Reader) loadBatch(data db.Database, seq int64) (int, error) {
+	bs, err := r.get(batchFileName(seq))
+	if err != nil {
+		return 0, err
+	}
+	if bs == nil {
+		return 0, errors.NotFoundError.Errorf("NoBatchInSnapshot(seq=%d)", seq)
+	}
+	var batch []entry
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &batch); err != nil {
+		return 0, errors.InvalidStateError.Wrapf(err, "InvalidBatchInSnapshot(seq=%d)", seq)
+	}
+	for _, e := range batch {
+		bk, err := data.GetBucket(db.BucketID(e.ID))
+		if err != nil {
+			return 0, err
+		}
+		if err := bk.Set(e.Key, e.Value); err != nil {
+			return 0, err
+		}
+	}
+	return len(batch), nil
+}
+
+// LoadData loads entries of the world state into the temporary database
+// under tmpDir, so that they can be found through Database(). It returns
+// errors.ErrInterrupted if cancel is signaled while it's loading.
+func (r *Reader) LoadData(tmpDir string, cancel <-chan struct{}) (rerr error) {
+	if r.data != nil {
+		return nil
+	}
+	data, err := openTempDatabase(tmpDir, "snapshot-data")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rerr != nil {
+			data.release()
+		}
+	}()
+	var items int64
+	for seq := int64(0); seq < r.manifest.Batches.Value; seq++ {
+		select {
+		case <-cancel:
+			return errors.ErrInterrupted
+		default:
+		}
+		n, err := r.loadBatch(data, seq)
+		if err != nil {
+			return err
+		}
+		items += int64(n)
+	}
+	if items != r.manifest.Items.Value {
+		return errors.InvalidStateError.Errorf(
+			"InvalidItemsInSnapshot(items=%d,exp=%d)", items, r.manifest.Items.Value)
+	}
+	r.data = data
+	return nil
+}
+
+// Database returns the read-only database with entries of the world state.
+// It can be used as a source of module.ServiceManager.ImportResult after
+// LoadData.
+func (r *Reader) Database() db.Database {
+	return &readerDatabase{r}
+}
+
+func (r *Reader) Close() error {
+	if r.data != nil {
+		r.data.release()
+		r.data = nil
+	}
+	if r.closer != nil {
+		return r.closer.Close()
+	}
+	return nil
+}
+
+func NewReader(ra io.ReaderAt, size int64) (*Reader, error) {
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidSnapshotFile")
+	}
+	r := &Reader{
+		files: make(map[string]*zip.File, len(zr.File)),
+	}
+	for _, f := range zr.File {
+		r.files[f.Name] = f
+	}
+	bs, err := r.get(ManifestFileName)
+	if err != nil {
+		return nil, err
+	}
+	if bs == nil {
+		return nil, errors.IllegalArgumentError.New("NoManifest")
+	}
+	if err := json.Unmarshal(bs, &r.manifest); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidManifest")
+	}
+	if r.manifest.Version != Version {
+		return nil, errors.UnsupportedError.Errorf(
+			"UnsupportedSnapshotVersion(version=%d)", r.manifest.Version)
+	}
+	return r, nil
+}
+
+// Open opens the snapshot file. Returned Reader should be closed after use.
+func Open(file string) (*Reader, error) {
+	fd, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	st, err := fd.Stat()
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+	r, err := NewReader(fd, st.Size())
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+	r.closer = fd
+	return r, nil
+}
+
+type readerDatabase struct {
+	r *Reader
+}
+
+func (d *readerDatabase) GetBucket(id db.BucketID) (db.Bucket, error) {
+	if d.r.data == nil {
+		return nil, errors.InvalidStateError.New("SnapshotDataNotLoaded")
+	}
+	bk, err := d.r.data.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	return &readerBucket{bk}, nil
+}
+
+func (d *readerDatabase) Close() error {
+	return nil
+}
+
+type readerBucket struct {
+	data db.Bucket
+}
+
+func (b *readerBucket) Get(key []byte) ([]byte, error) {
+	return b.data.Get(key)
+}
+
+func (b *readerBucket) Has(key []byte) (bool, error) {
+	return b.data.Has(key)
+}
+
+func (b *readerBucket) Set(key []byte, value []byte) error {
+	return errors.UnsupportedError.New("SnapshotIsReadOnly")
+}
+
+func (b *readerBucket) Delete(key []byte) error {
+	return errors.UnsupportedError.New("SnapshotIsReadOnly")
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+)
+
+func newTestTrie(t *testing.T, dbase db.Database, count int) []byte {
+	m := trie_manager.NewMutable(dbase, nil)
+	for i := 0; i < count; i++ {
+		_, err := m.Set([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i)))
+		assert.NoError(t, err)
+	}
+	ss := m.GetSnapshot()
+	assert.NoError(t, ss.Flush())
+	return ss.Hash()
+}
+
+func copyTrie(src, dst db.Database, hash []byte) error {
+	ctx := merkle.NewCopyContext(src, dst)
+	trie_manager.NewImmutable(ctx.Builder().Database(), hash).Resolve(ctx.Builder())
+	return ctx.Run()
+}
+
+func TestSnapshot_Basics(t *testing.T) {
+	assert := assert.New(t)
+
+	src := db.NewMapDB()
+	hash := newTestTrie(t, src, 100)
+
+	buf := bytes.NewBuffer(nil)
+	w, err := NewWriter(buf, src, t.TempDir())
+	assert.NoError(err)
+	assert.NoError(copyTrie(src, w.Database(), hash))
+	items := w.Items()
+	assert.True(items > 1)
+
+	// entries already written are not written again
+	assert.NoError(copyTrie(src, w.Database(), hash))
+	assert.Equal(items, w.Items())
+	assert.NoError(w.Close(&Manifest{
+		Height: common.HexInt64{Value: 10},
+		Block:  []byte{0x01},
+	}))
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(err)
+	assert.Error(copyTrie(r.Database(), db.NewMapDB(), hash))
+	assert.NoError(r.LoadData(t.TempDir(), nil))
+	m := r.Manifest()
+	assert.Equal(Version, m.Version)
+	assert.EqualValues(10, m.Height.Value)
+	assert.Equal(items, m.Items.Value)
+	_, err = r.Block(10)
+	assert.Error(err)
+	_, err = r.Votes()
+	assert.Error(err)
+
+	dst := db.NewMapDB()
+	assert.NoError(copyTrie(r.Database(), dst, hash))
+	tr := trie_manager.NewImmutable(dst, hash)
+	for i := 0; i < 100; i++ {
+		v, err := tr.Get([]byte(fmt.Sprintf("key%d", i)))
+		assert.NoError(err)
+		assert.Equal([]byte(fmt.Sprintf("value%d", i)), v)
+	}
+
+	// entries of other state are not in the snapshot
+	hash2 := newTestTrie(t, src, 101)
+	assert.Error(copyTrie(r.Database(), db.NewMapDB(), hash2))
+	assert.NoError(r.Close())
+}
+
+func TestSnapshot_InvalidFile(t *testing.T) {
+	_, err := NewReader(bytes.NewReader([]byte("invalid")), 7)
+	assert.Error(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	w, err := NewWriter(buf, db.NewMapDB(), t.TempDir())
+	assert.NoError(t, err)
+	w.Release()
+	assert.NoError(t, w.zw.Close())
+	_, err = NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Error(t, err)
+}
+
+func TestSnapshot_Batches(t *testing.T) {
+	assert := assert.New(t)
+
+	old := configBatchSize
+	configBatchSize = 512
+	defer func() { configBatchSize = old }()
+
+	src := db.NewMapDB()
+	hash := newTestTrie(t, src, 200)
+
+	buf := bytes.NewBuffer(nil)
+	tmpDir := t.TempDir()
+	w, err := NewWriter(buf, src, tmpDir)
+	assert.NoError(err)
+	assert.NoError(copyTrie(src, w.Database(), hash))
+	assert.NoError(w.Close(&Manifest{
+		Height: common.HexInt64{Value: 10},
+		Block:  []byte{0x01},
+	}))
+
+	// temporary index is removed
+	files, err := os.ReadDir(tmpDir)
+	assert.NoError(err)
+	assert.Empty(files)
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(err)
+	m := r.Manifest()
+	assert.True(m.Batches.Value > 1)
+	assert.True(m.Items.Value > m.Batches.Value)
+
+	// loading is interrupted by cancel
+	cancel := make(chan struct{}, 1)
+	cancel <- struct{}{}
+	assert.ErrorIs(r.LoadData(tmpDir, cancel), errors.ErrInterrupted)
+
+	assert.NoError(r.LoadData(tmpDir, nil))
+	assert.NoError(copyTrie(r.Database(), db.NewMapDB(), hash))
+	assert.NoError(r.Close())
+
+	files, err = os.ReadDir(tmpDir)
+	assert.NoError(err)
+	assert.Empty(files)
+}
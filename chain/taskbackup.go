@@ -40,6 +40,7 @@ type BackupInfo struct {
 	Channel string          `json:"channel"`
 	Height  int64           `json:"height"`
 	Codec   string          `json:"codec"`
+	DBType  string          `json:"dbType,omitempty"`
 }
 
 var backupStates = map[State]string{
@@ -116,6 +117,7 @@ func (t *taskBackup) Start() (ret error) {
 		Channel: t.chain.Channel(),
 		Height:  t.chain.lastBlockHeight(),
 		Codec:   codec.BC.Name(),
+		DBType:  t.chain.cfg.DBType,
 	}); err != nil {
 		return err
 	}
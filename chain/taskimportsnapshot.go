@@ -0,0 +1,321 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/chain/snapshot"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/network"
+	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const (
+	ImportSnapshotTask = "import_snapshot"
+)
+
+type importSnapshotParams struct {
+	File      string          `json:"file"`
+	BlockHash common.HexBytes `json:"blockHash"`
+}
+
+var importSnapshotStates = map[State]string{
+	Starting: "import_snapshot starting",
+	Stopping: "import_snapshot stopping",
+	Failed:   "import_snapshot failed",
+	Finished: "import_snapshot done",
+}
+
+type taskImportSnapshot struct {
+	chain     *singleChain
+	result    resultStore
+	file      string
+	blockHash []byte
+	reader    *snapshot.Reader
+	cancelCh  chan struct{}
+
+	reportHeight     int64
+	reportResolved   uint64
+	reportUnresolved uint64
+}
+
+func (t *taskImportSnapshot) String() string {
+	return fmt.Sprintf("ImportSnapshot(file=%s,blockHash=%#x)",
+		path.Base(t.file), t.blockHash)
+}
+
+func (t *taskImportSnapshot) DetailOf(s State) string {
+	switch s {
+	case Started:
+		return fmt.Sprintf("import_snapshot height=%d resolved=%d unresolved=%d",
+			atomic.LoadInt64(&t.reportHeight),
+			atomic.LoadUint64(&t.reportResolved),
+			atomic.LoadUint64(&t.reportUnresolved))
+	default:
+		if st, ok := importSnapshotStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+func (t *taskImportSnapshot) _reportProgress(h int64, resolved, unresolved int) error {
+	atomic.StoreInt64(&t.reportHeight, h)
+	atomic.StoreUint64(&t.reportResolved, uint64(resolved))
+	atomic.StoreUint64(&t.reportUnresolved, uint64(unresolved))
+	return nil
+}
+
+func (t *taskImportSnapshot) Start() error {
+	r, err := snapshot.Open(t.file)
+	if err != nil {
+		return err
+	}
+	m := r.Manifest()
+	if int(m.NID.Value) != t.chain.NID() || int(m.CID.Value) != t.chain.CID() {
+		r.Close()
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidSnapshotChain(nid=%#x,cid=%#x)", m.NID.Value, m.CID.Value)
+	}
+	if m.Codec != codec.BC.Name() {
+		r.Close()
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidSnapshotCodec(codec=%s)", m.Codec)
+	}
+	if !bytes.Equal(m.Block, t.blockHash) {
+		r.Close()
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidSnapshotBlock(exp=%#x,real=%#x)", t.blockHash, m.Block.Bytes())
+	}
+	if m.Height.Value < 2 {
+		r.Close()
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidSnapshotHeight(height=%d)", m.Height.Value)
+	}
+	t.reader = r
+	go func() {
+		defer t.reader.Close()
+		err := t._import()
+		t.result.SetValue(err)
+	}()
+	return nil
+}
+
+func (t *taskImportSnapshot) _readBlock(bdf module.BlockDataFactory, h int64, id []byte) (module.BlockData, error) {
+	bs, err := t.reader.Block(h)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := bdf.NewBlockDataFromReader(bytes.NewReader(bs))
+	if err != nil {
+		return nil, err
+	}
+	if blk.Height() != h || !bytes.Equal(blk.ID(), id) {
+		return nil, errors.InvalidStateError.Errorf(
+			"InvalidBlockInSnapshot(height=%d,id=%#x,exp=%#x)", h, blk.ID(), id)
+	}
+	return blk, nil
+}
+
+// _importBlocks imports the block of the snapshot and its previous blocks
+// with their states. States are imported to the database before
+// block.UnsafeFinalize, then it doesn't need to sync them from peers.
+func (t *taskImportSnapshot) _importBlocks() (module.BlockData, module.CommitVoteSet, error) {
+	c := t.chain
+	defer c.releaseManagers()
+
+	pr := network.PeerRoleFlag(c.cfg.Role)
+	c.nm = network.NewManager(c, c.nt, c.cfg.SeedAddr, pr.ToRoles()...)
+
+	contractDir := path.Join(c.cfg.AbsBaseDir(), DefaultContractDir)
+	var err error
+	c.sm, err = service.NewManager(c, c.nm, c.pm, c.plt, contractDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	bdf, err := block.NewBlockDataFactory(c, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.sm.Start()
+
+	height := t.reader.Manifest().Height.Value
+	blk, err := t._readBlock(bdf, height, t.blockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	pBlk, err := t._readBlock(bdf, height-1, blk.PrevID())
+	if err != nil {
+		return nil, nil, err
+	}
+	ppBlk, err := t._readBlock(bdf, height-2, pBlk.PrevID())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.logger.Infof("Load entries of states from snapshot")
+	if err := t.reader.LoadData(c.cfg.AbsBaseDir(), t.cancelCh); err != nil {
+		return nil, nil, err
+	}
+	for _, b := range []module.BlockData{ppBlk, pBlk, blk} {
+		c.logger.Infof("Import state height=%d result=%#x", b.Height(), b.Result())
+		_ = t._reportProgress(b.Height(), 0, 0)
+		if err := c.sm.ImportResult(b.Result(), b.NextValidatorsHash(), t.reader.Database()); err != nil {
+			return nil, nil, err
+		}
+		if err := block.UnsafeFinalize(c.sm, c, b, t.cancelCh, t._reportProgress); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	votesBytes, err := t.reader.Votes()
+	if err != nil {
+		return nil, nil, err
+	}
+	votes := c.CommitVoteSetDecoder()(votesBytes)
+	if votes == nil {
+		return nil, nil, errors.InvalidStateError.New("InvalidVotesInSnapshot")
+	}
+	vl, err := state.ValidatorSnapshotFromHash(c.Database(), pBlk.NextValidatorsHash())
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err = votes.VerifyBlock(blk, vl); err != nil {
+		return nil, nil, err
+	}
+
+	if err = block.SetLastHeight(c.Database(), nil, height); err != nil {
+		return nil, nil, err
+	}
+	return blk, votes, nil
+}
+
+func (t *taskImportSnapshot) _import() (ret error) {
+	c := t.chain
+	chainDir := c.cfg.AbsBaseDir()
+
+	var rb Revertible
+	defer func() {
+		rb.RevertOrCommit(ret != nil)
+	}()
+
+	// reset database
+	c.releaseDatabase()
+	rb.Append(func(revert bool) {
+		if revert {
+			c.ensureDatabase()
+		}
+	})
+	dbDir := path.Join(chainDir, DefaultDBDir)
+	if ret = rb.Delete(dbDir); ret != nil {
+		return
+	}
+	c.ensureDatabase()
+	rb.Append(func(revert bool) {
+		if revert {
+			c.releaseDatabase()
+			log.Must(os.RemoveAll(dbDir))
+		}
+	})
+
+	// remove other directories
+	for _, name := range []string{DefaultContractDir, DefaultWALDir, DefaultCacheDir} {
+		dir := path.Join(chainDir, name)
+		if ret = rb.Delete(dir); ret != nil {
+			return
+		}
+		rb.Append(func(revert bool) {
+			if revert {
+				log.Must(os.RemoveAll(dir))
+			}
+		})
+	}
+
+	blk, votes, err := t._importBlocks()
+	if err != nil {
+		return err
+	}
+
+	// create pruned genesis
+	gsfile := path.Join(chainDir, chainGenesisZipFileName)
+	if err := rb.Delete(gsfile); err != nil {
+		return err
+	}
+	if err := exportPrunedGenesis(c, blk, votes, gsfile); err != nil {
+		return err
+	}
+	rb.Append(func(revert bool) {
+		if revert {
+			_ = os.Remove(gsfile)
+		}
+	})
+
+	// reload new genesis
+	g, err := loadGenesisStorage(gsfile)
+	if err != nil {
+		return err
+	}
+	c.cfg.GenesisStorage = g
+	c.cfg.Genesis = g.Genesis()
+	return nil
+}
+
+func (t *taskImportSnapshot) Stop() {
+	select {
+	case t.cancelCh <- struct{}{}:
+	default:
+	}
+}
+
+func (t *taskImportSnapshot) Wait() error {
+	return t.result.Wait()
+}
+
+func taskImportSnapshotFactory(c *singleChain, params json.RawMessage) (chainTask, error) {
+	var p importSnapshotParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	if len(p.File) == 0 || len(p.BlockHash) != crypto.HashLen {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidParameter(file=%q,blockHash=%#x)", p.File, p.BlockHash.Bytes())
+	}
+	return &taskImportSnapshot{
+		chain:     c,
+		file:      c.cfg.ResolveAbsolute(p.File),
+		blockHash: p.BlockHash,
+		cancelCh:  make(chan struct{}, 1),
+	}, nil
+}
+
+func init() {
+	registerTaskFactory(ImportSnapshotTask, taskImportSnapshotFactory)
+}
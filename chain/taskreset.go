@@ -253,11 +253,17 @@ func (t *taskReset) _prepareBlocks(height int64, blockHash []byte) (module.Block
 	return blk, votes, nil
 }
 
-func (t *taskReset) _exportGenesis(blk module.BlockData, votes module.CommitVoteSet, gsfile string) (rerr error) {
-	if err := t.chain.prepareManagers(); err != nil {
+func (t *taskReset) _exportGenesis(blk module.BlockData, votes module.CommitVoteSet, gsfile string) error {
+	return exportPrunedGenesis(t.chain, blk, votes, gsfile)
+}
+
+// exportPrunedGenesis exports the pruned genesis storage for the block with
+// the votes to gsfile.
+func exportPrunedGenesis(c *singleChain, blk module.BlockData, votes module.CommitVoteSet, gsfile string) (rerr error) {
+	if err := c.prepareManagers(); err != nil {
 		return err
 	}
-	defer t.chain.releaseManagers()
+	defer c.releaseManagers()
 	fd, err := os.OpenFile(gsfile, os.O_CREATE|os.O_WRONLY|os.O_EXCL|os.O_TRUNC, 0700)
 	if err != nil {
 		return err
@@ -270,7 +276,7 @@ func (t *taskReset) _exportGenesis(blk module.BlockData, votes module.CommitVote
 			_ = os.Remove(gsfile)
 		}
 	}()
-	if err := t.chain.bm.ExportGenesis(blk, votes, gsw); err != nil {
+	if err := c.bm.ExportGenesis(blk, votes, gsw); err != nil {
 		return errors.Wrap(err, "fail on exporting genesis storage")
 	}
 	return nil
@@ -0,0 +1,210 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/chain/snapshot"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/merkle"
+)
+
+const (
+	SnapshotTask = "snapshot"
+)
+
+type snapshotParams struct {
+	Height int64  `json:"height"`
+	File   string `json:"file"`
+}
+
+var snapshotStates = map[State]string{
+	Starting: "snapshot starting",
+	Stopping: "snapshot stopping",
+	Failed:   "snapshot failed",
+	Finished: "snapshot done",
+}
+
+type taskSnapshot struct {
+	chain  *singleChain
+	result resultStore
+	height int64
+	file   string
+
+	current int64
+	stop    int32
+	sw      atomic.Value
+}
+
+func (t *taskSnapshot) String() string {
+	return fmt.Sprintf("Snapshot(height=%d,file=%s)", t.height, path.Base(t.file))
+}
+
+func (t *taskSnapshot) DetailOf(s State) string {
+	switch s {
+	case Started:
+		var items int64
+		if sw, ok := t.sw.Load().(*snapshot.Writer); ok {
+			items = sw.Items()
+		}
+		return fmt.Sprintf("snapshot height=%d items=%d",
+			atomic.LoadInt64(&t.current), items)
+	default:
+		if st, ok := snapshotStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+func (t *taskSnapshot) Start() error {
+	if err := t.chain.prepareManagers(); err != nil {
+		return err
+	}
+	blk, err := t.chain.bm.GetLastBlock()
+	if err != nil {
+		t.chain.releaseManagers()
+		return err
+	}
+	if t.height >= blk.Height() {
+		t.chain.releaseManagers()
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidHeight(height=%d,last=%d)", t.height, blk.Height())
+	}
+	go func() {
+		err := t._export()
+		t.result.SetValue(err)
+	}()
+	return nil
+}
+
+func (t *taskSnapshot) _interrupted() bool {
+	return atomic.LoadInt32(&t.stop) != 0
+}
+
+func (t *taskSnapshot) _export() (rerr error) {
+	c := t.chain
+	defer c.releaseManagers()
+
+	tmp, err := os.CreateTemp(path.Dir(t.file), path.Base(t.file)+TempSuffix)
+	if err != nil {
+		return errors.Wrap(err, "fail to make temporal file")
+	}
+	defer func() {
+		if rerr != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	sw, err := snapshot.NewWriter(tmp, c.Database(), path.Dir(t.file))
+	if err != nil {
+		return err
+	}
+	defer sw.Release()
+	t.sw.Store(sw)
+
+	// stop is checked while it's exporting entries of the state
+	ctx := merkle.NewCopyContext(c.Database(), sw.Database())
+	ctx.SetProgressCallback(func(height int64, resolved int, unresolved int) error {
+		if t._interrupted() {
+			return errors.ErrInterrupted
+		}
+		return nil
+	})
+
+	// the block and previous blocks for validators and voters of the block
+	for h := t.height - 2; h <= t.height; h++ {
+		if t._interrupted() {
+			return errors.ErrInterrupted
+		}
+		atomic.StoreInt64(&t.current, h)
+		blk, err := c.bm.GetBlockByHeight(h)
+		if err != nil {
+			return err
+		}
+		if err := sw.WriteBlock(blk); err != nil {
+			return err
+		}
+		c.logger.Infof("Export state height=%d result=%#x", h, blk.Result())
+		ctx.SetHeight(h)
+		if err := c.sm.ExportResult(blk.Result(), blk.NextValidatorsHash(), ctx.TargetDB()); err != nil {
+			return err
+		}
+	}
+
+	blk, err := c.bm.GetBlockByHeight(t.height)
+	if err != nil {
+		return err
+	}
+	nblk, err := c.bm.GetBlockByHeight(t.height + 1)
+	if err != nil {
+		return errors.InvalidStateError.Errorf("No next block height=%d", t.height)
+	}
+	if err := sw.WriteVotes(nblk.Votes()); err != nil {
+		return err
+	}
+	if err := sw.Close(&snapshot.Manifest{
+		NID:    common.HexInt32{Value: int32(c.NID())},
+		CID:    common.HexInt32{Value: int32(c.CID())},
+		Height: common.HexInt64{Value: t.height},
+		Block:  blk.ID(),
+		Codec:  codec.BC.Name(),
+	}); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), t.file)
+}
+
+func (t *taskSnapshot) Stop() {
+	atomic.StoreInt32(&t.stop, 1)
+}
+
+func (t *taskSnapshot) Wait() error {
+	return t.result.Wait()
+}
+
+func taskSnapshotFactory(c *singleChain, params json.RawMessage) (chainTask, error) {
+	var p snapshotParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	if p.Height < 2 || len(p.File) == 0 {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidParameter(height=%d,file=%q)", p.Height, p.File)
+	}
+	return &taskSnapshot{
+		chain:  c,
+		height: p.Height,
+		file:   c.cfg.ResolveAbsolute(p.File),
+	}, nil
+}
+
+func init() {
+	registerTaskFactory(SnapshotTask, taskSnapshotFactory)
+}
@@ -119,19 +119,21 @@ type TransactionResult struct {
 }
 
 type EventNotification struct {
-	Hash   jsonrpc.HexBytes `json:"hash"`
-	Height jsonrpc.HexInt   `json:"height"`
-	Index  jsonrpc.HexInt   `json:"index"`
-	Events []jsonrpc.HexInt `json:"events"`
-	Logs   []EventLog       `json:"logs,omitempty"`
+	Hash   jsonrpc.HexBytes           `json:"hash"`
+	Height jsonrpc.HexInt             `json:"height"`
+	Index  jsonrpc.HexInt             `json:"index"`
+	Events []jsonrpc.HexInt           `json:"events"`
+	Logs   []EventLog                 `json:"logs,omitempty"`
+	Cursor *server.NotificationCursor `json:"cursor,omitempty"`
 }
 
 type BlockNotification struct {
-	Hash    jsonrpc.HexBytes     `json:"hash"`
-	Height  jsonrpc.HexInt       `json:"height"`
-	Indexes [][]jsonrpc.HexInt   `json:"indexes,omitempty"`
-	Events  [][][]jsonrpc.HexInt `json:"events,omitempty"`
-	Logs    [][][]EventLog       `json:"logs,omitempty"`
+	Hash    jsonrpc.HexBytes           `json:"hash"`
+	Height  jsonrpc.HexInt             `json:"height"`
+	Indexes [][]jsonrpc.HexInt         `json:"indexes,omitempty"`
+	Events  [][][]jsonrpc.HexInt       `json:"events,omitempty"`
+	Logs    [][][]EventLog             `json:"logs,omitempty"`
+	Cursor  *server.NotificationCursor `json:"cursor,omitempty"`
 }
 
 //refer service/txresult/receipt.go:29 eventLogJSON
@@ -393,6 +395,15 @@ func (c *ClientV3) GetProofForEvents(param *v3.ProofEventsParam) ([][][]byte, er
 	return result, nil
 }
 
+func (c *ClientV3) GetProofForState(param *v3.ProofStateParam) ([][][]byte, error) {
+	var result [][][]byte
+	_, err := c.Do("icx_getProofForState", param, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *ClientV3) GetBTPNetworkInfo(param *v3.BTPQueryParam) (*BTPNetworkInfo, error) {
 	ni := &BTPNetworkInfo{}
 	if _, err := c.Do("btp_getNetworkInfo", param, ni); err != nil {
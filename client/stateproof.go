@@ -0,0 +1,43 @@
+package client
+
+import (
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// VerifyStateProof verifies proofs returned by icx_getProofForState against
+// the state hash, the first field of the result in the block header.
+// It returns the account and the values for the keys proven by the proofs.
+// The account or the value is nil if its absence is proven.
+func VerifyStateProof(stateHash []byte, addr module.Address, keys [][]byte, proofs [][][]byte) (state.AccountSnapshot, [][]byte, error) {
+	if len(proofs) != len(keys)+1 {
+		return nil, nil, errors.IllegalArgumentError.Errorf(
+			"InvalidProofCount(keys=%d,proofs=%d)", len(keys), len(proofs))
+	}
+	ass, err := state.ProveAccount(stateHash, addr.ID(), proofs[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(keys) == 0 {
+		return ass, nil, nil
+	}
+
+	var storageHash []byte
+	if ass != nil {
+		sp, ok := ass.(state.StorageProver)
+		if !ok {
+			return nil, nil, errors.IllegalArgumentError.Errorf("NoStorage(addr=%s)", addr)
+		}
+		storageHash = sp.StorageHash()
+	}
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		value, err := state.ProveStorage(storageHash, key, proofs[i+1])
+		if err != nil {
+			return nil, nil, err
+		}
+		values[i] = value
+	}
+	return ass, values, nil
+}
@@ -21,6 +21,8 @@ import (
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/node"
 )
 
@@ -145,6 +147,8 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 				param.NephewsLimit = &nephewsLimit
 			}
 			param.ValidateTxOnSend, _ = fs.GetBool("validate_tx_on_send")
+			param.BLSKey, _ = fs.GetString("bls_key")
+			param.BLSKeySecret, _ = fs.GetString("bls_key_secret")
 
 			var buf *bytes.Buffer
 			if len(genesisZip) > 0 {
@@ -202,6 +206,8 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.Int("children_limit", -1, "Maximum number of child connections (-1: uses system default value)")
 	joinFlags.Int("nephews_limit", -1, "Maximum number of nephew connections (-1: uses system default value)")
 	joinFlags.Bool("validate_tx_on_send", false, "Validate transaction on send")
+	joinFlags.String("bls_key", "", "BLS keystore file path for BTP (relative path is based on the chain directory)")
+	joinFlags.String("bls_key_secret", "", "Secret (password) file path for the BLS keystore (relative path is based on the chain directory)")
 
 	leaveCmd := &cobra.Command{
 		Use:   "leave CID",
@@ -408,6 +414,152 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	backupFlags := backupCmd.Flags()
 	backupFlags.Bool("manual", false, "Manual backup mode (just release database)")
 
+	snapshotCmd := &cobra.Command{
+		Use:   "snapshot CID",
+		Short: "Start to export the state snapshot at the height",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &node.ChainSnapshotParam{}
+			param.Height, _ = fs.GetInt64("height")
+			param.File, _ = fs.GetString("file")
+
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/snapshot"
+			_, err := adminClient.PostWithJson(reqUrl, param, &v)
+			if err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotFlags := snapshotCmd.Flags()
+	snapshotFlags.Int64("height", 0, "Block Height")
+	snapshotFlags.String("file", "", "Snapshot file path (relative to the chain directory)")
+	MarkAnnotationRequired(snapshotFlags, "height", "file")
+
+	importSnapshotCmd := &cobra.Command{
+		Use:   "import_snapshot CID",
+		Short: "Start to import the state snapshot",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &node.ChainImportSnapshotParam{}
+			param.File, _ = fs.GetString("file")
+			blockHash, _ := fs.GetString("block_hash")
+			if len(blockHash) >= 2 && blockHash[:2] == "0x" {
+				blockHash = blockHash[2:]
+			}
+			var err error
+			if param.BlockHash, err = hex.DecodeString(blockHash); err != nil {
+				return err
+			}
+
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/import_snapshot"
+			if _, err = adminClient.PostWithJson(reqUrl, param, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(importSnapshotCmd)
+	importSnapshotFlags := importSnapshotCmd.Flags()
+	importSnapshotFlags.String("file", "", "Snapshot file path (relative to the chain directory)")
+	importSnapshotFlags.String("block_hash", "", "Trusted hash of the block of the snapshot")
+	MarkAnnotationRequired(importSnapshotFlags, "file", "block_hash")
+
+	trafficCmd := &cobra.Command{
+		Use:   "traffic CID",
+		Short: "Show traffic statistics for protocols and peers",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := &network.TrafficView{}
+			reqUrl := node.UrlChain + "/" + args[0] + "/traffic"
+			if _, err := adminClient.Get(reqUrl, v); err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, v)
+		},
+	}
+	rootCmd.AddCommand(trafficCmd)
+
+	bansCmd := &cobra.Command{
+		Use:   "bans CID",
+		Short: "List banned peers",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var v []module.PeerBan
+			reqUrl := node.UrlChain + "/" + args[0] + "/bans"
+			if _, err := adminClient.Get(reqUrl, &v); err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, v)
+		},
+	}
+	rootCmd.AddCommand(bansCmd)
+
+	banCmd := &cobra.Command{
+		Use:   "ban CID",
+		Short: "Ban the peer by ID or IP",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &node.ChainBanParam{}
+			param.ID, _ = fs.GetString("id")
+			param.IP, _ = fs.GetString("ip")
+			param.Duration, _ = fs.GetInt64("duration")
+			param.Reason, _ = fs.GetString("reason")
+			if len(param.ID) == 0 && len(param.IP) == 0 {
+				return fmt.Errorf("id or ip required")
+			}
+
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/ban"
+			if _, err := adminClient.PostWithJson(reqUrl, param, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(banCmd)
+	banFlags := banCmd.Flags()
+	banFlags.String("id", "", "Peer ID (address of the node)")
+	banFlags.String("ip", "", "IP address of the peer")
+	banFlags.Int64("duration", 0, "Ban duration in milli-second(0:uses configured value)")
+	banFlags.String("reason", "", "Reason of the ban")
+
+	unbanCmd := &cobra.Command{
+		Use:   "unban CID",
+		Short: "Remove bans of the peer by ID or IP",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &node.ChainUnbanParam{}
+			param.ID, _ = fs.GetString("id")
+			param.IP, _ = fs.GetString("ip")
+			if len(param.ID) == 0 && len(param.IP) == 0 {
+				return fmt.Errorf("id or ip required")
+			}
+
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/unban"
+			if _, err := adminClient.PostWithJson(reqUrl, param, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(unbanCmd)
+	unbanFlags := unbanCmd.Flags()
+	unbanFlags.String("id", "", "Peer ID (address of the node)")
+	unbanFlags.String("ip", "", "IP address of the peer")
+
 	genesisCmd := &cobra.Command{
 		Use:   "genesis CID FILE",
 		Short: "Download chain genesis file",
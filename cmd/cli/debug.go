@@ -17,6 +17,7 @@
 package cli
 
 import (
+	"fmt"
 	"net/http"
 	"os"
 
@@ -24,6 +25,9 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/icon-project/goloop/client"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/server/jsonrpc"
 	v3 "github.com/icon-project/goloop/server/v3"
 )
@@ -44,6 +48,14 @@ func AddDebugRequiredFlags(c *cobra.Command) {
 	MarkAnnotationCustom(pFlags, "uri")
 }
 
+var debugCmdFactories []func() *cobra.Command
+
+// RegisterDebugCommand registers the factory of the platform specific
+// command, which is added under the debug command.
+func RegisterDebugCommand(factory func() *cobra.Command) {
+	debugCmdFactories = append(debugCmdFactories, factory)
+}
+
 func NewDebugCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command, *viper.Viper) {
 	var debugClient client.JsonRpcClient
 	rootCmd, vc := NewCommand(parentCmd, parentVc, "debug", "DEBUG API")
@@ -56,8 +68,9 @@ func NewDebugCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 		Short: "Get trace of the transaction",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			param := &v3.TransactionHashParam{
+			param := &v3.TraceParam{
 				Hash: jsonrpc.HexBytes(args[0]),
+				Mode: cmd.Flag("mode").Value.String(),
 			}
 			trace, err := debugClient.Do("debug_getTrace", param, nil)
 			if err != nil {
@@ -66,7 +79,117 @@ func NewDebugCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 			return JsonPrettyPrintln(os.Stdout, trace.Result)
 		},
 	}
+	traceCmd.Flags().String("mode", "", "Trace mode (invoke or callTree)")
 	rootCmd.AddCommand(traceCmd)
 
+	traceBlockCmd := &cobra.Command{
+		Use:   "traceblock",
+		Short: "Get trace of the transactions in the block",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			param := &v3.TraceBlockParam{
+				Mode: cmd.Flag("mode").Value.String(),
+			}
+			height, err := intconv.ParseInt(cmd.Flag("height").Value.String(), 64)
+			if err != nil {
+				return err
+			}
+			if hash := cmd.Flag("hash").Value.String(); hash != "" {
+				param.Hash = jsonrpc.HexBytes(hash)
+			} else if height != -1 {
+				param.Height = jsonrpc.HexInt(intconv.FormatInt(height))
+			} else {
+				return fmt.Errorf("height or hash is required")
+			}
+			trace, err := debugClient.Do("debug_traceBlock", param, nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, trace.Result)
+		},
+	}
+	traceBlockFlags := traceBlockCmd.Flags()
+	traceBlockFlags.Int("height", -1, "BlockHeight")
+	traceBlockFlags.String("hash", "", "BlockHash")
+	traceBlockFlags.String("mode", "", "Trace mode (invoke or callTree)")
+	rootCmd.AddCommand(traceBlockCmd)
+
+	rootCmd.AddCommand(NewDebugWALCmd())
+	for _, factory := range debugCmdFactories {
+		rootCmd.AddCommand(factory())
+	}
+
 	return rootCmd, vc
 }
+
+type walInspection struct {
+	*consensus.WALInspectResult
+	Entries []*consensus.WALRecord `json:"entries,omitempty"`
+}
+
+func NewDebugWALCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wal DIR",
+		Short: "Inspect consensus WAL of the chain",
+		Long: "Inspect consensus WAL in DIR (e.g. <node_dir>/<chain_id>/wal).\n" +
+			"It decodes entries of the WAL and validates signatures of them.\n" +
+			"Corrupted tail of the WAL is truncated only with --repair.\n" +
+			"Stop the node before repairing the WAL.",
+		Args: cobra.ExactArgs(1),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			names, _ := fs.GetStringSlice("name")
+			if len(names) == 0 {
+				names = consensus.WALNames()
+			}
+			opt := &consensus.WALInspectOption{}
+			if nid, _ := fs.GetString("nid"); nid != "" {
+				v, err := intconv.ParseUint(nid, 32)
+				if err != nil {
+					return fmt.Errorf("invalid nid %s", nid)
+				}
+				opt.NID = uint32(v)
+			}
+			validators, _ := fs.GetStringSlice("validators")
+			for _, v := range validators {
+				addr, err := common.NewAddressFromString(v)
+				if err != nil {
+					return fmt.Errorf("invalid validator address %s", v)
+				}
+				opt.Validators = append(opt.Validators, addr)
+			}
+			opt.Repair, _ = fs.GetBool("repair")
+			summary, _ := fs.GetBool("summary")
+
+			var results []*walInspection
+			for _, name := range names {
+				ins := new(walInspection)
+				res, err := consensus.InspectWAL(args[0], name, opt,
+					func(r *consensus.WALRecord) error {
+						if !summary {
+							ins.Entries = append(ins.Entries, r)
+						}
+						return nil
+					})
+				if consensus.IsNotExist(err) {
+					continue
+				} else if err != nil {
+					return err
+				}
+				ins.WALInspectResult = res
+				results = append(results, ins)
+			}
+			return JsonPrettyPrintln(os.Stdout, results)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringSlice("name", nil, "Name of WAL to inspect (round,lock,commit), all if empty")
+	flags.String("nid", "", "Network ID of the chain to verify messages")
+	flags.StringSlice("validators", nil, "Addresses of validators to verify signers")
+	flags.Bool("repair", false, "Truncate corrupted tail of WAL (dry-run if false)")
+	flags.Bool("summary", false, "Print summary only")
+	return cmd
+}
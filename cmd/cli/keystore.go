@@ -11,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/icon-project/goloop/btp/ntm"
 	"github.com/icon-project/goloop/common/wallet"
 )
 
@@ -67,6 +68,36 @@ func newKeystoreGenCmd(c string) *cobra.Command {
 	return cmd
 }
 
+func newBLSKeyGenCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   c,
+		Short: "Generate BLS key for BTP",
+	}
+	flags := cmd.PersistentFlags()
+	out := flags.StringP("out", "o", "bls_keystore.json", "Output file path")
+	interactive := flags.BoolP("interactive", "i", false, "Interactive mode for password input")
+	secret := flags.StringP("secret", "s", "", "KeySecret file path")
+	pass := flags.StringP("password", "p", "gochain", "Password for the keystore")
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		pb := getPasswordFromFlags("Password: ", interactive, secret, pass)
+		sk := ntm.GenerateBLSSecret()
+		w, err := ntm.NewBLSWallet(sk)
+		if err != nil {
+			log.Panicf("Fail to generate BLS key err=%+v", err)
+		}
+		ks, err := ntm.EncryptBLSKeyStore(sk, pb)
+		if err != nil {
+			log.Panicf("Fail to generate BLS keystore err=%+v", err)
+		}
+		if err := os.WriteFile(*out, ks, 0600); err != nil {
+			log.Panicf("Fail to write BLS keystore err=%+v", err)
+		}
+		fmt.Printf("0x%x ==> %s\n", w.PublicKey(), *out)
+	}
+	return cmd
+}
+
 func newVerifyCmd(c string) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   c,
@@ -145,6 +176,7 @@ func newReEncryptCmd(c string) *cobra.Command {
 func NewKeystoreCmd(c string) *cobra.Command {
 	cmd := &cobra.Command{Use: c, Short: "Keystore manipulation"}
 	cmd.AddCommand(newKeystoreGenCmd("gen"))
+	cmd.AddCommand(newBLSKeyGenCmd("blsgen"))
 	cmd.AddCommand(newVerifyCmd("verify"))
 	cmd.AddCommand(publickeyFromKeyStore("pubkey"))
 	cmd.AddCommand(newReEncryptCmd("encrypt"))
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
+	"github.com/icon-project/goloop/btp/verifier"
 	"github.com/icon-project/goloop/client"
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/errors"
@@ -454,6 +456,31 @@ func NewRpcCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command,
 				}
 				return JsonPrettyPrintln(os.Stdout, raw)
 			},
+		},
+		&cobra.Command{
+			Use:   "proofforstate BLOCK_HASH ADDRESS [KEYS]",
+			Short: "GetProofForState",
+			Args:  ArgsWithDefaultErrorFunc(cobra.RangeArgs(2, 3)),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				param := &v3.ProofStateParam{
+					BlockHash: jsonrpc.HexBytes(args[0]),
+					Address:   jsonrpc.Address(args[1]),
+				}
+				if len(args) > 2 {
+					for _, str := range strings.Split(args[2], ",") {
+						key, err := hex.DecodeString(strings.TrimPrefix(str, "0x"))
+						if err != nil {
+							return err
+						}
+						param.Keys = append(param.Keys, key)
+					}
+				}
+				raw, err := rpcClient.GetProofForState(param)
+				if err != nil {
+					return err
+				}
+				return JsonPrettyPrintln(os.Stdout, raw)
+			},
 		})
 	scoreStatusCmd := &cobra.Command{
 		Use:   "scorestatus ADDRESS",
@@ -1276,5 +1303,157 @@ func NewMonitorCmd(parentCmd *cobra.Command, parentVc *viper.Viper) *cobra.Comma
 		"BTP Network ID")
 	monitorBTPFlags.Bool("proof_flag", false, "Includes proof")
 
+	monitorBTPMessagesCmd := &cobra.Command{
+		Use:   "btpmessages NETWORK_ID",
+		Short: "MonitorBTPMessages",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nid, err := intconv.ParseInt(args[0], 64)
+			if err != nil {
+				return err
+			}
+			statePath := cmd.Flag("state").Value.String()
+			s, err := loadBTPVerifierState(&rpcClient, nid, statePath)
+			if err != nil {
+				return err
+			}
+			v, err := verifier.NewVerifier(s)
+			if err != nil {
+				return err
+			}
+			param := &server.BTPRequest{
+				Height:    common.HexInt64{Value: s.Height + 1},
+				NetworkId: common.HexInt64{Value: nid},
+				ProofFlag: common.HexBool{Value: true},
+			}
+			if s.Height == 0 {
+				ni, err := rpcClient.GetBTPNetworkInfo(&v3.BTPQueryParam{
+					Id: jsonrpc.HexInt(intconv.FormatInt(nid)),
+				})
+				if err != nil {
+					return err
+				}
+				param.Height.Value = ni.StartHeight.Value() + 1
+			}
+
+			var verifyErr error
+			OnInterrupt(rpcClient.Cleanup)
+			err = rpcClient.MonitorBtp(param, func(n *server.BTPNotification) {
+				if verifyErr != nil {
+					return
+				}
+				if verifyErr = onBTPNotification(&rpcClient, v, n, statePath); verifyErr != nil {
+					rpcClient.Cleanup()
+				}
+			}, nil)
+			if verifyErr != nil {
+				return verifyErr
+			}
+			return err
+		},
+	}
+	rootCmd.AddCommand(monitorBTPMessagesCmd)
+	monitorBTPMessagesFlags := monitorBTPMessagesCmd.Flags()
+	monitorBTPMessagesFlags.String("state", "",
+		"File path to load and save verified state of the network")
+
 	return rootCmd
 }
+
+type btpMessageBatch struct {
+	Height         common.HexInt64 `json:"height"`
+	NetworkID      common.HexInt64 `json:"networkID"`
+	FirstMessageSN common.HexInt64 `json:"firstMessageSN"`
+	Messages       []string        `json:"messages"`
+}
+
+// loadBTPVerifierState returns the state stored in the file. If there is no
+// file, it returns the state for verifying the network from the first BTP
+// block.
+func loadBTPVerifierState(c *client.ClientV3, nid int64, path string) (*verifier.State, error) {
+	if len(path) > 0 {
+		bs, err := os.ReadFile(path)
+		if err == nil {
+			s := new(verifier.State)
+			if err := json.Unmarshal(bs, s); err != nil {
+				return nil, errors.Wrapf(err, "fail to parse state file=%s", path)
+			}
+			if s.NetworkID != nid {
+				return nil, errors.IllegalArgumentError.Errorf(
+					"network of state mismatch (state=%d, given=%d)", s.NetworkID, nid)
+			}
+			return s, nil
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	si, err := c.GetBTPSourceInformation()
+	if err != nil {
+		return nil, err
+	}
+	ni, err := c.GetBTPNetworkInfo(&v3.BTPQueryParam{
+		Id: jsonrpc.HexInt(intconv.FormatInt(nid)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &verifier.State{
+		SrcNetworkUID:  si.SrcNetworkUID,
+		NetworkTypeID:  ni.NetworkTypeID.Value(),
+		NetworkTypeUID: ni.NetworkTypeName,
+		NetworkID:      nid,
+	}, nil
+}
+
+func onBTPNotification(c *client.ClientV3, v *verifier.Verifier, n *server.BTPNotification, statePath string) error {
+	hb, err := base64.StdEncoding.DecodeString(n.Header)
+	if err != nil {
+		return err
+	}
+	var pb []byte
+	if len(n.Proof) > 0 {
+		if pb, err = base64.StdEncoding.DecodeString(n.Proof); err != nil {
+			return err
+		}
+	}
+	bh, err := v.Verify(hb, pb)
+	if err != nil {
+		return err
+	}
+	if bh.MessageCount() > 0 {
+		msgs, err := c.GetBTPMessages(&v3.BTPMessagesParam{
+			Height:    jsonrpc.HexInt(intconv.FormatInt(bh.MainHeight())),
+			NetworkId: jsonrpc.HexInt(intconv.FormatInt(bh.NetworkID())),
+		})
+		if err != nil {
+			return err
+		}
+		msgBytes := make([][]byte, len(msgs))
+		for i, msg := range msgs {
+			if msgBytes[i], err = base64.StdEncoding.DecodeString(msg); err != nil {
+				return err
+			}
+		}
+		if err = v.VerifyMessages(bh, msgBytes); err != nil {
+			return err
+		}
+		if err = JsonPrettyPrintln(os.Stdout, &btpMessageBatch{
+			Height:         common.HexInt64{Value: bh.MainHeight()},
+			NetworkID:      common.HexInt64{Value: bh.NetworkID()},
+			FirstMessageSN: common.HexInt64{Value: bh.FirstMessageSN()},
+			Messages:       msgs,
+		}); err != nil {
+			return err
+		}
+	}
+	if len(statePath) > 0 {
+		bs, err := json.MarshalIndent(v.State(), "", "  ")
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(statePath, bs, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -27,6 +27,9 @@ type ServerConfig struct {
 	KeyPlugin     string            `json:"key_plugin,omitempty"`
 	KeyPlgOptions map[string]string `json:"key_plugin_options,omitempty"`
 
+	KeySigner     string            `json:"key_signer,omitempty"`
+	KeySgnOptions map[string]string `json:"key_signer_options,omitempty"`
+
 	Wallet module.Wallet `json:"-"`
 
 	LogLevel     string               `json:"log_level"`
@@ -51,6 +54,15 @@ func (cfg *ServerConfig) MakesureWallet(gen bool) error {
 	if cfg.Wallet != nil {
 		return nil
 	}
+	if cfg.KeySigner != "" {
+		tlsCfg := wallet.SignerTLSConfigFromOptions(cfg.KeySgnOptions)
+		if w, err := wallet.OpenRemote(cfg.KeySigner, tlsCfg); err != nil {
+			return err
+		} else {
+			cfg.Wallet = w
+			return nil
+		}
+	}
 	if cfg.KeyPlugin != "" {
 		options := make(map[string]string)
 		for k, v := range cfg.KeyPlgOptions {
@@ -155,13 +167,15 @@ func NewServerCmd(parentCmd *cobra.Command, parentVc *viper.Viper, version, buil
 	rootPFlags.String("key_secret", "", "Secret (password) file for KeyStore")
 	rootPFlags.String("key_plugin", "", "KeyPlugin file for wallet")
 	rootPFlags.StringToString("key_plugin_options", nil, "KeyPlugin options")
+	rootPFlags.String("key_signer", "", "Remote signer address for wallet (unix:PATH or tcp:HOST:PORT)")
+	rootPFlags.StringToString("key_signer_options", nil, "Remote signer options (cert,key,ca,server_name)")
 	//
 	rootPFlags.String("log_forwarder_vendor", "", "LogForwarder vendor (fluentd,logstash)")
 	rootPFlags.String("log_forwarder_address", "", "LogForwarder address")
 	rootPFlags.String("log_forwarder_level", "info", "LogForwarder level")
 	rootPFlags.String("log_forwarder_name", "", "LogForwarder name")
 	rootPFlags.StringToString("log_forwarder_options", nil, "LogForwarder options, comma-separated 'key=value'")
-	rootPFlags.String("engines", "python", "Execution engines, comma-separated (python,java)")
+	rootPFlags.String("engines", "python", "Execution engines, comma-separated (python,java,wasm)")
 
 	rootPFlags.String("log_writer_filename", "", "Log filename (rotated files resides in same directory)")
 	rootPFlags.Int("log_writer_maxsize", 100, "Maximum log file size in MiB")
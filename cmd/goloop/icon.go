@@ -18,9 +18,12 @@ package main
 
 import (
 	"github.com/icon-project/goloop/chain"
+	"github.com/icon-project/goloop/cmd/cli"
 	"github.com/icon-project/goloop/icon"
 )
 
 func init() {
 	chain.RegisterPlatform("icon", icon.NewPlatform)
+	cli.RegisterDebugCommand(newDebugWhatIfCmd)
+	cli.RegisterDebugCommand(newDebugIISSCmd)
 }
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/cmd/cli"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/icon/icsim"
+	"github.com/icon-project/goloop/module"
+)
+
+func dumpIISS(dbase db.Database, height int64, addrs []module.Address) (interface{}, error) {
+	sim, err := newSimulatorFromDB(dbase, height)
+	if err != nil {
+		return nil, err
+	}
+	dump, err := sim.DumpIISS(addrs)
+	if err != nil {
+		return nil, err
+	}
+	tobj, err := common.EncodeAny(dump)
+	if err != nil {
+		return nil, err
+	}
+	return common.DecodeAnyForJSON(tobj)
+}
+
+func newDebugIISSCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "iiss DB_DIR",
+		Short: "Show IISS state of ICON chain",
+		Long: "Show IISS state of ICON chain in DB_DIR (e.g. <node_dir>/<cid>/db/<nid>)\n" +
+			"after the block at --height as JSON. The database is opened read-only\n" +
+			"(goleveldb or pebble), but stop the node or use a copy of it for\n" +
+			"the database locked by the node.\n\n" +
+			"It shows network values, term, reward fund, P-Reps and pending entries\n" +
+			"of reward calculation (front, back1 and back2). Accounts (stakes,\n" +
+			"delegations, bonds, unbonds and rewards) are shown only for --address,\n" +
+			"and P-Reps and pending entries are also filtered by them.\n" +
+			"With --diff, it shows changed values from --height to the height.",
+		Args: cobra.ExactArgs(1),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			height, _ := fs.GetInt64("height")
+			diff, _ := fs.GetInt64("diff")
+			dbType, _ := fs.GetString("db_type")
+			params, _ := fs.GetStringSlice("address")
+
+			addrs := make([]module.Address, len(params))
+			for i, param := range params {
+				addr, err := common.NewAddressFromString(param)
+				if err != nil {
+					return fmt.Errorf("invalid address %s err=%+v", param, err)
+				}
+				addrs[i] = addr
+			}
+
+			log.GlobalLogger().SetConsoleLevel(log.WarnLevel)
+			dbase, err := db.OpenReadOnly(args[0], dbType, "")
+			if err != nil {
+				return err
+			}
+			defer dbase.Close()
+
+			jso, err := dumpIISS(dbase, height, addrs)
+			if err != nil {
+				return err
+			}
+			if diff >= 0 {
+				to, err := dumpIISS(dbase, diff, addrs)
+				if err != nil {
+					return err
+				}
+				jso = icsim.DiffIISS(jso, to)
+			}
+			return cli.JsonPrettyPrintln(os.Stdout, jso)
+		},
+	}
+	flags := cmd.Flags()
+	flags.Int64("height", -1, "Height of the block to show the state after (latest if negative)")
+	flags.Int64("diff", -1, "Height of the block to compare the state with (no comparison if negative)")
+	flags.StringSlice("address", nil, "Addresses of accounts and P-Reps to show")
+	flags.String("db_type", "goleveldb",
+		fmt.Sprintf("Name of database system (%s)", strings.Join(db.GetReadOnlySupportedTypes(), ", ")))
+	return cmd
+}
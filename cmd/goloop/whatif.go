@@ -0,0 +1,113 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/cmd/cli"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/icon/icsim"
+)
+
+// newSimulatorFromDB forks the state of the chain in dbase after the block
+// at height. It uses the latest block having its result if height is negative.
+func newSimulatorFromDB(dbase db.Database, height int64) (icsim.Simulator, error) {
+	if height < 0 {
+		last, err := block.GetLastHeight(dbase)
+		if err != nil {
+			return nil, err
+		}
+		height = last - 1
+	}
+	return icsim.NewSimulatorFromChain(dbase, height, nil)
+}
+
+func newDebugWhatIfCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "whatif DB_DIR SCENARIO",
+		Short: "Simulate rewards of ICON chain with hypothetical transactions",
+		Long: "Fork the state of ICON chain in DB_DIR (e.g. <node_dir>/<cid>/db/<nid>)\n" +
+			"after the block at --height, and run SCENARIO (JSON file) on it.\n" +
+			"The database isn't modified, but stop the node or use a copy of it\n" +
+			"for the database locked by the node.\n\n" +
+			"SCENARIO sets balances, executes transactions (method and params of\n" +
+			"the chain SCORE API) in a block, then goes to the end of the term\n" +
+			"for the given times. Validators in offline don't vote for blocks, and\n" +
+			"I-Scores of accounts are reported for each term. For example,\n" +
+			"  {\"balances\": {\"hx...\": \"0x...\"},\n" +
+			"   \"transactions\": [{\"from\": \"hx...\", \"method\": \"setStake\",\n" +
+			"     \"params\": {\"value\": \"0x...\"}}],\n" +
+			"   \"terms\": 3, \"offline\": [\"hx...\"], \"accounts\": [\"hx...\"]}",
+		Args: cobra.ExactArgs(2),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			height, _ := fs.GetInt64("height")
+			dbType, _ := fs.GetString("db_type")
+
+			bs, err := os.ReadFile(args[1])
+			if err != nil {
+				return err
+			}
+			w := new(icsim.WhatIf)
+			if err = json.Unmarshal(bs, w); err != nil {
+				return fmt.Errorf("invalid scenario %s err=%+v", args[1], err)
+			}
+
+			log.GlobalLogger().SetConsoleLevel(log.WarnLevel)
+			dbase, err := db.Open(args[0], dbType, "")
+			if err != nil {
+				return err
+			}
+			defer dbase.Close()
+
+			sim, err := newSimulatorFromDB(dbase, height)
+			if err != nil {
+				return err
+			}
+			res, err := sim.WhatIf(w)
+			if err != nil {
+				return err
+			}
+			tobj, err := common.EncodeAny(res)
+			if err != nil {
+				return err
+			}
+			jso, err := common.DecodeAnyForJSON(tobj)
+			if err != nil {
+				return err
+			}
+			return cli.JsonPrettyPrintln(os.Stdout, jso)
+		},
+	}
+	flags := cmd.Flags()
+	flags.Int64("height", -1, "Height of the block to fork the state after (latest if negative)")
+	flags.String("db_type", "goleveldb",
+		fmt.Sprintf("Name of database system (%s)", strings.Join(db.GetSupportedTypes(), ", ")))
+	return cmd
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// gosigner is the reference signer for remote wallets of nodes. It keeps
+// the key in the KeyStore file, and signs data for nodes connected to it.
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/wallet"
+)
+
+var (
+	keyStore  string
+	keyPass   string
+	keySecret string
+	listen    string
+	statePath string
+	tlsConfig wallet.SignerTLSConfig
+)
+
+func openWallet() (*wallet.Signer, error) {
+	ks, err := os.ReadFile(keyStore)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to read KeyStore file=%s", keyStore)
+	}
+	pass := keyPass
+	if keySecret != "" {
+		bs, err := os.ReadFile(keySecret)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to read KeySecret file=%s", keySecret)
+		}
+		pass = strings.TrimSpace(string(bs))
+	}
+	pk, err := wallet.DecryptKeyStore(ks, []byte(pass))
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to decrypt KeyStore")
+	}
+	w, err := wallet.NewFromPrivateKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	return wallet.NewSigner(w, statePath, log.GlobalLogger())
+}
+
+func run() error {
+	s, err := openWallet()
+	if err != nil {
+		return err
+	}
+	if err := s.Listen(listen, &tlsConfig); err != nil {
+		return err
+	}
+	log.Infof("Signer address=%s listen=%s", s.Address(), listen)
+
+	sch := make(chan os.Signal, 1)
+	signal.Notify(sch, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sch
+		_ = s.Close()
+	}()
+	if err := s.Loop(); err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
+
+func main() {
+	rootCmd := &cobra.Command{
+		Use:   os.Args[0],
+		Short: "Remote signer for the wallet of nodes",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := run(); err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, "Error:", err)
+				os.Exit(1)
+			}
+		},
+	}
+	flags := rootCmd.Flags()
+	flags.StringVar(&keyStore, "key_store", "", "KeyStore file for wallet")
+	flags.StringVar(&keyPass, "key_password", "", "Password for the KeyStore file")
+	flags.StringVar(&keySecret, "key_secret", "", "Secret (password) file for KeyStore")
+	flags.StringVar(&listen, "listen", "unix:signer.sock", "Listen address (unix:PATH or tcp:HOST:PORT)")
+	flags.StringVar(&statePath, "state", "signer_state.json", "File for the last signed position")
+	flags.StringVar(&tlsConfig.Cert, "cert", "", "Certificate file for TLS")
+	flags.StringVar(&tlsConfig.Key, "key", "", "Private key file of the certificate")
+	flags.StringVar(&tlsConfig.CA, "ca", "", "CA certificate file to verify nodes")
+	_ = rootCmd.MarkFlagRequired("key_store")
+
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -115,7 +115,7 @@ func (m *CallMaker) MakeOne() (interface{}, error) {
 		}
 		params[n] = buffer.String()
 	}
-	return makeCallTx(m.NID, m.owner, m.contract, m.Method, params)
+	return makeCallTx(m.NID, m.owner, m.contract, stepLimitForCoinTransfer, m.Method, params)
 }
 
 func (m *CallMaker) Dispose(tx interface{}) {
@@ -123,14 +123,14 @@ func (m *CallMaker) Dispose(tx interface{}) {
 }
 
 func makeCallTx(nid int64, from module.Wallet,
-	contract module.Address, method string, params map[string]string,
+	contract module.Address, stepLimit int64, method string, params map[string]string,
 ) (interface{}, error) {
 	tx := map[string]interface{}{
 		"version":   "0x3",
 		"from":      from.Address(),
 		"to":        contract,
 		"nid":       fmt.Sprintf("0x%x", nid),
-		"stepLimit": fmt.Sprintf("0x%x", stepLimitForCoinTransfer),
+		"stepLimit": fmt.Sprintf("0x%x", stepLimit),
 		"timestamp": TimeStampNow(),
 		"dataType":  "call",
 		"data": map[string]interface{}{
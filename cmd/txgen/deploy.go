@@ -69,31 +69,42 @@ func zipDirectory(fd io.Writer, p string) error {
 	return zfd.Close()
 }
 
-func makeDeploy(nid int64, from module.Wallet, src string, params interface{}) (interface{}, error) {
-	contentType := ""
-	content := ""
+func makeDeployContent(src string) (contentType string, content string, err error) {
 	if strings.HasSuffix(src, ".jar") {
 		contentType = "application/java"
 		data, err := os.ReadFile(src)
 		if err != nil {
-			return nil, err
+			return "", "", err
 		}
 		content = "0x" + hex.EncodeToString(data)
 	} else {
 		contentType = "application/zip"
 		buf := bytes.NewBuffer(nil)
 		if err := zipDirectory(buf, src); err != nil {
-			return nil, err
+			return "", "", err
 		}
 		content = "0x" + hex.EncodeToString(buf.Bytes())
 	}
+	return contentType, content, nil
+}
+
+func makeDeploy(nid int64, from module.Wallet, src string, params interface{}) (interface{}, error) {
+	contentType, content, err := makeDeployContent(src)
+	if err != nil {
+		return nil, err
+	}
+	return makeDeployTx(nid, from, stepLimitForDeploy, contentType, content, params)
+}
 
+func makeDeployTx(nid int64, from module.Wallet, stepLimit int64,
+	contentType, content string, params interface{},
+) (interface{}, error) {
 	tx := map[string]interface{}{
 		"version":   "0x3",
 		"from":      from.Address(),
 		"to":        "cx0000000000000000000000000000000000000000",
 		"nid":       fmt.Sprintf("0x%x", nid),
-		"stepLimit": fmt.Sprintf("0x%x", stepLimitForDeploy),
+		"stepLimit": fmt.Sprintf("0x%x", stepLimit),
 		"timestamp": TimeStampNow(),
 		"dataType":  "deploy",
 		"data": map[string]interface{}{
@@ -25,6 +25,8 @@ func main() {
 	var index, last int64
 	var waitTimeout int64
 	var noWaitResult bool
+	var scenarioFile string
+	var reportFile string
 
 	cmd := &cobra.Command{
 		Use: fmt.Sprintf("%s [urls]", os.Args[0]),
@@ -45,6 +47,8 @@ func main() {
 	flags.Int64VarP(&last, "last", "l", 0, "Last index value to be used for generating transaction")
 	flags.Int64Var(&waitTimeout, "wait", 0, "Wait for specified time (in ms) for each TX (enable to use sendAndWait)")
 	flags.BoolVar(&noWaitResult, "nowaitresult", false, "No wait for result for confirm in COIN transfer")
+	flags.StringVar(&scenarioFile, "scenario", "", "Scenario file (YAML or JSON) describing workloads and phases")
+	flags.StringVar(&reportFile, "report", "", "File path to write reports of the scenario in JSON")
 
 	cmd.RunE = func(cmd *cobra.Command, urls []string) error {
 		if len(urls) == 0 {
@@ -73,6 +77,14 @@ func main() {
 			log.Panicf("Fail to decrypt KeyStore err=%+v", err)
 		}
 
+		if len(scenarioFile) > 0 {
+			scenario, err := LoadScenario(scenarioFile, nid, urls, concurrent)
+			if err != nil {
+				return err
+			}
+			return RunScenario(scenario, godWallet, reportFile)
+		}
+
 		var maker TransactionMaker
 		if len(scorePath) > 0 && len(params) > 0 {
 			maker = &CallMaker{
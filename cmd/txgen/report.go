@@ -0,0 +1,222 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PhaseStats collects results of transactions submitted in a phase.
+// Results are accounted to the phase in which the transaction was submitted
+// even though they are confirmed after the phase.
+type PhaseStats struct {
+	phase *PhaseConfig
+
+	lock      sync.Mutex
+	start     time.Time
+	end       time.Time
+	sent      int64
+	accepted  int64
+	poolFull  int64
+	succeeded int64
+	failed    int64
+	failures  map[string]int64
+	latencies []time.Duration
+}
+
+func NewPhaseStats(phase *PhaseConfig) *PhaseStats {
+	return &PhaseStats{
+		phase:    phase,
+		failures: make(map[string]int64),
+	}
+}
+
+func (s *PhaseStats) OnStart() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.start = time.Now()
+}
+
+func (s *PhaseStats) OnEnd() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.end = time.Now()
+}
+
+// OnSend is called on every trial to send a transaction.
+func (s *PhaseStats) OnSend() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.sent += 1
+}
+
+// OnPoolFull is called when the transaction is rejected by the full pool.
+func (s *PhaseStats) OnPoolFull() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.poolFull += 1
+}
+
+// OnAccept is called when the transaction is accepted by the node.
+func (s *PhaseStats) OnAccept() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.accepted += 1
+}
+
+// OnResult is called when the result of the transaction is available.
+// Failed transactions also have latencies, because they are confirmed.
+func (s *PhaseStats) OnResult(latency time.Duration, failure string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.latencies = append(s.latencies, latency)
+	if len(failure) == 0 {
+		s.succeeded += 1
+	} else {
+		s.failed += 1
+		s.failures[failure] += 1
+	}
+}
+
+// OnFailure is called when the transaction isn't confirmed, because of
+// the error on sending or waiting for the result.
+func (s *PhaseStats) OnFailure(reason string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.failed += 1
+	s.failures[reason] += 1
+}
+
+func (s *PhaseStats) Progress() (elapsed time.Duration, sent int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return time.Since(s.start), s.sent
+}
+
+type LatencyReport struct {
+	Min time.Duration `json:"min"`
+	Avg time.Duration `json:"avg"`
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P95 time.Duration `json:"p95"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+func percentile(sorted []time.Duration, p int) time.Duration {
+	idx := (len(sorted)*p+99)/100 - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
+
+func NewLatencyReport(latencies []time.Duration) *LatencyReport {
+	if len(latencies) == 0 {
+		return nil
+	}
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	var sum time.Duration
+	for _, l := range sorted {
+		sum += l
+	}
+	return &LatencyReport{
+		Min: sorted[0],
+		Avg: sum / time.Duration(len(sorted)),
+		P50: percentile(sorted, 50),
+		P90: percentile(sorted, 90),
+		P95: percentile(sorted, 95),
+		P99: percentile(sorted, 99),
+		Max: sorted[len(sorted)-1],
+	}
+}
+
+type PhaseReport struct {
+	Name      string           `json:"name"`
+	Duration  time.Duration    `json:"duration"`
+	TargetTPS string           `json:"targetTPS"`
+	Sent      int64            `json:"sent"`
+	Accepted  int64            `json:"accepted"`
+	PoolFull  int64            `json:"poolFull"`
+	SubmitTPS float64          `json:"submitTPS"`
+	Succeeded int64            `json:"succeeded"`
+	Failed    int64            `json:"failed"`
+	Latency   *LatencyReport   `json:"latency,omitempty"`
+	Failures  map[string]int64 `json:"failures,omitempty"`
+}
+
+func (s *PhaseStats) Report() *PhaseReport {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	duration := s.end.Sub(s.start)
+	failures := make(map[string]int64, len(s.failures))
+	for k, v := range s.failures {
+		failures[k] = v
+	}
+	return &PhaseReport{
+		Name:      s.phase.Name,
+		Duration:  duration,
+		TargetTPS: s.phase.TargetRate(),
+		Sent:      s.sent,
+		Accepted:  s.accepted,
+		PoolFull:  s.poolFull,
+		SubmitTPS: calcTPS(duration, s.accepted),
+		Succeeded: s.succeeded,
+		Failed:    s.failed,
+		Latency:   NewLatencyReport(s.latencies),
+		Failures:  failures,
+	}
+}
+
+func (r *PhaseReport) Print(w io.Writer) {
+	fmt.Fprintf(w, "[%s] duration=%s target_TPS=%s\n",
+		r.Name, r.Duration.Round(time.Millisecond), r.TargetTPS)
+	fmt.Fprintf(w, "  submission   : sent=%d accepted=%d pool_full=%d TPS=%.2f\n",
+		r.Sent, r.Accepted, r.PoolFull, r.SubmitTPS)
+	fmt.Fprintf(w, "  confirmation : succeeded=%d failed=%d\n",
+		r.Succeeded, r.Failed)
+	if l := r.Latency; l != nil {
+		fmt.Fprintf(w, "  latency      : min=%s avg=%s p50=%s p90=%s p95=%s p99=%s max=%s\n",
+			l.Min, l.Avg, l.P50, l.P90, l.P95, l.P99, l.Max)
+	}
+	if len(r.Failures) > 0 {
+		reasons := make([]string, 0, len(r.Failures))
+		for reason := range r.Failures {
+			reasons = append(reasons, reason)
+		}
+		sort.Slice(reasons, func(i, j int) bool {
+			ci, cj := r.Failures[reasons[i]], r.Failures[reasons[j]]
+			if ci != cj {
+				return ci > cj
+			}
+			return reasons[i] < reasons[j]
+		})
+		fmt.Fprintf(w, "  failures     :\n")
+		for _, reason := range reasons {
+			fmt.Fprintf(w, "    %8d %s\n", r.Failures[reason], reason)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	defaultScenarioWallets = 100
+	defaultResultTimeout   = 30 * time.Second
+)
+
+var (
+	defaultScenarioBalance = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	defaultDepositValue    = new(big.Int).Mul(big.NewInt(5000), big.NewInt(1e18))
+)
+
+// Scenario describes the load generated by txgen. It's written in YAML
+// (or JSON as a subset of YAML).
+//
+//	nid: 3
+//	targets: [ "http://localhost:9080/api/v3" ]
+//	wallets: 100
+//	workloads:
+//	  - { name: transfer, type: coin, weight: 8 }
+//	  - name: token
+//	    type: call
+//	    weight: 2
+//	    from: base
+//	    score: ./sample_token
+//	    installParams: { _name: "MyToken", _symbol: "MTK", _decimals: "0x12", _initialSupply: "0x3e8" }
+//	    method: transfer
+//	    params: { _to: "{{.to}}", _value: "{{printf \"0x%x\" .index}}" }
+//	phases:
+//	  - { name: ramp-up, duration: 30s, tps: 10, toTps: 200 }
+//	  - { name: steady, duration: 5m, tps: 200 }
+//	  - { name: transfer-only, duration: 1m, tps: 50, mix: { transfer: 1 } }
+//	  - { name: ramp-down, duration: 30s, tps: 200, toTps: 0 }
+type Scenario struct {
+	// NID is the network ID. --nid is used if it's not specified.
+	NID int64 `yaml:"nid"`
+	// Targets are URLs of nodes. URLs in arguments are used if it's empty.
+	Targets []string `yaml:"targets"`
+	// Concurrent is the number of senders for each target.
+	// --concurrent is used if it's not specified.
+	Concurrent int `yaml:"concurrent"`
+	// Wallets is the number of temporal wallets used as senders.
+	Wallets int `yaml:"wallets"`
+	// Balance is the amount of coins for each temporal wallet.
+	Balance string `yaml:"balance"`
+	// ResultTimeout is the maximum time to wait for the result of
+	// a transaction.
+	ResultTimeout time.Duration `yaml:"resultTimeout"`
+
+	Workloads []*WorkloadConfig `yaml:"workloads"`
+	Phases    []*PhaseConfig    `yaml:"phases"`
+}
+
+// WorkloadConfig describes a kind of transactions.
+type WorkloadConfig struct {
+	Name string `yaml:"name"`
+	// Type is one of coin, call, deploy and deposit.
+	Type string `yaml:"type"`
+	// Weight is the relative frequency of the workload in the mix.
+	Weight int `yaml:"weight"`
+	// From is the sender, "wallets" (default) for random temporal wallets,
+	// or "base" for the base account. Deposits are always sent by the base
+	// account as the owner of the contract.
+	From      string `yaml:"from"`
+	StepLimit int64  `yaml:"stepLimit"`
+	// Value is the amount of coins to transfer (coin), or the amount of
+	// deposit to add or withdraw (deposit).
+	Value string `yaml:"value"`
+
+	// Score is the path to the SCORE source (directory or jar), or
+	// the address of the deployed contract (call, deposit).
+	Score         string            `yaml:"score"`
+	InstallParams map[string]string `yaml:"installParams"`
+
+	// Method and Params are for call. Params are text/template with
+	// .index, .from, .to and .base.
+	Method string            `yaml:"method"`
+	Params map[string]string `yaml:"params"`
+
+	// Action is "add" (default) or "withdraw" for deposit.
+	Action string `yaml:"action"`
+}
+
+// PhaseConfig describes the rate and the mix of the load for a period.
+type PhaseConfig struct {
+	Name     string        `yaml:"name"`
+	Duration time.Duration `yaml:"duration"`
+	// TPS is the target submission rate at the start of the phase.
+	TPS float64 `yaml:"tps"`
+	// ToTPS is the target submission rate at the end of the phase.
+	// The rate changes linearly from TPS to ToTPS (e.g. ramp-up).
+	// It's same as TPS if it's not specified.
+	ToTPS *float64 `yaml:"toTps"`
+	// Mix overrides weights of workloads by their names.
+	Mix map[string]int `yaml:"mix"`
+}
+
+// RateAt returns the target submission rate after elapsed from the start.
+func (p *PhaseConfig) RateAt(elapsed time.Duration) float64 {
+	if p.ToTPS == nil || p.Duration <= 0 {
+		return p.TPS
+	}
+	return p.TPS + (*p.ToTPS-p.TPS)*float64(elapsed)/float64(p.Duration)
+}
+
+func (p *PhaseConfig) TargetRate() string {
+	if p.ToTPS == nil {
+		return fmt.Sprintf("%.2f", p.TPS)
+	}
+	return fmt.Sprintf("%.2f->%.2f", p.TPS, *p.ToTPS)
+}
+
+// WeightOf returns the weight of the workload in the phase.
+func (p *PhaseConfig) WeightOf(w *WorkloadConfig) int {
+	if p.Mix != nil {
+		return p.Mix[w.Name]
+	}
+	return w.Weight
+}
+
+func parseValue(s string, def *big.Int) (*big.Int, error) {
+	if len(s) == 0 {
+		return def, nil
+	}
+	v, ok := new(big.Int).SetString(s, 0)
+	if !ok || v.Sign() < 0 {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidValue(value=%s)", s)
+	}
+	return v, nil
+}
+
+func (s *Scenario) Validate() error {
+	if len(s.Workloads) == 0 {
+		return errors.IllegalArgumentError.New("NoWorkloads")
+	}
+	if len(s.Phases) == 0 {
+		return errors.IllegalArgumentError.New("NoPhases")
+	}
+	if s.Wallets < 2 {
+		return errors.IllegalArgumentError.Errorf("InvalidWallets(wallets=%d)", s.Wallets)
+	}
+	if _, err := parseValue(s.Balance, nil); err != nil {
+		return err
+	}
+	names := make(map[string]bool)
+	for i, w := range s.Workloads {
+		if len(w.Name) == 0 {
+			return errors.IllegalArgumentError.Errorf("NoWorkloadName(idx=%d)", i)
+		}
+		if names[w.Name] {
+			return errors.IllegalArgumentError.Errorf("DuplicateWorkload(name=%s)", w.Name)
+		}
+		names[w.Name] = true
+		if w.Weight < 0 {
+			return errors.IllegalArgumentError.Errorf("InvalidWeight(name=%s,weight=%d)", w.Name, w.Weight)
+		}
+		if w.From != "" && w.From != SenderWallets && w.From != SenderBase {
+			return errors.IllegalArgumentError.Errorf("InvalidFrom(name=%s,from=%s)", w.Name, w.From)
+		}
+		if _, err := parseValue(w.Value, nil); err != nil {
+			return err
+		}
+		switch w.Type {
+		case WorkloadCoin:
+		case WorkloadCall:
+			if len(w.Score) == 0 || len(w.Method) == 0 {
+				return errors.IllegalArgumentError.Errorf("NoScoreOrMethod(name=%s)", w.Name)
+			}
+		case WorkloadDeploy:
+			if len(w.Score) == 0 {
+				return errors.IllegalArgumentError.Errorf("NoScore(name=%s)", w.Name)
+			}
+		case WorkloadDeposit:
+			if len(w.Score) == 0 {
+				return errors.IllegalArgumentError.Errorf("NoScore(name=%s)", w.Name)
+			}
+			if w.Action != "" && w.Action != DepositAdd && w.Action != DepositWithdraw {
+				return errors.IllegalArgumentError.Errorf("InvalidAction(name=%s,action=%s)", w.Name, w.Action)
+			}
+		default:
+			return errors.IllegalArgumentError.Errorf("InvalidType(name=%s,type=%s)", w.Name, w.Type)
+		}
+	}
+	for i, p := range s.Phases {
+		if len(p.Name) == 0 {
+			p.Name = fmt.Sprintf("phase%d", i)
+		}
+		if p.Duration <= 0 {
+			return errors.IllegalArgumentError.Errorf("InvalidDuration(phase=%s)", p.Name)
+		}
+		if p.TPS < 0 || (p.ToTPS != nil && *p.ToTPS < 0) {
+			return errors.IllegalArgumentError.Errorf("InvalidTPS(phase=%s)", p.Name)
+		}
+		for name, w := range p.Mix {
+			if !names[name] {
+				return errors.IllegalArgumentError.Errorf("UnknownWorkload(phase=%s,name=%s)", p.Name, name)
+			}
+			if w < 0 {
+				return errors.IllegalArgumentError.Errorf("InvalidWeight(phase=%s,name=%s,weight=%d)", p.Name, name, w)
+			}
+		}
+		total := 0
+		for _, w := range s.Workloads {
+			total += p.WeightOf(w)
+		}
+		if total <= 0 {
+			return errors.IllegalArgumentError.Errorf("NoWeights(phase=%s)", p.Name)
+		}
+	}
+	return nil
+}
+
+// LoadScenario reads the scenario from the file. Zero values are replaced
+// with given defaults, then it's validated.
+func LoadScenario(file string, nid int64, urls []string, concurrent int) (*Scenario, error) {
+	bs, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	s := new(Scenario)
+	if err := yaml.Unmarshal(bs, s); err != nil {
+		return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidScenario(file=%s)", file)
+	}
+	if s.NID == 0 {
+		s.NID = nid
+	}
+	if len(s.Targets) == 0 {
+		s.Targets = urls
+	}
+	if s.Concurrent <= 0 {
+		s.Concurrent = concurrent
+	}
+	if s.Wallets == 0 {
+		s.Wallets = defaultScenarioWallets
+	}
+	if s.ResultTimeout <= 0 {
+		s.ResultTimeout = defaultResultTimeout
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
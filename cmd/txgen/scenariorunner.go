@@ -0,0 +1,393 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/icon-project/goloop/client"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+const (
+	resultPollingInterval = 100 * time.Millisecond
+	idleInterval          = 100 * time.Millisecond
+)
+
+var (
+	ErrResultTimeout = errors.New("ResultTimeout")
+)
+
+type scenarioJob struct {
+	stats    *PhaseStats
+	workload Workload
+}
+
+// workloadPicker picks workloads randomly by their weights in the phase.
+type workloadPicker struct {
+	workloads  []Workload
+	cumulative []int
+}
+
+func newWorkloadPicker(phase *PhaseConfig, cfgs []*WorkloadConfig, workloads []Workload) *workloadPicker {
+	cumulative := make([]int, len(cfgs))
+	total := 0
+	for i, cfg := range cfgs {
+		total += phase.WeightOf(cfg)
+		cumulative[i] = total
+	}
+	return &workloadPicker{
+		workloads:  workloads,
+		cumulative: cumulative,
+	}
+}
+
+func (p *workloadPicker) pick() Workload {
+	n := rand.Intn(p.cumulative[len(p.cumulative)-1])
+	return p.workloads[sort.SearchInts(p.cumulative, n+1)]
+}
+
+type ScenarioRunner struct {
+	scenario  *Scenario
+	env       *workloadEnv
+	workloads []Workload
+
+	index   int64
+	noWait  int32
+	waiters sync.WaitGroup
+}
+
+func NewScenarioRunner(s *Scenario, base module.Wallet) (*ScenarioRunner, error) {
+	env := &workloadEnv{
+		nid:  s.NID,
+		base: base,
+	}
+	workloads := make([]Workload, len(s.Workloads))
+	for i, cfg := range s.Workloads {
+		w, err := NewWorkload(env, cfg)
+		if err != nil {
+			return nil, err
+		}
+		workloads[i] = w
+	}
+	return &ScenarioRunner{
+		scenario:  s,
+		env:       env,
+		workloads: workloads,
+	}, nil
+}
+
+func (r *ScenarioRunner) prepare(client *Client) error {
+	balance, err := parseValue(r.scenario.Balance, defaultScenarioBalance)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[#] Prepare %d wallets", r.scenario.Wallets)
+	wallets := make([]module.Wallet, r.scenario.Wallets)
+	tids := make([]string, len(wallets))
+	for i := range wallets {
+		wallets[i] = wallet.New()
+		tx, err := makeCoinTransfer(r.env.nid, r.env.base, wallets[i].Address(), balance)
+		if err != nil {
+			return err
+		}
+		if tids[i], err = client.SendTx(tx); err != nil {
+			return err
+		}
+	}
+	for _, tid := range tids {
+		if txr, err := client.GetTxResult(tid, timeoutForCoinTransfer); err != nil {
+			return err
+		} else if txr.Status.Value != 1 {
+			return errors.Errorf("TransactionFails:failure=%+v", txr.Failure)
+		}
+	}
+	r.env.wallets = wallets
+
+	for _, w := range r.workloads {
+		log.Printf("[#] Prepare workload %s", w.Name())
+		if err := w.Prepare(client); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ScenarioRunner) send(c *Client, tx interface{}) (string, error) {
+	var txHash string
+	if _, err := c.Do("icx_sendTransaction", tx, &txHash); err != nil {
+		return "", err
+	}
+	return txHash, nil
+}
+
+func (r *ScenarioRunner) getResult(c *Client, tid string, deadline time.Time) (*TransactionResult, error) {
+	params := map[string]interface{}{
+		"txHash": tid,
+	}
+	for {
+		polling := atomic.LoadInt32(&r.noWait) != 0
+		method := "icx_waitTransactionResult"
+		if polling {
+			method = "icx_getTransactionResult"
+		}
+		result := new(TransactionResult)
+		_, err := c.Do(method, params, result)
+		if err == nil {
+			return result, nil
+		}
+		re, ok := err.(*jsonrpc.Error)
+		if !ok {
+			return nil, err
+		}
+		switch re.Code {
+		case jsonrpc.ErrorCodeMethodNotFound:
+			if polling {
+				return nil, err
+			}
+			// waitTransactionResult isn't enabled in the node
+			atomic.StoreInt32(&r.noWait, 1)
+			continue
+		case jsonrpc.ErrorCodeTimeout, jsonrpc.ErrorCodeSystemTimeout,
+			jsonrpc.ErrorCodePending, jsonrpc.ErrorCodeExecuting,
+			jsonrpc.ErrorCodeNotFound:
+			if time.Now().After(deadline) {
+				return nil, ErrResultTimeout
+			}
+			if polling {
+				time.Sleep(resultPollingInterval)
+			}
+			continue
+		default:
+			return nil, err
+		}
+	}
+}
+
+func reasonOf(err error) string {
+	if re, ok := err.(*jsonrpc.Error); ok {
+		return fmt.Sprintf("RPCError(code=%d,msg=%s)", re.Code, re.Message)
+	}
+	if errors.Is(err, syscall.ECONNRESET) {
+		return "ConnectionReset"
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return "ConnectionRefused"
+	}
+	return err.Error()
+}
+
+func (r *ScenarioRunner) waitResult(stats *PhaseStats, c *Client, tid string, start time.Time) {
+	defer r.waiters.Done()
+
+	result, err := r.getResult(c, tid, start.Add(r.scenario.ResultTimeout))
+	if err != nil {
+		stats.OnFailure(reasonOf(err))
+		return
+	}
+	latency := time.Since(start)
+	if result.Status.Value == 1 {
+		stats.OnResult(latency, "")
+	} else if result.Failure != nil {
+		stats.OnResult(latency, fmt.Sprintf("Failure(code=%d,msg=%s)",
+			result.Failure.Code.Value, result.Failure.Message))
+	} else {
+		stats.OnResult(latency, fmt.Sprintf("Failure(status=%d)", result.Status.Value))
+	}
+}
+
+func (r *ScenarioRunner) sendJobs(wg *sync.WaitGroup, jobs <-chan *scenarioJob, c, wc *Client) {
+	defer wg.Done()
+	for job := range jobs {
+		index := atomic.AddInt64(&r.index, 1) - 1
+		tx, err := job.workload.MakeOne(index)
+		if err != nil {
+			log.Printf("Fail to make transaction workload=%s err=%+v", job.workload.Name(), err)
+			continue
+		}
+		job.stats.OnSend()
+		start := time.Now()
+		tid, err := r.send(c, tx)
+		if err != nil {
+			if re, ok := err.(*jsonrpc.Error); ok && re.Code == jsonrpc.ErrorCodeTxPoolOverflow {
+				job.stats.OnPoolFull()
+			} else {
+				job.stats.OnFailure(reasonOf(err))
+			}
+			continue
+		}
+		job.stats.OnAccept()
+		r.waiters.Add(1)
+		go r.waitResult(job.stats, wc, tid, start)
+	}
+}
+
+func (r *ScenarioRunner) showProgress(stats *PhaseStats, done <-chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var lastSent int64
+	lastTime := time.Now()
+	for {
+		select {
+		case <-done:
+			fmt.Printf("%s", ClearLine)
+			return
+		case now := <-ticker.C:
+			elapsed, sent := stats.Progress()
+			fmt.Printf("%s[%s] elapsed [%6.1fs] target_TPS [%8.2f] current_TPS [%8.2f] TX=%6d \r",
+				ClearLine, stats.phase.Name, elapsed.Seconds(),
+				stats.phase.RateAt(elapsed), calcTPS(now.Sub(lastTime), sent-lastSent), sent)
+			lastSent, lastTime = sent, now
+		}
+	}
+}
+
+// runPhase dispatches jobs at the target rate of the phase. If all senders
+// are busy, dispatching is delayed, so the submission rate may be lower than
+// the target.
+func (r *ScenarioRunner) runPhase(phase *PhaseConfig, stats *PhaseStats, jobs chan<- *scenarioJob) {
+	picker := newWorkloadPicker(phase, r.scenario.Workloads, r.workloads)
+
+	stats.OnStart()
+	defer stats.OnEnd()
+
+	done := make(chan struct{})
+	defer close(done)
+	go r.showProgress(stats, done)
+
+	start := time.Now()
+	deadline := start.Add(phase.Duration)
+	end := time.NewTimer(phase.Duration)
+	defer end.Stop()
+
+	next := start
+	for {
+		now := time.Now()
+		if !now.Before(deadline) {
+			return
+		}
+		if wait := next.Sub(now); wait > 0 {
+			if remain := deadline.Sub(now); wait > remain {
+				wait = remain
+			}
+			time.Sleep(wait)
+			continue
+		}
+		rate := phase.RateAt(now.Sub(start))
+		if rate <= 0 {
+			next = now.Add(idleInterval)
+			continue
+		}
+		select {
+		case jobs <- &scenarioJob{stats: stats, workload: picker.pick()}:
+		case <-end.C:
+			return
+		}
+		next = next.Add(time.Duration(float64(time.Second) / rate))
+		if current := time.Now(); current.Sub(next) > time.Second {
+			next = current
+		}
+	}
+}
+
+// Run runs phases of the scenario in order, and returns reports of phases
+// after all results are resolved.
+func (r *ScenarioRunner) Run() ([]*PhaseReport, error) {
+	s := r.scenario
+	c := &Client{client.NewJsonRpcClient(&http.Client{}, s.Targets[0])}
+	if err := r.prepare(c); err != nil {
+		log.Printf("Fail to prepare err=%+v", err)
+		return nil, err
+	}
+
+	iconOpts := jsonrpc.IconOptions{}
+	iconOpts.SetInt(jsonrpc.IconOptionsTimeout, int64(s.ResultTimeout/time.Millisecond))
+	jobs := make(chan *scenarioJob)
+	var senders sync.WaitGroup
+	for _, url := range s.Targets {
+		wc := &Client{client.NewJsonRpcClient(&http.Client{}, url)}
+		wc.CustomHeader[jsonrpc.HeaderKeyIconOptions] = iconOpts.ToHeaderValue()
+		for i := 0; i < s.Concurrent; i++ {
+			senders.Add(1)
+			c := &Client{client.NewJsonRpcClient(&http.Client{}, url)}
+			go r.sendJobs(&senders, jobs, c, wc)
+		}
+	}
+
+	stats := make([]*PhaseStats, len(s.Phases))
+	for i, phase := range s.Phases {
+		log.Printf("[#] Start phase %s duration=%s target_TPS=%s",
+			phase.Name, phase.Duration, phase.TargetRate())
+		stats[i] = NewPhaseStats(phase)
+		r.runPhase(phase, stats[i], jobs)
+	}
+	close(jobs)
+	senders.Wait()
+
+	log.Println("[#] Wait for results of transactions")
+	r.waiters.Wait()
+
+	reports := make([]*PhaseReport, len(stats))
+	for i, s := range stats {
+		reports[i] = s.Report()
+	}
+	return reports, nil
+}
+
+// RunScenario runs the scenario, then prints reports of phases.
+// Reports are also written in JSON to reportFile if it's specified
+// (durations are in nanoseconds).
+func RunScenario(s *Scenario, base module.Wallet, reportFile string) error {
+	runner, err := NewScenarioRunner(s, base)
+	if err != nil {
+		return err
+	}
+	reports, err := runner.Run()
+	if err != nil {
+		return err
+	}
+	for _, report := range reports {
+		report.Print(os.Stdout)
+	}
+	if len(reportFile) > 0 {
+		fd, err := os.Create(reportFile)
+		if err != nil {
+			return err
+		}
+		defer fd.Close()
+		enc := json.NewEncoder(fd)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reports)
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+	"text/template"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	WorkloadCoin    = "coin"
+	WorkloadCall    = "call"
+	WorkloadDeploy  = "deploy"
+	WorkloadDeposit = "deposit"
+
+	SenderWallets = "wallets"
+	SenderBase    = "base"
+
+	DepositAdd      = "add"
+	DepositWithdraw = "withdraw"
+
+	stepLimitForDeposit = 100000
+)
+
+var (
+	coinValueForTransfer = big.NewInt(10)
+)
+
+// Workload makes transactions of a WorkloadConfig.
+type Workload interface {
+	Name() string
+	Prepare(client *Client) error
+	MakeOne(index int64) (interface{}, error)
+}
+
+type workloadEnv struct {
+	nid     int64
+	base    module.Wallet
+	wallets []module.Wallet
+}
+
+func (e *workloadEnv) sender(from string) module.Wallet {
+	if from == SenderBase {
+		return e.base
+	}
+	return e.wallets[rand.Intn(len(e.wallets))]
+}
+
+// receiver returns a random temporal wallet other than from.
+func (e *workloadEnv) receiver(from module.Wallet) module.Wallet {
+	idx := rand.Intn(len(e.wallets))
+	if e.wallets[idx] == from {
+		idx = (idx + 1) % len(e.wallets)
+	}
+	return e.wallets[idx]
+}
+
+func NewWorkload(env *workloadEnv, cfg *WorkloadConfig) (Workload, error) {
+	switch cfg.Type {
+	case WorkloadCoin:
+		value, err := parseValue(cfg.Value, coinValueForTransfer)
+		if err != nil {
+			return nil, err
+		}
+		return &coinWorkload{env: env, cfg: cfg, value: value}, nil
+	case WorkloadCall:
+		return &callWorkload{env: env, cfg: cfg}, nil
+	case WorkloadDeploy:
+		return &deployWorkload{env: env, cfg: cfg}, nil
+	case WorkloadDeposit:
+		value, err := parseValue(cfg.Value, nil)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Action != DepositWithdraw && value == nil {
+			value = defaultDepositValue
+		}
+		return &depositWorkload{env: env, cfg: cfg, value: value}, nil
+	default:
+		return nil, errors.IllegalArgumentError.Errorf("InvalidType(type=%s)", cfg.Type)
+	}
+}
+
+func stepLimitOf(cfg *WorkloadConfig, def int64) int64 {
+	if cfg.StepLimit > 0 {
+		return cfg.StepLimit
+	}
+	return def
+}
+
+func installParamsOf(cfg *WorkloadConfig) map[string]string {
+	if cfg.InstallParams == nil {
+		return make(map[string]string)
+	}
+	return cfg.InstallParams
+}
+
+// prepareContract deploys the contract at the path by the base account,
+// or parses the address of the contract already deployed.
+func prepareContract(client *Client, env *workloadEnv, cfg *WorkloadConfig) (module.Address, error) {
+	if _, err := os.Stat(cfg.Score); err != nil {
+		addr := new(common.Address)
+		if err := addr.SetString(cfg.Score); err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err,
+				"InvalidScore(name=%s,score=%s)", cfg.Name, cfg.Score)
+		}
+		return addr, nil
+	}
+	deploy, err := makeDeploy(env.nid, env.base, cfg.Score, installParamsOf(cfg))
+	if err != nil {
+		return nil, err
+	}
+	r, err := client.SendTxAndGetResult(deploy, timeoutForDeploy)
+	if err != nil {
+		return nil, err
+	}
+	if r.Status.Value != 1 {
+		return nil, errors.Errorf("DeployFailed(name=%s,failure=%+v)", cfg.Name, r.Failure)
+	}
+	return r.SCOREAddress, nil
+}
+
+type coinWorkload struct {
+	env   *workloadEnv
+	cfg   *WorkloadConfig
+	value *big.Int
+}
+
+func (w *coinWorkload) Name() string {
+	return w.cfg.Name
+}
+
+func (w *coinWorkload) Prepare(client *Client) error {
+	return nil
+}
+
+func (w *coinWorkload) MakeOne(index int64) (interface{}, error) {
+	from := w.env.sender(w.cfg.From)
+	to := w.env.receiver(from)
+	return makeCoinTransfer(w.env.nid, from, to.Address(), w.value)
+}
+
+type callWorkload struct {
+	env       *workloadEnv
+	cfg       *WorkloadConfig
+	contract  module.Address
+	templates map[string]*template.Template
+}
+
+func (w *callWorkload) Name() string {
+	return w.cfg.Name
+}
+
+func (w *callWorkload) Prepare(client *Client) error {
+	ts := make(map[string]*template.Template)
+	for n, p := range w.cfg.Params {
+		if tmpl, err := template.New(n).Parse(p); err != nil {
+			return err
+		} else {
+			ts[n] = tmpl
+		}
+	}
+	w.templates = ts
+
+	contract, err := prepareContract(client, w.env, w.cfg)
+	if err != nil {
+		return err
+	}
+	w.contract = contract
+	return nil
+}
+
+func (w *callWorkload) MakeOne(index int64) (interface{}, error) {
+	from := w.env.sender(w.cfg.From)
+	context := map[string]interface{}{
+		"index": index,
+		"from":  from.Address(),
+		"to":    w.env.receiver(from).Address(),
+		"base":  w.env.base.Address(),
+	}
+	params := make(map[string]string)
+	buffer := bytes.NewBuffer(nil)
+	for n, t := range w.templates {
+		buffer.Reset()
+		if err := t.Execute(buffer, context); err != nil {
+			return nil, err
+		}
+		params[n] = buffer.String()
+	}
+	return makeCallTx(w.env.nid, from, w.contract,
+		stepLimitOf(w.cfg, stepLimitForTokenTransfer), w.cfg.Method, params)
+}
+
+type deployWorkload struct {
+	env         *workloadEnv
+	cfg         *WorkloadConfig
+	contentType string
+	content     string
+}
+
+func (w *deployWorkload) Name() string {
+	return w.cfg.Name
+}
+
+func (w *deployWorkload) Prepare(client *Client) error {
+	contentType, content, err := makeDeployContent(w.cfg.Score)
+	if err != nil {
+		return err
+	}
+	w.contentType, w.content = contentType, content
+	return nil
+}
+
+func (w *deployWorkload) MakeOne(index int64) (interface{}, error) {
+	return makeDeployTx(w.env.nid, w.env.sender(w.cfg.From),
+		stepLimitOf(w.cfg, stepLimitForDeploy), w.contentType, w.content,
+		installParamsOf(w.cfg))
+}
+
+type depositWorkload struct {
+	env      *workloadEnv
+	cfg      *WorkloadConfig
+	value    *big.Int
+	contract module.Address
+}
+
+func (w *depositWorkload) Name() string {
+	return w.cfg.Name
+}
+
+func (w *depositWorkload) Prepare(client *Client) error {
+	contract, err := prepareContract(client, w.env, w.cfg)
+	if err != nil {
+		return err
+	}
+	w.contract = contract
+	return nil
+}
+
+func (w *depositWorkload) MakeOne(index int64) (interface{}, error) {
+	action := w.cfg.Action
+	if len(action) == 0 {
+		action = DepositAdd
+	}
+	return makeDepositTx(w.env.nid, w.env.base, w.contract,
+		stepLimitOf(w.cfg, stepLimitForDeposit), action, w.value)
+}
+
+func makeDepositTx(nid int64, from module.Wallet, contract module.Address,
+	stepLimit int64, action string, value *big.Int,
+) (interface{}, error) {
+	data := map[string]interface{}{
+		"action": action,
+	}
+	tx := map[string]interface{}{
+		"version":   "0x3",
+		"from":      from.Address(),
+		"to":        contract,
+		"nid":       fmt.Sprintf("0x%x", nid),
+		"stepLimit": fmt.Sprintf("0x%x", stepLimit),
+		"timestamp": TimeStampNow(),
+		"dataType":  "deposit",
+		"data":      data,
+	}
+	if action == DepositAdd {
+		tx["value"] = fmt.Sprintf("0x%x", value)
+	} else if value != nil {
+		data["amount"] = fmt.Sprintf("0x%x", value)
+	}
+	if err := SignTx(from, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
@@ -17,12 +17,43 @@ type LayerDB interface {
 	Unwrap() Database
 }
 
+// Batch collects changes of buckets in the database, and writes them
+// at once.
+type Batch interface {
+	Set(id BucketID, key []byte, value []byte) error
+	Delete(id BucketID, key []byte) error
+	Write() error
+}
+
+// Batcher is implemented by Database supporting Batch.
+type Batcher interface {
+	NewBatch() Batch
+}
+
+// batcherOf returns Batcher of the database without flags. It returns nil
+// if it's not supported.
+func batcherOf(database Database) Batcher {
+	for {
+		switch d := database.(type) {
+		case Batcher:
+			return d
+		case *databaseContext:
+			database = d.Database
+		default:
+			return nil
+		}
+	}
+}
+
 type BackendType string
 
 type dbCreator func(name string, dir string) (Database, error)
 
 var backends = map[BackendType]dbCreator{}
 
+// readOnlyBackends has creators of backends supporting read-only mode.
+var readOnlyBackends = map[BackendType]dbCreator{}
+
 func registerDBCreator(backend BackendType, creator dbCreator, force bool) {
 	_, ok := backends[backend]
 	if !force && ok {
@@ -31,6 +62,14 @@ func registerDBCreator(backend BackendType, creator dbCreator, force bool) {
 	backends[backend] = creator
 }
 
+func registerReadOnlyDBCreator(backend BackendType, creator dbCreator, force bool) {
+	_, ok := readOnlyBackends[backend]
+	if !force && ok {
+		return
+	}
+	readOnlyBackends[backend] = creator
+}
+
 func RegisteredBackendTypes() []string {
 	l := make([]string, 0)
 	for k := range backends {
@@ -40,10 +79,30 @@ func RegisteredBackendTypes() []string {
 	return l
 }
 
+func IsRegisteredBackendType(dbtype string) bool {
+	_, ok := backends[BackendType(dbtype)]
+	return ok
+}
+
 func Open(dir, dbtype, name string) (Database, error) {
 	return openDatabase(BackendType(dbtype), name, dir)
 }
 
+// OpenReadOnly opens the existing database in read-only mode, so
+// modifications of it fail. It returns an error if the backend doesn't
+// support read-only mode.
+func OpenReadOnly(dir, dbtype, name string) (Database, error) {
+	backend := BackendType(dbtype)
+	dbCreator, ok := readOnlyBackends[backend]
+	if !ok {
+		if _, ok := backends[backend]; ok {
+			return nil, errors.UnsupportedError.Errorf("ReadOnlyNotSupported(type=%s)", backend)
+		}
+		return nil, errors.Errorf("UnknownBackend(type=%s)", backend)
+	}
+	return dbCreator(name, dir)
+}
+
 func openDatabase(backend BackendType, name string, dir string) (Database, error) {
 	dbCreator, ok := backends[backend]
 	if !ok {
@@ -67,6 +126,17 @@ func GetSupportedTypes() []string {
 	return types
 }
 
+// GetReadOnlySupportedTypes returns types of backends supporting
+// OpenReadOnly.
+func GetReadOnlySupportedTypes() []string {
+	types := make([]string, 0, len(readOnlyBackends))
+	for be := range readOnlyBackends {
+		types = append(types, string(be))
+	}
+	sort.Strings(types)
+	return types
+}
+
 type errorBucket struct {
 	error
 }
@@ -258,3 +258,44 @@ func TestDatabase_OperationAfterClose(t *testing.T) {
 		})
 	}
 }
+
+func testDatabase_OpenReadOnly(t *testing.T, backend BackendType) {
+	dir := t.TempDir()
+	key := []byte("hello")
+	value := []byte("world")
+
+	_, err := OpenReadOnly(dir, string(backend), "test")
+	assert.Error(t, err)
+
+	testDB, err := Open(dir, string(backend), "test")
+	assert.NoError(t, err)
+	bucket, err := testDB.GetBucket(MerkleTrie)
+	assert.NoError(t, err)
+	assert.NoError(t, bucket.Set(key, value))
+	assert.NoError(t, testDB.Close())
+
+	testDB, err = OpenReadOnly(dir, string(backend), "test")
+	assert.NoError(t, err)
+	defer testDB.Close()
+
+	bucket, err = testDB.GetBucket(MerkleTrie)
+	assert.NoError(t, err)
+	stored, err := bucket.Get(key)
+	assert.NoError(t, err)
+	assert.Equal(t, value, stored)
+
+	assert.Error(t, bucket.Set(key, []byte("world2")))
+	assert.Error(t, bucket.Delete(key))
+}
+
+func TestDatabase_OpenReadOnly(t *testing.T) {
+	for name := range readOnlyBackends {
+		t.Run(string(name), func(t *testing.T) {
+			testDatabase_OpenReadOnly(t, name)
+		})
+	}
+	_, err := OpenReadOnly(t.TempDir(), string(MapDBBackend), "test")
+	assert.Error(t, err)
+	_, err = OpenReadOnly(t.TempDir(), "unknown", "test")
+	assert.Error(t, err)
+}
@@ -15,6 +15,12 @@ func init() {
 		return NewGoLevelDB(name, dir)
 	}
 	registerDBCreator(GoLevelDBBackend, dbCreator, false)
+	registerReadOnlyDBCreator(GoLevelDBBackend, func(name string, dir string) (Database, error) {
+		return NewGoLevelDBWithOpts(name, dir, &opt.Options{
+			ReadOnly:       true,
+			ErrorIfMissing: true,
+		})
+	}, false)
 }
 
 func NewGoLevelDB(name string, dir string) (*GoLevelDB, error) {
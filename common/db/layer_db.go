@@ -34,6 +34,7 @@ func (l *layerBucketItems) MoveToBack(element *list.Element) {
 
 type layerBucket struct {
 	lock sync.Mutex
+	id   BucketID
 	data map[string]*list.Element
 	list *layerBucketItems
 	real Bucket
@@ -128,6 +129,7 @@ func (ldb *layerDB) GetBucket(id BucketID) (Bucket, error) {
 		return realbk, nil
 	}
 	bk := &layerBucket{
+		id:   id,
 		data: make(map[string]*list.Element),
 		list: &ldb.list,
 		real: realbk,
@@ -159,16 +161,22 @@ func (ldb *layerDB) Flush(write bool) error {
 	}()
 
 	if write {
-		for element := ldb.list.Front() ; element != nil ; element = element.Next() {
-			item := element.Value.(*layerBucketItem)
-
-			if item.value != nil {
-				if err := item.bk.real.Set([]byte(item.key), item.value ); err != nil {
-					return err
-				}
-			} else {
-				if err := item.bk.real.Delete([]byte(item.key)); err != nil {
-					return err
+		if batcher := batcherOf(ldb.real); batcher != nil {
+			if err := ldb.writeBatch(batcher.NewBatch()); err != nil {
+				return err
+			}
+		} else {
+			for element := ldb.list.Front() ; element != nil ; element = element.Next() {
+				item := element.Value.(*layerBucketItem)
+
+				if item.value != nil {
+					if err := item.bk.real.Set([]byte(item.key), item.value ); err != nil {
+						return err
+					}
+				} else {
+					if err := item.bk.real.Delete([]byte(item.key)); err != nil {
+						return err
+					}
 				}
 			}
 		}
@@ -185,6 +193,24 @@ func (ldb *layerDB) Flush(write bool) error {
 	return nil
 }
 
+// writeBatch writes all the changes at once with the batch.
+func (ldb *layerDB) writeBatch(batch Batch) error {
+	for element := ldb.list.Front(); element != nil; element = element.Next() {
+		item := element.Value.(*layerBucketItem)
+
+		var err error
+		if item.value != nil {
+			err = batch.Set(item.bk.id, []byte(item.key), item.value)
+		} else {
+			err = batch.Delete(item.bk.id, []byte(item.key))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
 func (ldb *layerDB) Close() error {
 	return nil
 }
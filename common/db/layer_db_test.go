@@ -147,3 +147,45 @@ func TestLayerDB_FlushInOrder(t *testing.T) {
 
 	assert.Equal(t, Unwrap(ldb), dbase)
 }
+
+func TestLayerDB_FlushWithBatch(t *testing.T) {
+	pdb, err := NewPebbleDB("test", t.TempDir())
+	assert.NoError(t, err)
+	defer pdb.Close()
+
+	dbase := WithFlags(pdb, Flags{"test": true})
+	assert.Equal(t, Batcher(pdb), batcherOf(dbase))
+
+	ldb := NewLayerDB(dbase)
+	assert.Nil(t, batcherOf(ldb))
+
+	bk, err := pdb.GetBucket(BytesByHash)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Set([]byte("key1"), []byte("value1")))
+
+	runTestScenario(t, ldb, []testOperation{
+		{BytesByHash, []byte("key1"), nil},
+		{BytesByHash, []byte("key2"), []byte("value2")},
+		{ChainProperty, []byte("key3"), []byte("value3")},
+	})
+
+	// nothing is written before flush
+	value, err := bk.Get([]byte("key2"))
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	assert.NoError(t, ldb.Flush(true))
+
+	value, err = bk.Get([]byte("key1"))
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+	value, err = bk.Get([]byte("key2"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value2"), value)
+
+	bk2, err := pdb.GetBucket(ChainProperty)
+	assert.NoError(t, err)
+	value, err = bk2.Get([]byte("key3"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value3"), value)
+}
@@ -0,0 +1,249 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+)
+
+const PebbleDBBackend BackendType = "pebble"
+
+func init() {
+	dbCreator := func(name string, dir string) (Database, error) {
+		return NewPebbleDB(name, dir)
+	}
+	registerDBCreator(PebbleDBBackend, dbCreator, false)
+	registerReadOnlyDBCreator(PebbleDBBackend, func(name string, dir string) (Database, error) {
+		return NewPebbleDBWithOpts(name, dir, &pebble.Options{
+			ReadOnly:         true,
+			ErrorIfNotExists: true,
+		})
+	}, false)
+}
+
+func NewPebbleDB(name string, dir string) (*PebbleDB, error) {
+	return NewPebbleDBWithOpts(name, dir, nil)
+}
+
+func NewPebbleDBWithOpts(name string, dir string, o *pebble.Options) (*PebbleDB, error) {
+	dbPath := filepath.Join(dir, name)
+	db, err := pebble.Open(dbPath, o)
+	if err != nil {
+		return nil, err
+	}
+	database := &PebbleDB{
+		db:      db,
+		buckets: make(map[BucketID]Bucket),
+	}
+	return database, nil
+}
+
+//----------------------------------------
+// Database
+
+var _ Database = (*PebbleDB)(nil)
+var _ Batcher = (*PebbleDB)(nil)
+
+type PebbleDB struct {
+	lock    sync.RWMutex
+	bkLock  sync.Mutex
+	db      *pebble.DB
+	buckets map[BucketID]Bucket
+}
+
+func (db *PebbleDB) GetBucket(id BucketID) (Bucket, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, pebble.ErrClosed
+	}
+
+	db.bkLock.Lock()
+	defer db.bkLock.Unlock()
+
+	if bk, ok := db.buckets[id]; ok {
+		return bk, nil
+	} else {
+		bk = &pebbleBucket{
+			id: id,
+			db: db,
+		}
+		db.buckets[id] = bk
+		return bk, nil
+	}
+}
+
+func (db *PebbleDB) NewBatch() Batch {
+	return &pebbleBatch{db: db}
+}
+
+func (db *PebbleDB) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return pebble.ErrClosed
+	}
+	if err := db.db.Close(); err != nil {
+		return err
+	}
+	db.db = nil
+	return nil
+}
+
+func (db *PebbleDB) getValue(k []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, pebble.ErrClosed
+	}
+	value, closer, err := db.db.Get(k)
+	if err == pebble.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	// the value is valid only until the closer is closed.
+	v := make([]byte, len(value))
+	copy(v, value)
+	return v, nil
+}
+
+func (db *PebbleDB) hasValue(k []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return false, pebble.ErrClosed
+	}
+	_, closer, err := db.db.Get(k)
+	if err == pebble.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	closer.Close()
+	return true, nil
+}
+
+func (db *PebbleDB) setValue(k, v []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return pebble.ErrClosed
+	}
+	return db.db.Set(k, v, pebble.NoSync)
+}
+
+func (db *PebbleDB) deleteValue(k []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return pebble.ErrClosed
+	}
+	return db.db.Delete(k, pebble.NoSync)
+}
+
+func (db *PebbleDB) writeBatch(ops []pebbleBatchOp) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return pebble.ErrClosed
+	}
+	batch := db.db.NewBatch()
+	defer batch.Close()
+	for _, op := range ops {
+		var err error
+		if op.value != nil {
+			err = batch.Set(op.key, op.value, nil)
+		} else {
+			err = batch.Delete(op.key, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return batch.Commit(pebble.NoSync)
+}
+
+//----------------------------------------
+// Bucket
+
+var _ Bucket = (*pebbleBucket)(nil)
+
+type pebbleBucket struct {
+	id BucketID
+	db *PebbleDB
+}
+
+func (bucket *pebbleBucket) Get(key []byte) ([]byte, error) {
+	return bucket.db.getValue(internalKey(bucket.id, key))
+}
+
+func (bucket *pebbleBucket) Has(key []byte) (bool, error) {
+	return bucket.db.hasValue(internalKey(bucket.id, key))
+}
+
+func (bucket *pebbleBucket) Set(key []byte, value []byte) error {
+	return bucket.db.setValue(internalKey(bucket.id, key), value)
+}
+
+func (bucket *pebbleBucket) Delete(key []byte) error {
+	return bucket.db.deleteValue(internalKey(bucket.id, key))
+}
+
+//----------------------------------------
+// Batch
+
+type pebbleBatchOp struct {
+	key   []byte
+	value []byte
+}
+
+// pebbleBatch collects operations, and writes them at once with
+// a pebble.Batch on Write. Nil value of the operation means deletion.
+type pebbleBatch struct {
+	db  *PebbleDB
+	ops []pebbleBatchOp
+}
+
+func (b *pebbleBatch) Set(id BucketID, key []byte, value []byte) error {
+	if value == nil {
+		value = []byte{}
+	}
+	b.ops = append(b.ops, pebbleBatchOp{internalKey(id, key), value})
+	return nil
+}
+
+func (b *pebbleBatch) Delete(id BucketID, key []byte) error {
+	b.ops = append(b.ops, pebbleBatchOp{internalKey(id, key), nil})
+	return nil
+}
+
+func (b *pebbleBatch) Write() error {
+	return b.db.writeBatch(b.ops)
+}
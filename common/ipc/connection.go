@@ -2,10 +2,12 @@ package ipc
 
 import (
 	"bufio"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
 	"sync"
+	"time"
 
 	"github.com/icon-project/goloop/common/codec"
 )
@@ -19,6 +21,7 @@ type Connection interface {
 	SendAndReceive(msg uint, data interface{}, buf interface{}) error
 	SetHandler(msg uint, handler MessageHandler)
 	HandleMessage() error
+	SetDeadline(t time.Time) error
 	Close() error
 }
 
@@ -138,6 +141,10 @@ func (c *connection) SetHandler(msg uint, handler MessageHandler) {
 	c.handler[msg] = handler
 }
 
+func (c *connection) SetDeadline(t time.Time) error {
+	return c.conn.SetDeadline(t)
+}
+
 func (c *connection) Close() error {
 	c.closed = true
 	return c.conn.Close()
@@ -150,3 +157,23 @@ func Dial(network, address string) (Connection, error) {
 		return connectionFromConn(conn), nil
 	}
 }
+
+// DialTimeout connects to the address with the timeout.
+func DialTimeout(network, address string, timeout time.Duration) (Connection, error) {
+	if conn, err := net.DialTimeout(network, address, timeout); err != nil {
+		return nil, err
+	} else {
+		return connectionFromConn(conn), nil
+	}
+}
+
+// DialTLS connects to the address with TLS. It can be used for unix domain
+// sockets as well. Zero timeout means no timeout.
+func DialTLS(network, address string, config *tls.Config, timeout time.Duration) (Connection, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	if conn, err := tls.DialWithDialer(dialer, network, address, config); err != nil {
+		return nil, err
+	} else {
+		return connectionFromConn(conn), nil
+	}
+}
@@ -1,6 +1,7 @@
 package ipc
 
 import (
+	"crypto/tls"
 	"io"
 	"net"
 	"os"
@@ -28,8 +29,9 @@ type Server interface {
 }
 
 type server struct {
-	listener net.Listener
-	handler  ConnectionHandler
+	listener  net.Listener
+	handler   ConnectionHandler
+	tlsConfig *tls.Config
 }
 
 func (s *server) Addr() net.Addr {
@@ -54,6 +56,9 @@ func (s *server) Listen(network, address string) error {
 	if ulsr, ok := listener.(*net.UnixListener); ok {
 		ulsr.SetUnlinkOnClose(true)
 	}
+	if s.tlsConfig != nil {
+		listener = tls.NewListener(listener, s.tlsConfig)
+	}
 	s.listener = listener
 	return nil
 }
@@ -108,3 +113,8 @@ func (s *server) Close() error {
 func NewServer() Server {
 	return new(server)
 }
+
+// NewTLSServer returns the server accepting connections with TLS.
+func NewTLSServer(config *tls.Config) Server {
+	return &server{tlsConfig: config}
+}
@@ -311,7 +311,7 @@ func (n *branch) getProof(m *mpt, keys []byte, proofs [][]byte) (nn node, proof
 	}
 	child := n.children[keys[0]]
 	if child == nil {
-		return n, nil, nil
+		return n, proofs, common.ErrNotFound
 	}
 	nchild, proofs, err := child.getProof(m, keys[1:], proofs)
 	if nchild != child {
@@ -239,13 +239,13 @@ func (n *extension) getProof(m *mpt, keys []byte, proofs [][]byte) (node, [][]by
 		return n, nil, fmt.Errorf("IllegaState %s", n.toString())
 	}
 
-	cnt, _ := compareKeys(n.keys, keys)
-	if cnt < len(n.keys) {
-		return n, nil, nil
-	}
 	if n.hashValue != nil {
 		proofs = append(proofs, n.serialized)
 	}
+	cnt, _ := compareKeys(n.keys, keys)
+	if cnt < len(n.keys) {
+		return n, proofs, common.ErrNotFound
+	}
 	next, proofs, err := n.next.getProof(m, keys[cnt:], proofs)
 	if next != n.next {
 		n.next = next
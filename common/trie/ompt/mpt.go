@@ -455,11 +455,31 @@ func (m *mpt) Filter(prefix []byte) trie.IteratorForObject {
 }
 
 func (m *mpt) GetProof(k []byte) [][]byte {
+	proofs, err := m.getProof(k)
+	if err != nil {
+		return nil
+	}
+	return proofs
+}
+
+// GetProofOrExclusion returns the proof of the value for the key. If there
+// is no value, it returns the proof of the exclusion, which is the path to
+// the node where the key diverges, then Prove returns common.ErrNotFound
+// for the proof.
+func (m *mpt) GetProofOrExclusion(k []byte) [][]byte {
+	proofs, err := m.getProof(k)
+	if err != nil && err != common.ErrNotFound {
+		return nil
+	}
+	return proofs
+}
+
+func (m *mpt) getProof(k []byte) ([][]byte, error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
 	if m.root == nil {
-		return nil
+		return nil, common.ErrNotFound
 	}
 
 	// make sure that it's hashed.
@@ -473,13 +493,10 @@ func (m *mpt) GetProof(k []byte) [][]byte {
 	if root != m.root {
 		m.root = root
 	}
-	if err != nil {
-		if debugPrint {
-			log.Printf("Fail to get proof for [%x]", k)
-		}
-		return nil
+	if err != nil && debugPrint {
+		log.Printf("Fail to get proof for [%x]", k)
 	}
-	return proofs
+	return proofs, err
 }
 
 func (m *mpt) Prove(k []byte, proofs [][]byte) (trie.Object, error) {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/merkle"
 
 	"github.com/icon-project/goloop/common/db"
@@ -293,6 +294,57 @@ func Test_GetPoof(t *testing.T) {
 // 	}
 // }
 
+func Test_GetProofOrExclusion(t *testing.T) {
+	value := func(k []byte) []byte {
+		return bytes.Repeat(k, 40/len(k))
+	}
+	keys := [][]byte{
+		{0x01, 0x22},
+		{0x01, 0x23, 0x44},
+		{0x01, 0x23, 0x45},
+		{0x45, 0x67},
+	}
+	d1 := db.NewMapDB()
+	m1 := NewMPTForBytes(d1, nil)
+	assert.Nil(t, m1.GetSnapshot().GetProofOrExclusion([]byte{0x01}))
+	for _, k := range keys {
+		_, err := m1.Set(k, value(k))
+		assert.NoError(t, err)
+	}
+	s1 := m1.GetSnapshot()
+	assert.NoError(t, s1.Flush())
+	h := s1.Hash()
+
+	for _, k := range keys {
+		proof := s1.GetProofOrExclusion(k)
+		assert.Equal(t, s1.GetProof(k), proof)
+		v, err := NewMPTForBytes(db.NewMapDB(), h).Prove(k, proof)
+		assert.NoError(t, err)
+		assert.Equal(t, value(k), v)
+	}
+
+	absents := []struct {
+		k     []byte
+		other []byte
+	}{
+		{[]byte{0x02}, keys[3]},             // no child in the branch
+		{[]byte{0x01, 0x24}, keys[3]},       // diverges in the extension
+		{[]byte{0x01, 0x23, 0x46}, keys[3]}, // no child in the branch under the extension
+		{[]byte{0x45, 0x68}, keys[0]},       // diverges in the leaf
+	}
+	for _, a := range absents {
+		assert.Nil(t, s1.GetProof(a.k))
+		proof := s1.GetProofOrExclusion(a.k)
+		assert.NotEmpty(t, proof)
+		_, err := NewMPTForBytes(db.NewMapDB(), h).Prove(a.k, proof)
+		assert.ErrorIs(t, err, common.ErrNotFound, "key=%#x", a.k)
+
+		// it doesn't prove the value out of the path
+		_, err = NewMPTForBytes(db.NewMapDB(), h).Prove(a.other, proof)
+		assert.Error(t, err)
+	}
+}
+
 func TestNullHash(t *testing.T) {
 	m := NewMPTForBytes(db.NewMapDB(), nil)
 	if m.Hash() != nil {
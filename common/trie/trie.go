@@ -16,6 +16,8 @@ type (
 		Get(k []byte) ([]byte, error)
 		Hash() []byte               // return nil if this Tree is empty
 		GetProof(k []byte) [][]byte // return nill of this Tree is empty
+		// GetProofOrExclusion returns the proof of the exclusion if there is no value for the key.
+		GetProofOrExclusion(k []byte) [][]byte
 		Iterator() Iterator
 		Filter(prefix []byte) Iterator
 		Equal(immutable Immutable, exact bool) bool
@@ -66,6 +68,8 @@ type (
 		Get(k []byte) (Object, error)
 		Hash() []byte
 		GetProof(k []byte) [][]byte // return nill of this Tree is empty
+		// GetProofOrExclusion returns the proof of the exclusion if there is no value for the key.
+		GetProofOrExclusion(k []byte) [][]byte
 		Iterator() IteratorForObject
 		Filter(prefix []byte) IteratorForObject
 		Equal(object ImmutableForObject, exact bool) bool
@@ -0,0 +1,347 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wallet
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/ipc"
+	"github.com/icon-project/goloop/module"
+)
+
+// SignStep is the step of consensus messages to sign.
+type SignStep uint8
+
+const (
+	SignStepProposal SignStep = iota + 1
+	SignStepPrevote
+	SignStepPrecommit
+)
+
+func (s SignStep) String() string {
+	switch s {
+	case SignStepProposal:
+		return "proposal"
+	case SignStepPrevote:
+		return "prevote"
+	case SignStepPrecommit:
+		return "precommit"
+	default:
+		return fmt.Sprintf("SignStep(%d)", s)
+	}
+}
+
+// SignKind classifies the data to sign. Signers refuse requests without
+// the kind.
+type SignKind uint8
+
+const (
+	// SignKindConsensus is for consensus messages with the SignContext.
+	SignKindConsensus SignKind = iota + 1
+	// SignKindOther is for other data, like the authentication of peers,
+	// BTP proofs and transactions. The signer can't check it.
+	SignKindOther
+)
+
+func (k SignKind) String() string {
+	switch k {
+	case SignKindConsensus:
+		return "consensus"
+	case SignKindOther:
+		return "other"
+	default:
+		return fmt.Sprintf("SignKind(%d)", k)
+	}
+}
+
+// SignContext is the position of the consensus message to sign.
+type SignContext struct {
+	Height int64
+	Round  int32
+	Step   SignStep
+}
+
+// Compare returns negative value if sc is before o, zero if they are same,
+// and positive value if sc is after o.
+func (sc *SignContext) Compare(o *SignContext) int {
+	switch {
+	case sc.Height != o.Height:
+		return compareInt64(sc.Height, o.Height)
+	case sc.Round != o.Round:
+		return compareInt64(int64(sc.Round), int64(o.Round))
+	default:
+		return compareInt64(int64(sc.Step), int64(o.Step))
+	}
+}
+
+func compareInt64(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+func (sc *SignContext) String() string {
+	return fmt.Sprintf("{Height:%d Round:%d Step:%s}", sc.Height, sc.Round, sc.Step)
+}
+
+// ConsensusSigner is implemented by wallets needing the position of
+// consensus messages to sign, like remote signers preventing double signs.
+type ConsensusSigner interface {
+	SignConsensus(data []byte, sc *SignContext) ([]byte, error)
+}
+
+// messages of signer protocol
+const (
+	signerMsgPublicKey uint = iota + 1
+	signerMsgSign
+)
+
+var (
+	signerDialTimeout    = 5 * time.Second
+	signerRequestTimeout = 5 * time.Second
+)
+
+type signRequest struct {
+	Data    []byte
+	Context *SignContext
+	Kind    SignKind
+}
+
+type signResponse struct {
+	Signature []byte
+	Error     string
+}
+
+type publicKeyResponse struct {
+	PublicKey []byte
+}
+
+// ParseSignerAddress parses the address of the signer like
+// "unix:/path/to/socket" or "tcp:host:port".
+func ParseSignerAddress(s string) (string, string, error) {
+	idx := strings.Index(s, ":")
+	if idx < 0 {
+		return "", "", errors.IllegalArgumentError.Errorf("InvalidSignerAddress(addr=%s)", s)
+	}
+	network, address := s[:idx], s[idx+1:]
+	switch network {
+	case "unix", "tcp":
+		return network, address, nil
+	default:
+		return "", "", errors.IllegalArgumentError.Errorf("UnsupportedNetwork(network=%s)", network)
+	}
+}
+
+// SignerTLSConfig is the configuration for mutual TLS between nodes and
+// signers. Cert and Key are files of the certificate and its key, and CA
+// is the file of certificates to verify the peer.
+type SignerTLSConfig struct {
+	Cert       string
+	Key        string
+	CA         string
+	ServerName string
+}
+
+func (c *SignerTLSConfig) IsEmpty() bool {
+	return c == nil || (c.Cert == "" && c.Key == "" && c.CA == "")
+}
+
+func (c *SignerTLSConfig) build(server bool) (*tls.Config, error) {
+	if c.Cert == "" || c.Key == "" || c.CA == "" {
+		return nil, errors.IllegalArgumentError.New("CertKeyAndCARequired")
+	}
+	cert, err := tls.LoadX509KeyPair(c.Cert, c.Key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to load key pair cert=%s key=%s", c.Cert, c.Key)
+	}
+	caBytes, err := os.ReadFile(c.CA)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to read CA file=%s", c.CA)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caBytes) {
+		return nil, errors.IllegalArgumentError.Errorf("NoCertificates(file=%s)", c.CA)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if server {
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		cfg.ClientCAs = pool
+	} else {
+		cfg.RootCAs = pool
+		cfg.ServerName = c.ServerName
+	}
+	return cfg, nil
+}
+
+// SignerTLSConfigFromOptions returns the configuration with the options
+// cert, key, ca and server_name. It returns nil if there is no options.
+func SignerTLSConfigFromOptions(opts map[string]string) *SignerTLSConfig {
+	c := &SignerTLSConfig{
+		Cert:       opts["cert"],
+		Key:        opts["key"],
+		CA:         opts["ca"],
+		ServerName: opts["server_name"],
+	}
+	if c.IsEmpty() {
+		return nil
+	}
+	return c
+}
+
+func checkSignerTLS(network string, c *SignerTLSConfig) error {
+	if network != "unix" && c.IsEmpty() {
+		return errors.IllegalArgumentError.Errorf("TLSRequired(network=%s)", network)
+	}
+	return nil
+}
+
+type remoteWallet struct {
+	lock    sync.Mutex
+	network string
+	address string
+	tls     *tls.Config
+	conn    ipc.Connection
+
+	pkey *crypto.PublicKey
+	addr module.Address
+}
+
+func (w *remoteWallet) connectInLock() (ipc.Connection, error) {
+	if w.conn != nil {
+		return w.conn, nil
+	}
+	var conn ipc.Connection
+	var err error
+	if w.tls != nil {
+		conn, err = ipc.DialTLS(w.network, w.address, w.tls, signerDialTimeout)
+	} else {
+		conn, err = ipc.DialTimeout(w.network, w.address, signerDialTimeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+	w.conn = conn
+	return conn, nil
+}
+
+func (w *remoteWallet) request(msg uint, data interface{}, buf interface{}) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	// try again with new connection if the signer was restarted.
+	// Signers return the same signature for the same request, so it's safe
+	// to send it again.
+	var err error
+	for retry := 0; retry < 2; retry++ {
+		var conn ipc.Connection
+		if conn, err = w.connectInLock(); err != nil {
+			continue
+		}
+		// unresponsive signer shouldn't block consensus forever.
+		if err = conn.SetDeadline(time.Now().Add(signerRequestTimeout)); err == nil {
+			if err = conn.SendAndReceive(msg, data, buf); err == nil {
+				return nil
+			}
+		}
+		_ = conn.Close()
+		w.conn = nil
+	}
+	return err
+}
+
+func (w *remoteWallet) sign(kind SignKind, data []byte, sc *SignContext) ([]byte, error) {
+	var res signResponse
+	req := &signRequest{Data: data, Context: sc, Kind: kind}
+	if err := w.request(signerMsgSign, req, &res); err != nil {
+		return nil, err
+	}
+	if len(res.Error) > 0 {
+		return nil, errors.InvalidStateError.Errorf("SignFailure(err=%s)", res.Error)
+	}
+	return res.Signature, nil
+}
+
+func (w *remoteWallet) Sign(data []byte) ([]byte, error) {
+	return w.sign(SignKindOther, data, nil)
+}
+
+func (w *remoteWallet) SignConsensus(data []byte, sc *SignContext) ([]byte, error) {
+	return w.sign(SignKindConsensus, data, sc)
+}
+
+func (w *remoteWallet) PublicKey() []byte {
+	return w.pkey.SerializeCompressed()
+}
+
+func (w *remoteWallet) Address() module.Address {
+	return w.addr
+}
+
+// OpenRemote returns the wallet using the signer at the address like
+// "unix:/path/to/socket" or "tcp:host:port". Mutual TLS is required for
+// TCP.
+func OpenRemote(addr string, c *SignerTLSConfig) (module.Wallet, error) {
+	network, address, err := ParseSignerAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSignerTLS(network, c); err != nil {
+		return nil, err
+	}
+	w := &remoteWallet{
+		network: network,
+		address: address,
+	}
+	if !c.IsEmpty() {
+		cc := *c
+		if cc.ServerName == "" {
+			if host, _, err := net.SplitHostPort(address); err == nil {
+				cc.ServerName = host
+			} else {
+				cc.ServerName = "localhost"
+			}
+		}
+		if w.tls, err = cc.build(false); err != nil {
+			return nil, err
+		}
+	}
+	var res publicKeyResponse
+	if err := w.request(signerMsgPublicKey, nil, &res); err != nil {
+		return nil, err
+	}
+	if w.pkey, err = crypto.ParsePublicKey(res.PublicKey); err != nil {
+		return nil, errors.Wrap(err, "invalid public key from signer")
+	}
+	w.addr = common.NewAccountAddressFromPublicKey(w.pkey)
+	return w, nil
+}
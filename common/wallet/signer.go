@@ -0,0 +1,267 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/ipc"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+// signerState is the last signed consensus message. It's stored before
+// the signature is returned, so that the signer never signs conflicting
+// messages even after restart.
+type signerState struct {
+	Height    int64    `json:"height"`
+	Round     int32    `json:"round"`
+	Step      SignStep `json:"step"`
+	Data      []byte   `json:"data"`
+	Signature []byte   `json:"signature"`
+}
+
+func (s *signerState) context() *SignContext {
+	return &SignContext{Height: s.Height, Round: s.Round, Step: s.Step}
+}
+
+// Signer signs data for remote wallets with the wallet. It keeps the last
+// signed position of consensus messages, and refuses to sign conflicting
+// messages for the position or messages for positions before it. Nodes
+// sharing the key are protected only if they use the same signer.
+type Signer struct {
+	lock   sync.Mutex
+	wallet module.Wallet
+	path   string
+	state  *signerState
+	server ipc.Server
+	log    log.Logger
+}
+
+func (s *Signer) loadState() error {
+	bs, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	state := new(signerState)
+	if err := json.Unmarshal(bs, state); err != nil {
+		return errors.Wrapf(err, "invalid signer state file=%s", s.path)
+	}
+	s.state = state
+	return nil
+}
+
+func (s *Signer) storeState(state *signerState) error {
+	bs, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(bs); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Sign signs the consensus message at the position of sc. Signing the same
+// data for the last position returns the same signature.
+func (s *Signer) Sign(data []byte, sc *SignContext) ([]byte, error) {
+	if sc == nil {
+		return nil, errors.IllegalArgumentError.New("NoSignContext")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.state != nil {
+		last := s.state.context()
+		switch c := sc.Compare(last); {
+		case c < 0:
+			return nil, errors.InvalidStateError.Errorf(
+				"PositionRegressed(last=%s,req=%s)", last, sc)
+		case c == 0:
+			if bytes.Equal(s.state.Data, data) {
+				return s.state.Signature, nil
+			}
+			return nil, errors.InvalidStateError.Errorf(
+				"ConflictingSign(pos=%s)", sc)
+		}
+	}
+	sig, err := s.wallet.Sign(data)
+	if err != nil {
+		return nil, err
+	}
+	state := &signerState{
+		Height:    sc.Height,
+		Round:     sc.Round,
+		Step:      sc.Step,
+		Data:      data,
+		Signature: sig,
+	}
+	if err := s.storeState(state); err != nil {
+		return nil, errors.Wrap(err, "fail to store signer state")
+	}
+	s.state = state
+	return sig, nil
+}
+
+// SignOther signs the data other than consensus messages, like
+// the authentication of peers and BTP proofs. Nodes need them to work,
+// but they can't be checked by the signer.
+func (s *Signer) SignOther(data []byte) ([]byte, error) {
+	return s.wallet.Sign(data)
+}
+
+func (s *Signer) signFor(req *signRequest) ([]byte, error) {
+	switch req.Kind {
+	case SignKindConsensus:
+		return s.Sign(req.Data, req.Context)
+	case SignKindOther:
+		return s.SignOther(req.Data)
+	default:
+		return nil, errors.IllegalArgumentError.Errorf("UnclassifiedSign(kind=%s)", req.Kind)
+	}
+}
+
+// Address returns the address of the wallet of the signer.
+func (s *Signer) Address() module.Address {
+	return s.wallet.Address()
+}
+
+func (s *Signer) OnConnect(c ipc.Connection) error {
+	c.SetHandler(signerMsgPublicKey, s)
+	c.SetHandler(signerMsgSign, s)
+	return nil
+}
+
+func (s *Signer) OnClose(c ipc.Connection) {
+	// do nothing
+}
+
+func (s *Signer) HandleMessage(c ipc.Connection, msg uint, data []byte) error {
+	switch msg {
+	case signerMsgPublicKey:
+		return c.Send(msg, &publicKeyResponse{s.wallet.PublicKey()})
+	case signerMsgSign:
+		var req signRequest
+		if _, err := codec.MP.UnmarshalFromBytes(data, &req); err != nil {
+			return err
+		}
+		var res signResponse
+		if sig, err := s.signFor(&req); err != nil {
+			s.log.Warnf("Fail to sign kind=%s pos=%v err=%v", req.Kind, req.Context, err)
+			res.Error = err.Error()
+		} else {
+			res.Signature = sig
+		}
+		return c.Send(msg, &res)
+	default:
+		return errors.Errorf("UnknownMessage(msg=%d)", msg)
+	}
+}
+
+// Listen starts to listen on the address like "unix:/path/to/socket" or
+// "tcp:host:port". Mutual TLS is required for TCP.
+func (s *Signer) Listen(addr string, c *SignerTLSConfig) error {
+	network, address, err := ParseSignerAddress(addr)
+	if err != nil {
+		return err
+	}
+	if err := checkSignerTLS(network, c); err != nil {
+		return err
+	}
+	var server ipc.Server
+	if c.IsEmpty() {
+		server = ipc.NewServer()
+	} else {
+		cfg, err := c.build(true)
+		if err != nil {
+			return err
+		}
+		server = ipc.NewTLSServer(cfg)
+	}
+	if err := server.Listen(network, address); err != nil {
+		return err
+	}
+	if network == "unix" {
+		if err := os.Chmod(address, 0600); err != nil {
+			_ = server.Close()
+			return err
+		}
+	}
+	server.SetHandler(s)
+	s.server = server
+	return nil
+}
+
+// Addr returns the address listening on.
+func (s *Signer) Addr() net.Addr {
+	return s.server.Addr()
+}
+
+// Loop handles connections until the signer is closed.
+func (s *Signer) Loop() error {
+	return s.server.Loop()
+}
+
+func (s *Signer) Close() error {
+	if s.server != nil {
+		return s.server.Close()
+	}
+	return nil
+}
+
+// NewSigner returns the signer for the wallet. The last signed position is
+// stored in the file of statePath.
+func NewSigner(w module.Wallet, statePath string, logger log.Logger) (*Signer, error) {
+	if dir := filepath.Dir(statePath); dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, err
+		}
+	}
+	s := &Signer{
+		wallet: w,
+		path:   statePath,
+		log:    logger,
+	}
+	if err := s.loadState(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+func startTestSigner(t *testing.T, w module.Wallet, dir, addr string, c *SignerTLSConfig) *Signer {
+	s, err := NewSigner(w, filepath.Join(dir, "state.json"), log.GlobalLogger())
+	assert.NoError(t, err)
+	assert.NoError(t, s.Listen(addr, c))
+	go s.Loop()
+	return s
+}
+
+func verifyTestSignature(t *testing.T, w module.Wallet, data, sig []byte) {
+	s, err := crypto.ParseSignature(sig)
+	assert.NoError(t, err)
+	pk, err := crypto.ParsePublicKey(w.PublicKey())
+	assert.NoError(t, err)
+	assert.True(t, s.Verify(data, pk))
+}
+
+func TestRemoteWallet_Sign(t *testing.T) {
+	dir := t.TempDir()
+	addr := "unix:" + filepath.Join(dir, "signer.sock")
+	w := New()
+	s := startTestSigner(t, w, dir, addr, nil)
+
+	rw, err := OpenRemote(addr, nil)
+	assert.NoError(t, err)
+	assert.True(t, w.Address().Equal(rw.Address()))
+	assert.Equal(t, w.PublicKey(), rw.PublicKey())
+
+	data := crypto.SHA3Sum256([]byte("data"))
+	sig, err := rw.Sign(data)
+	assert.NoError(t, err)
+	verifyTestSignature(t, w, data, sig)
+
+	cs := rw.(ConsensusSigner)
+	vote1 := crypto.SHA3Sum256([]byte("vote1"))
+	vote2 := crypto.SHA3Sum256([]byte("vote2"))
+	pos := &SignContext{Height: 10, Round: 1, Step: SignStepPrevote}
+	sig1, err := cs.SignConsensus(vote1, pos)
+	assert.NoError(t, err)
+	verifyTestSignature(t, w, vote1, sig1)
+
+	// same message for the position
+	sig, err = cs.SignConsensus(vote1, pos)
+	assert.NoError(t, err)
+	assert.Equal(t, sig1, sig)
+
+	// conflicting message for the position
+	_, err = cs.SignConsensus(vote2, pos)
+	assert.Error(t, err)
+
+	// message for the previous position
+	_, err = cs.SignConsensus(vote2, &SignContext{Height: 10, Round: 0, Step: SignStepPrecommit})
+	assert.Error(t, err)
+
+	_, err = cs.SignConsensus(vote2, &SignContext{Height: 10, Round: 1, Step: SignStepPrecommit})
+	assert.NoError(t, err)
+
+	// the state is kept after restart of the signer
+	assert.NoError(t, s.Close())
+	s = startTestSigner(t, w, dir, addr, nil)
+	defer s.Close()
+
+	_, err = cs.SignConsensus(vote1, &SignContext{Height: 10, Round: 1, Step: SignStepPrecommit})
+	assert.Error(t, err)
+	_, err = cs.SignConsensus(vote1, &SignContext{Height: 11, Round: 0, Step: SignStepProposal})
+	assert.NoError(t, err)
+}
+
+func writeTestPEM(t *testing.T, path, typ string, bs []byte) {
+	f, err := os.Create(path)
+	assert.NoError(t, err)
+	assert.NoError(t, pem.Encode(f, &pem.Block{Type: typ, Bytes: bs}))
+	assert.NoError(t, f.Close())
+}
+
+func newTestCert(t *testing.T, dir, name string, ca *x509.Certificate, caKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{name},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if ca == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		ca, caKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	writeTestPEM(t, filepath.Join(dir, name+".crt"), "CERTIFICATE", der)
+	writeTestPEM(t, filepath.Join(dir, name+".key"), "EC PRIVATE KEY", keyDER)
+	return cert, key
+}
+
+func TestRemoteWallet_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := newTestCert(t, dir, "ca", nil, nil)
+	newTestCert(t, dir, "signer", ca, caKey)
+	newTestCert(t, dir, "node", ca, caKey)
+	other, otherKey := newTestCert(t, dir, "other", nil, nil)
+	newTestCert(t, dir, "intruder", other, otherKey)
+
+	tlsConfigFor := func(name string) *SignerTLSConfig {
+		return &SignerTLSConfig{
+			Cert:       filepath.Join(dir, name+".crt"),
+			Key:        filepath.Join(dir, name+".key"),
+			CA:         filepath.Join(dir, "ca.crt"),
+			ServerName: "signer",
+		}
+	}
+
+	w := New()
+
+	// TLS is required for TCP
+	_, err := OpenRemote("tcp:127.0.0.1:1", nil)
+	assert.Error(t, err)
+
+	s := startTestSigner(t, w, dir, "tcp:127.0.0.1:0", tlsConfigFor("signer"))
+	defer s.Close()
+	addr := "tcp:" + s.Addr().String()
+
+	rw, err := OpenRemote(addr, tlsConfigFor("node"))
+	assert.NoError(t, err)
+	assert.True(t, w.Address().Equal(rw.Address()))
+
+	_, err = OpenRemote(addr, tlsConfigFor("intruder"))
+	assert.Error(t, err)
+}
+
+func TestSigner_Classification(t *testing.T) {
+	dir := t.TempDir()
+	addr := "unix:" + filepath.Join(dir, "signer.sock")
+	w := New()
+	s := startTestSigner(t, w, dir, addr, nil)
+	defer s.Close()
+
+	data := crypto.SHA3Sum256([]byte("data"))
+
+	// consensus messages need the position
+	_, err := s.Sign(data, nil)
+	assert.Error(t, err)
+
+	// unclassified requests are refused
+	_, err = s.signFor(&signRequest{Data: data})
+	assert.Error(t, err)
+	_, err = s.signFor(&signRequest{Data: data, Kind: SignKindConsensus})
+	assert.Error(t, err)
+
+	rw, err := OpenRemote(addr, nil)
+	assert.NoError(t, err)
+	sig, err := rw.Sign(data)
+	assert.NoError(t, err)
+	verifyTestSignature(t, w, data, sig)
+
+	sig, err = rw.(ConsensusSigner).SignConsensus(data, &SignContext{Height: 1, Step: SignStepProposal})
+	assert.NoError(t, err)
+	verifyTestSignature(t, w, data, sig)
+}
+
+func TestRemoteWallet_Timeout(t *testing.T) {
+	old := signerRequestTimeout
+	signerRequestTimeout = 100 * time.Millisecond
+	defer func() { signerRequestTimeout = old }()
+
+	// the signer accepting connections without responses
+	path := filepath.Join(t.TempDir(), "signer.sock")
+	l, err := net.Listen("unix", path)
+	assert.NoError(t, err)
+	defer l.Close()
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer c.Close()
+		}
+	}()
+
+	start := time.Now()
+	_, err = OpenRemote("unix:"+path, nil)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/icon-project/goloop/common/codec"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/wallet"
 	"github.com/icon-project/goloop/consensus/fastsync"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/network"
@@ -96,6 +97,7 @@ type consensus struct {
 	bpmCache       bpmCache
 	timeoutPropose time.Duration
 	dsmLog         dsmLog
+	evidences      *evidencePool
 
 	lastBlock          module.Block
 	validators         module.ValidatorList
@@ -131,6 +133,11 @@ type consensus struct {
 	metric *metric.ConsensusMetric
 
 	lastVoteData *LastVoteData
+
+	// round state inspection
+	reason         string
+	roundEvents    []*RoundEvent
+	roundListeners RoundEventListeners
 }
 
 func NewConsensus(
@@ -232,6 +239,7 @@ func (cs *consensus) resetForNewHeight(prevBlock module.Block, votes *voteSet) {
 	cs._resetForNewHeight(prevBlock, votes)
 	cs._resetForNewRound(0)
 	cs.beginStep(stepNewHeight)
+	cs.retryEvidences()
 }
 
 func (cs *consensus) _resetForNewRound(round int32) {
@@ -291,6 +299,7 @@ func (cs *consensus) beginStep(step step) {
 	}
 	cs.step = step
 	cs.log.Debugf("enterStep %v\n", cs.hrs)
+	cs.onStepChange()
 }
 
 func (cs *consensus) OnReceive(
@@ -368,11 +377,7 @@ func (cs *consensus) logAndCheckProposalMessage(msg *ProposalMessage) error {
 	}
 	dsd := cs.dsmLog.LogAndCheckProposalMessage(msg)
 	if dsd != nil {
-		if blk == nil {
-			return errors.Errorf("cannot report conflicting vote: no previous block cs.height=%d msg.Height=%d", cs.height, msg.Height)
-		}
-		err := cs.c.ServiceManager().SendDoubleSignReport(blk.Result(), blk.NextValidatorsHash(), dsd)
-		return err
+		return cs.reportDoubleSign(dsd)
 	}
 	return nil
 }
@@ -385,6 +390,7 @@ func (cs *consensus) ReceiveProposalMessage(msg *ProposalMessage, unicast bool)
 	if msg.Height != cs.height || msg.Round != cs.round || cs.step >= stepCommit {
 		return nil
 	}
+	cs.setReason(reasonProposal)
 	index := cs.validators.IndexOf(msg.address())
 	if index < 0 {
 		return errors.Errorf("bad proposer %v", msg.address())
@@ -442,11 +448,7 @@ func (cs *consensus) logAndCheckVoteMessage(msg *VoteMessage) error {
 	}
 	dsd := cs.dsmLog.LogAndCheckVoteMessage(msg)
 	if dsd != nil {
-		if blk == nil {
-			return errors.Errorf("cannot report conflicting vote: no previous block cs.height=%d msg.Height=%d", cs.height, msg.Height)
-		}
-		err := cs.c.ServiceManager().SendDoubleSignReport(blk.Result(), blk.NextValidatorsHash(), dsd)
-		return err
+		return cs.reportDoubleSign(dsd)
 	}
 	return nil
 }
@@ -461,6 +463,7 @@ func (cs *consensus) ReceiveBlockPartMessage(msg *BlockPartMessage, unicast bool
 	if cs.currentBlockParts.IsZero() || cs.currentBlockParts.IsComplete() {
 		return -1, nil
 	}
+	cs.setReason(reasonBlockPart)
 
 	bp, err := cs.currentBlockParts.AddPartFromBytes(msg.BlockPart, cs.c.BlockManager())
 	if bp == nil {
@@ -546,6 +549,7 @@ func (cs *consensus) handlePrevoteMessage(msg *VoteMessage, prevotes *voteSet) {
 	if cs.step >= stepCommit {
 		return
 	}
+	cs.setReason(reasonPrevote)
 
 	partSetID, ok := prevotes.getOverTwoThirdsPartSetID()
 	if ok {
@@ -575,6 +579,7 @@ func (cs *consensus) handlePrevoteMessage(msg *VoteMessage, prevotes *voteSet) {
 }
 
 func (cs *consensus) handlePrecommitMessage(msg *VoteMessage, precommits *voteSet) {
+	cs.setReason(reasonPrecommit)
 	if msg.Round < cs.round && cs.step < stepCommit {
 		if psid, _ := precommits.getOverTwoThirdsPartSetID(); psid != nil {
 			cs.enterCommit(precommits, psid, msg.Round)
@@ -643,6 +648,7 @@ func (cs *consensus) enterPropose() {
 		if cs.hrs != hrs || !cs.started {
 			return
 		}
+		cs.setReason(reasonTimeoutPropose)
 		cs.enterPrevote()
 	})
 
@@ -679,6 +685,7 @@ func (cs *consensus) enterPropose() {
 
 					if err != nil {
 						cs.log.Warnf("propose cb error: %+v\n", err)
+						cs.setReason(reasonProposeFail)
 						cs.enterPrevote()
 						return
 					}
@@ -690,6 +697,7 @@ func (cs *consensus) enterPropose() {
 
 					cs.sendProposal(bps, -1)
 					cs.currentBlockParts.SetByPartSetAndValidatedBlock(bps, blk)
+					cs.setReason(reasonProposed)
 					cs.enterPrevote()
 				},
 			)
@@ -829,6 +837,7 @@ func (cs *consensus) enterPrevoteWait() {
 			if cs.hrs != hrs || !cs.started {
 				return
 			}
+			cs.setReason(reasonTimeoutPrevote)
 			cs.enterPrecommit()
 		})
 	}
@@ -929,6 +938,7 @@ func (cs *consensus) enterPrecommitWait() {
 			if cs.hrs != hrs || !cs.started {
 				return
 			}
+			cs.setReason(reasonTimeoutPrecommit)
 			cs.enterNewRound()
 		})
 	}
@@ -1030,6 +1040,7 @@ func (cs *consensus) enterNewRound() {
 			if cs.hrs != hrs || !cs.started {
 				return
 			}
+			cs.setReason(reasonWaitDone)
 			cs.enterPropose()
 		})
 	} else {
@@ -1055,6 +1066,7 @@ func (cs *consensus) enterTransactionWait() {
 				return
 			}
 
+			cs.setReason(reasonTransaction)
 			cs.enterPropose()
 		})
 		cs.log.Must(err)
@@ -1082,6 +1094,7 @@ func (cs *consensus) enterNewHeight() {
 			if cs.hrs != hrs || !cs.started {
 				return
 			}
+			cs.setReason(reasonWaitDone)
 			cs.processPrefetchItems()
 			if cs.step <= stepTransactionWait {
 				cs.enterTransactionWait()
@@ -1117,7 +1130,11 @@ func (cs *consensus) doSendProposal(blockParts PartSet, polRound int32) error {
 	msg.BlockPartSetID = blockParts.ID()
 	msg.POLRound = polRound
 	msg.NID = cs.nidForCSMessage()
-	err := msg.Sign(cs.c.Wallet())
+	err := msg.signFor(cs.c.Wallet(), &wallet.SignContext{
+		Height: msg.Height,
+		Round:  msg.Round,
+		Step:   wallet.SignStepProposal,
+	})
 	if err != nil {
 		return err
 	}
@@ -1280,7 +1297,11 @@ func (cs *consensus) doSendVote(vt VoteType, blockParts *blockPartSet) error {
 	}
 	msg.Timestamp = cs.voteTimestamp()
 
-	err := msg.Sign(cs.c.Wallet())
+	err := msg.signFor(cs.c.Wallet(), &wallet.SignContext{
+		Height: msg.Height,
+		Round:  msg.Round,
+		Step:   signStepFor(msg.Type),
+	})
 	if err != nil {
 		return err
 	}
@@ -1782,6 +1803,7 @@ func (cs *consensus) Start() error {
 	cs.mutex.Lock()
 	defer cs.mutex.Unlock()
 
+	cs.setReason(reasonStart)
 	lastBlock, err := cs.c.BlockManager().GetLastBlock()
 	if err != nil {
 		return err
@@ -1808,6 +1830,11 @@ func (cs *consensus) Start() error {
 		return err
 	}
 
+	cs.evidences, err = loadEvidencePool(cs.c.Database(), cs.log)
+	if err != nil {
+		return err
+	}
+
 	cs.nextPCM = pcMap
 	cs.resetForNewHeight(lastBlock, newVoteSet(0))
 	cs.prevValidators = validators
@@ -2162,6 +2189,7 @@ func (cs *consensus) processBlock(br fastsync.BlockResult) {
 	cs.currentBlockParts.SetByPartSetAndBlock(ps, blk)
 	cs.syncing = false
 	br.Consume()
+	cs.setReason(reasonSync)
 	if cs.step < stepCommit {
 		cs.enterCommit(precommits, id, votes.Round)
 	} else {
@@ -220,6 +220,150 @@ func TestConsensus_BasicConsensus(t *testing.T) {
 	}
 }
 
+func TestConsensus_RoundEvents(t *testing.T) {
+	f := test.NewNode(t)
+	defer f.Close()
+
+	h := make([]*test.SimplePeerHandler, 3)
+	for i := 0; i < len(h); i++ {
+		_, h[i] = f.NM.NewPeerFor(module.ProtoConsensus)
+	}
+
+	f.ProposeImportFinalizeBlockWithTX(
+		consensus.NewEmptyCommitVoteList(),
+		test.NewTx().SetValidatorsAddresser(
+			h[0], h[1], h[2], f.Chain.Wallet(),
+		).String(),
+	)
+	f.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+
+	rsi, ok := f.CS.(consensus.RoundStateInspector)
+	assert.True(t, ok)
+	evch := make(chan *consensus.RoundEvent, 64)
+	remove := rsi.AddRoundEventListener(func(ev *consensus.RoundEvent) {
+		evch <- ev
+	})
+	defer remove()
+
+	err := f.CS.Start()
+	assert.NoError(t, err)
+
+	var pm consensus.ProposalMessage
+	h[0].Receive(consensus.ProtoProposal, nil, &pm)
+	assert.EqualValues(t, 3, pm.Height)
+
+	rs := rsi.GetRoundStatus()
+	assert.EqualValues(t, 3, rs.Height)
+	assert.Equal(t, 4, len(rs.Validators))
+	assert.True(t, f.Chain.Wallet().Address().Equal(rs.Proposer))
+
+	ps := consensus.NewPartSetFromID(pm.BlockPartSetID)
+	for !ps.IsComplete() {
+		var bpm consensus.BlockPartMessage
+		h[0].Receive(consensus.ProtoBlockPart, nil, &bpm)
+		pt, err := consensus.NewPart(bpm.BlockPart)
+		assert.NoError(t, err)
+		assert.NoError(t, ps.AddPart(pt))
+	}
+	blk, err := f.BM.NewBlockDataFromReader(ps.NewReader())
+	assert.NoError(t, err)
+
+	for _, vt := range []consensus.VoteType{consensus.VoteTypePrevote, consensus.VoteTypePrecommit} {
+		for i := 0; i < len(h); i++ {
+			h[i].Unicast(
+				consensus.ProtoVote,
+				consensus.NewVoteMessage(
+					h[i].Wallet(),
+					vt, 3, 0, blk.ID(),
+					ps.ID(), blk.Timestamp()+1, nil, nil,
+					0,
+				),
+				nil,
+			)
+		}
+	}
+
+	var steps []string
+	for {
+		ev := <-evch
+		if ev.Height != 3 {
+			break
+		}
+		steps = append(steps, ev.Step)
+		if ev.Step == "propose" {
+			assert.Equal(t, "start", ev.Reason)
+		}
+		if ev.Step == "precommitWait" {
+			assert.Equal(t, "precommit", ev.Reason)
+			assert.Equal(t, 4, len(ev.Precommits))
+		}
+	}
+	assert.Equal(t, []string{
+		"newHeight", "transactionWait", "propose", "prevote", "prevoteWait",
+		"precommit", "precommitWait", "commit",
+	}, steps)
+}
+
+func TestConsensus_DoubleSignEvidence(t *testing.T) {
+	f := test.NewNode(t)
+	defer f.Close()
+
+	h := make([]*test.SimplePeerHandler, 3)
+	for i := 0; i < len(h); i++ {
+		_, h[i] = f.NM.NewPeerFor(module.ProtoConsensus)
+	}
+
+	f.ProposeImportFinalizeBlockWithTX(
+		consensus.NewEmptyCommitVoteList(),
+		test.NewTx().SetValidatorsAddresser(
+			h[0], h[1], h[2], f.Chain.Wallet(),
+		).String(),
+	)
+	f.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+
+	err := f.CS.Start()
+	assert.NoError(t, err)
+
+	var pm consensus.ProposalMessage
+	h[0].Receive(consensus.ProtoProposal, nil, &pm)
+	assert.EqualValues(t, 3, pm.Height)
+
+	ei, ok := f.CS.(consensus.EvidenceInspector)
+	assert.True(t, ok)
+	assert.Empty(t, ei.GetEvidences())
+
+	psid := &consensus.PartSetID{Count: 1, Hash: make([]byte, 32)}
+	for i := 0; i < 3; i++ {
+		id := make([]byte, 32)
+		id[0] = byte(i % 2)
+		h[0].Unicast(
+			consensus.ProtoVote,
+			consensus.NewVoteMessage(
+				h[1].Wallet(), consensus.VoteTypePrevote, 3, 0, id,
+				psid, int64(1000+i), nil, nil, 0,
+			),
+			nil,
+		)
+	}
+
+	var evidences []*consensus.Evidence
+	for i := 0; i < 50 && len(evidences) == 0; i++ {
+		time.Sleep(20 * time.Millisecond)
+		evidences = ei.GetEvidences()
+	}
+	assert.Equal(t, 1, len(evidences))
+	e := evidences[0]
+	assert.Equal(t, module.DSTVote, e.Type)
+	assert.EqualValues(t, 3, e.Height)
+	assert.True(t, h[1].Wallet().Address().Equal(e.Signer))
+	assert.Equal(t, 2, len(e.Data))
+	assert.Equal(t, consensus.EvidenceQueued, e.Status)
+	assert.Equal(t, 1, e.Attempts)
+
+	sm := f.SM.(*test.ServiceManager)
+	assert.Equal(t, 1, len(sm.DoubleSignReports()))
+}
+
 func TestConsensus_BasicConsensus2(t *testing.T) {
 	f := test.NewFixture(t,
 		test.AddDefaultNode(false),
@@ -248,15 +392,26 @@ type btpTest struct {
 }
 
 func newBTPTest(t *testing.T, opt ...test.FixtureOption) *btpTest {
-	const dsa = "ecdsa/secp256k1"
-	const uid = "eth"
+	return newBTPTestWith(t, "ecdsa/secp256k1", "eth", opt...)
+}
+
+func newBTPTestWith(t *testing.T, dsa string, uid string, opt ...test.FixtureOption) *btpTest {
 	assert := assert.New(t)
 	opt = append(opt, test.AddDefaultNode(false), test.AddValidatorNodes(4), test.SetTimeoutPropose(4*time.Second))
 	f := test.NewFixture(t, opt...)
+	if dsa == "bls/bls12-381" {
+		for _, v := range f.Validators {
+			w, err := ntm.NewBLSWallet(ntm.GenerateBLSSecret())
+			assert.NoError(err)
+			v.Chain.SetWalletFor(dsa, w)
+		}
+	}
 
-	tx := test.NewTx().Call("setRevision", map[string]string{
+	// the revision is applied to the next transaction
+	f.SendTransactionToProposer(test.NewTx().Call("setRevision", map[string]string{
 		"code": fmt.Sprintf("0x%x", basic.MaxRevision),
-	}).Call("setMinimizeBlockGen", map[string]string{
+	}))
+	tx := test.NewTx().Call("setMinimizeBlockGen", map[string]string{
 		"yn": "0x1",
 	})
 	for i, v := range f.Validators {
@@ -264,12 +419,9 @@ func newBTPTest(t *testing.T, opt ...test.FixtureOption) *btpTest {
 			"name":   dsa,
 			"pubKey": fmt.Sprintf("0x%x", v.Chain.WalletFor(dsa).PublicKey()),
 		})
-		pk := v.Chain.WalletFor(dsa).PublicKey()
-		iconAddr, err := ntm.NewIconAddressFromPubKey(pk)
-		assert.NoError(err)
-		ethAddr, err := ntm.ForUID("eth").AddressFromPubKey(pk)
+		addr, err := ntm.ForUID(uid).AddressFromPubKey(v.Chain.WalletFor(dsa).PublicKey())
 		assert.NoError(err)
-		t.Logf("register key index=%d %s=%x icon=%x eth=%x", i, dsa, v.Chain.WalletFor(dsa).PublicKey(), iconAddr, ethAddr)
+		t.Logf("register key index=%d %s=%x %s=%x", i, dsa, v.Chain.WalletFor(dsa).PublicKey(), uid, addr)
 	}
 	tx.Call("openBTPNetwork", map[string]string{
 		"networkTypeName": uid,
@@ -314,6 +466,16 @@ func TestConsensus_NoNTSVoteCountForFirstNTS(t *testing.T) {
 func TestConsensus_BTPBasic(t *testing.T) {
 	tst := newBTPTest(t)
 	defer tst.Close()
+	testBTPBasic(tst)
+}
+
+func TestConsensus_BTPBasicWithBLS(t *testing.T) {
+	tst := newBTPTestWith(t, "bls/bls12-381", "bls")
+	defer tst.Close()
+	testBTPBasic(tst)
+}
+
+func testBTPBasic(tst *btpTest) {
 	f := tst.Fixture
 	assert := tst.Assertions
 
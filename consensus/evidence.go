@@ -0,0 +1,311 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+// status of evidences
+const (
+	// EvidencePending is for the evidence waiting for the submission.
+	EvidencePending = "pending"
+	// EvidenceQueued is for the evidence accepted by the service manager.
+	// The report is included in the block proposed by the node.
+	EvidenceQueued = "queued"
+	// EvidenceFailed is for the evidence failed to submit after retries.
+	EvidenceFailed = "failed"
+)
+
+const (
+	keyEvidences             = "consensus.evidences"
+	configEvidencePoolSize   = 256
+	configEvidenceRetryLimit = 10
+	configEvidenceExpiration = 24 * time.Hour
+)
+
+// Evidence is the conflicting messages of a validator detected by consensus.
+type Evidence struct {
+	Type     string
+	Height   int64
+	Round    int32
+	Signer   *common.Address
+	Data     [][]byte
+	Detected int64
+	Status   string
+	Attempts int
+	Error    string
+}
+
+func (e *Evidence) isSameWith(t string, h int64, r int32, signer []byte) bool {
+	return e.Type == t && e.Height == h && e.Round == r &&
+		bytes.Equal(e.Signer.ID(), signer)
+}
+
+// evictable returns true if the evidence is reported, failed or expired.
+func (e *Evidence) evictable(now int64) bool {
+	return e.Status != EvidencePending ||
+		now-e.Detected > configEvidenceExpiration.Microseconds()
+}
+
+func (e *Evidence) doubleSignData() ([]module.DoubleSignData, error) {
+	dsd := make([]module.DoubleSignData, len(e.Data))
+	for i, d := range e.Data {
+		var err error
+		if dsd[i], err = DecodeDoubleSignData(e.Type, d); err != nil {
+			return nil, err
+		}
+	}
+	return dsd, nil
+}
+
+// EvidenceInspector provides double sign evidences detected by consensus.
+type EvidenceInspector interface {
+	GetEvidences() []*Evidence
+}
+
+func EvidenceInspectorOf(c module.Chain) (EvidenceInspector, error) {
+	if cs := c.Consensus(); cs != nil {
+		if ei, ok := cs.(EvidenceInspector); ok {
+			return ei, nil
+		}
+	}
+	return nil, errors.UnsupportedError.New("NoEvidenceInspector")
+}
+
+// evidencePool keeps evidences in the database of the chain, so that
+// reports are retried after restart.
+//
+// Reports are system transactions included by the proposer. They can't be
+// submitted through the wallet of the node, because transactions rules
+// reject signed reports (UnsupportedDoubleSignReport).
+type evidencePool struct {
+	bk        db.Bucket
+	log       log.Logger
+	evidences []*Evidence
+}
+
+func roundOf(dsd module.DoubleSignData) int32 {
+	switch d := dsd.(type) {
+	case *dsVote:
+		return d.msg.Round
+	case *dsProposal:
+		return d.msg.Round
+	default:
+		return 0
+	}
+}
+
+// add adds the evidence of the data. It returns nil if it's already added.
+func (p *evidencePool) add(dsd []module.DoubleSignData) *Evidence {
+	t, h, r, signer := dsd[0].Type(), dsd[0].Height(), roundOf(dsd[0]), dsd[0].Signer()
+	for _, e := range p.evidences {
+		if e.isSameWith(t, h, r, signer) {
+			return nil
+		}
+	}
+	e := &Evidence{
+		Type:     t,
+		Height:   h,
+		Round:    r,
+		Signer:   common.NewAccountAddress(signer),
+		Data:     make([][]byte, len(dsd)),
+		Detected: time.Now().UnixMicro(),
+		Status:   EvidencePending,
+	}
+	for i, d := range dsd {
+		e.Data[i] = d.Bytes()
+	}
+	if len(p.evidences) >= configEvidencePoolSize {
+		p.evict(e.Detected)
+	}
+	p.evidences = append(p.evidences, e)
+	return e
+}
+
+// evict removes the oldest evictable evidence, or the oldest one if all of
+// them are still pending.
+func (p *evidencePool) evict(now int64) {
+	idx := 0
+	for i, e := range p.evidences {
+		if e.evictable(now) {
+			idx = i
+			break
+		}
+	}
+	p.evidences = append(p.evidences[:idx], p.evidences[idx+1:]...)
+}
+
+func (p *evidencePool) pendings() []*Evidence {
+	var res []*Evidence
+	for _, e := range p.evidences {
+		if e.Status == EvidencePending {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+func (p *evidencePool) flush() {
+	bs, err := codec.BC.MarshalToBytes(p.evidences)
+	if err != nil {
+		p.log.Warnf("fail to encode evidences err=%+v", err)
+		return
+	}
+	if err := p.bk.Set([]byte(keyEvidences), bs); err != nil {
+		p.log.Warnf("fail to store evidences err=%+v", err)
+	}
+}
+
+func (p *evidencePool) list() []*Evidence {
+	res := make([]*Evidence, len(p.evidences))
+	for i, e := range p.evidences {
+		ec := *e
+		res[i] = &ec
+	}
+	return res
+}
+
+func loadEvidencePool(dbase db.Database, logger log.Logger) (*evidencePool, error) {
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return nil, err
+	}
+	p := &evidencePool{
+		bk:  bk,
+		log: logger,
+	}
+	bs, err := bk.Get([]byte(keyEvidences))
+	if err != nil {
+		return nil, err
+	}
+	if len(bs) > 0 {
+		if _, err := codec.BC.UnmarshalFromBytes(bs, &p.evidences); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range p.evidences {
+		// reports in the service manager are lost on restart.
+		if e.Status == EvidenceQueued {
+			e.Status = EvidencePending
+			e.Attempts = 0
+		}
+	}
+	return p, nil
+}
+
+func (cs *consensus) reportDoubleSign(dsd []module.DoubleSignData) error {
+	if cs.evidences == nil {
+		return errors.InvalidStateError.New("NoEvidencePool")
+	}
+	e := cs.evidences.add(dsd)
+	if e == nil {
+		return nil
+	}
+	cs.log.Infof("double sign detected type=%s height=%d round=%d signer=%s",
+		e.Type, e.Height, e.Round, e.Signer)
+	cs.relayDoubleSign(dsd)
+	err := cs.submitEvidence(e, dsd)
+	cs.evidences.flush()
+	return err
+}
+
+// relayDoubleSign sends the conflicting messages to validators, so that
+// the next proposer reports it even if it didn't receive them.
+func (cs *consensus) relayDoubleSign(dsd []module.DoubleSignData) {
+	if cs.ph == nil {
+		return
+	}
+	for _, d := range dsd {
+		var err error
+		switch m := d.(type) {
+		case *dsVote:
+			err = cs.ph.Multicast(ProtoVote, m.Bytes(), module.RoleValidator)
+		case *dsProposal:
+			err = cs.ph.Multicast(ProtoProposal, m.Bytes(), module.RoleValidator)
+		}
+		if err != nil {
+			cs.log.Debugf("fail to relay double sign data err=%+v", err)
+		}
+	}
+}
+
+func (cs *consensus) submitEvidence(e *Evidence, dsd []module.DoubleSignData) error {
+	if e.Height > cs.height {
+		// wait for the block to be the base of the report
+		return nil
+	}
+	e.Attempts += 1
+	err := func() error {
+		blk, err := cs.c.BlockManager().GetBlockByHeight(e.Height - 1)
+		if err != nil {
+			return err
+		}
+		return cs.c.ServiceManager().SendDoubleSignReport(blk.Result(), blk.NextValidatorsHash(), dsd)
+	}()
+	if err != nil {
+		e.Error = err.Error()
+		if e.Attempts >= configEvidenceRetryLimit {
+			e.Status = EvidenceFailed
+		}
+		return err
+	}
+	e.Error = ""
+	e.Status = EvidenceQueued
+	return nil
+}
+
+func (cs *consensus) retryEvidences() {
+	if cs.evidences == nil {
+		return
+	}
+	pendings := cs.evidences.pendings()
+	if len(pendings) == 0 {
+		return
+	}
+	for _, e := range pendings {
+		dsd, err := e.doubleSignData()
+		if err != nil {
+			e.Status = EvidenceFailed
+			e.Error = err.Error()
+			continue
+		}
+		if err := cs.submitEvidence(e, dsd); err != nil {
+			cs.log.Debugf("fail to report double sign height=%d signer=%s attempts=%d err=%+v",
+				e.Height, e.Signer, e.Attempts, err)
+		}
+	}
+	cs.evidences.flush()
+}
+
+func (cs *consensus) GetEvidences() []*Evidence {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	if cs.evidences == nil {
+		return nil
+	}
+	return cs.evidences.list()
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestEvidencePool_Persistence(t *testing.T) {
+	dbase := db.NewMapDB()
+	p, err := loadEvidencePool(dbase, log.GlobalLogger())
+	assert.NoError(t, err)
+
+	w := wallet.New()
+	psid := &PartSetID{Count: 1, Hash: make([]byte, 32)}
+	vote1 := NewVoteMessage(w, VoteTypePrecommit, 10, 2, []byte{1}, psid, 1000, nil, nil, 0)
+	vote2 := NewVoteMessage(w, VoteTypePrecommit, 10, 2, []byte{2}, psid, 1000, nil, nil, 0)
+	dsd := []module.DoubleSignData{&dsVote{vote1}, &dsVote{vote2}}
+
+	e := p.add(dsd)
+	assert.NotNil(t, e)
+	assert.Nil(t, p.add(dsd))
+	e.Status = EvidenceQueued
+	e.Attempts = 1
+	p.flush()
+
+	p, err = loadEvidencePool(dbase, log.GlobalLogger())
+	assert.NoError(t, err)
+	evidences := p.list()
+	assert.Equal(t, 1, len(evidences))
+	e = evidences[0]
+	assert.EqualValues(t, 10, e.Height)
+	assert.EqualValues(t, 2, e.Round)
+	assert.True(t, w.Address().Equal(e.Signer))
+	assert.Equal(t, EvidencePending, e.Status)
+	assert.Equal(t, 0, e.Attempts)
+
+	data, err := e.doubleSignData()
+	assert.NoError(t, err)
+	assert.True(t, data[0].IsConflictWith(data[1]))
+}
+
+func TestEvidencePool_Eviction(t *testing.T) {
+	p, err := loadEvidencePool(db.NewMapDB(), log.GlobalLogger())
+	assert.NoError(t, err)
+
+	psid := &PartSetID{Count: 1, Hash: make([]byte, 32)}
+	newDSD := func(w module.Wallet, height int64) []module.DoubleSignData {
+		vote1 := NewVoteMessage(w, VoteTypePrecommit, height, 0, []byte{1}, psid, 1000, nil, nil, 0)
+		vote2 := NewVoteMessage(w, VoteTypePrecommit, height, 0, []byte{2}, psid, 1000, nil, nil, 0)
+		return []module.DoubleSignData{&dsVote{vote1}, &dsVote{vote2}}
+	}
+
+	w := wallet.New()
+	for i := 0; i < configEvidencePoolSize; i++ {
+		assert.NotNil(t, p.add(newDSD(w, int64(i+1))))
+	}
+	assert.Equal(t, configEvidencePoolSize, len(p.evidences))
+
+	// queued one is evicted before pending ones
+	p.evidences[10].Status = EvidenceQueued
+	assert.NotNil(t, p.add(newDSD(w, 1000)))
+	assert.Equal(t, configEvidencePoolSize, len(p.evidences))
+	assert.EqualValues(t, 1, p.evidences[0].Height)
+	for _, e := range p.evidences {
+		assert.NotEqual(t, EvidenceQueued, e.Status)
+	}
+
+	// expired one is evicted before others
+	p.evidences[20].Detected -= (configEvidenceExpiration + time.Minute).Microseconds()
+	expired := p.evidences[20].Height
+	assert.NotNil(t, p.add(newDSD(w, 1001)))
+	assert.EqualValues(t, 1, p.evidences[0].Height)
+	for _, e := range p.evidences {
+		assert.NotEqual(t, expired, e.Height)
+	}
+
+	// the oldest one is evicted if all of them are pending
+	assert.NotNil(t, p.add(newDSD(w, 1002)))
+	assert.EqualValues(t, 2, p.evidences[0].Height)
+	assert.Equal(t, configEvidencePoolSize, len(p.evidences))
+}
@@ -0,0 +1,239 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+// reasons of step transitions
+const (
+	reasonStart            = "start"
+	reasonProposal         = "proposal"
+	reasonBlockPart        = "blockPart"
+	reasonPrevote          = "prevote"
+	reasonPrecommit        = "precommit"
+	reasonProposed         = "proposed"
+	reasonProposeFail      = "proposeFail"
+	reasonTransaction      = "transaction"
+	reasonWaitDone         = "waitDone"
+	reasonTimeoutPropose   = "timeoutPropose"
+	reasonTimeoutPrevote   = "timeoutPrevote"
+	reasonTimeoutPrecommit = "timeoutPrecommit"
+	reasonSync             = "sync"
+)
+
+const configRoundEventHistory = 64
+
+var stepNames = map[step]string{
+	stepNewHeight:       "newHeight",
+	stepTransactionWait: "transactionWait",
+	stepNewRound:        "newRound",
+	stepPropose:         "propose",
+	stepPrevote:         "prevote",
+	stepPrevoteWait:     "prevoteWait",
+	stepPrecommit:       "precommit",
+	stepPrecommitWait:   "precommitWait",
+	stepCommit:          "commit",
+}
+
+// RoundEvent is a step transition of consensus.
+type RoundEvent struct {
+	Height int64
+	Round  int32
+	Step   string
+	// Reason is the event made the transition. It's one of start, proposal,
+	// blockPart, prevote, precommit, proposed, proposeFail, transaction,
+	// waitDone, timeoutPropose, timeoutPrevote, timeoutPrecommit and sync.
+	Reason    string
+	Timestamp time.Time
+	Proposer  module.Address
+	// Prevotes and Precommits are masks of validators whose votes for the
+	// round have arrived, like "1101".
+	Prevotes   string
+	Precommits string
+}
+
+// RoundStatus is the detailed status of the current round.
+type RoundStatus struct {
+	Height      int64
+	Round       int32
+	Step        string
+	Proposer    module.Address
+	Validators  []module.Address
+	Prevotes    string
+	Precommits  string
+	BlockParts  string
+	LockedRound int32
+	Sync        bool
+	// Events are the recent step transitions in the height.
+	Events []*RoundEvent
+}
+
+// RoundStateInspector provides the status of rounds and step transitions.
+type RoundStateInspector interface {
+	GetRoundStatus() *RoundStatus
+
+	// AddRoundEventListener adds the listener for step transitions, and
+	// returns the function to remove it. The listener is called in the lock
+	// of consensus, so it must not block.
+	AddRoundEventListener(l func(ev *RoundEvent)) func()
+}
+
+func RoundStateInspectorOf(c module.Chain) (RoundStateInspector, error) {
+	if cs := c.Consensus(); cs != nil {
+		if rsi, ok := cs.(RoundStateInspector); ok {
+			return rsi, nil
+		}
+	}
+	return nil, errors.UnsupportedError.New("NoRoundStateInspector")
+}
+
+type roundEventListener struct {
+	cb func(ev *RoundEvent)
+}
+
+// RoundEventListeners is the list of listeners for step transitions.
+type RoundEventListeners struct {
+	lock      sync.Mutex
+	listeners []*roundEventListener
+}
+
+func (ls *RoundEventListeners) Add(cb func(ev *RoundEvent)) func() {
+	ls.lock.Lock()
+	defer ls.lock.Unlock()
+
+	l := &roundEventListener{cb}
+	ls.listeners = append(ls.listeners, l)
+	return func() {
+		ls.lock.Lock()
+		defer ls.lock.Unlock()
+
+		// make a new slice, because Notify may be iterating the old one.
+		listeners := make([]*roundEventListener, 0, len(ls.listeners))
+		for _, li := range ls.listeners {
+			if li != l {
+				listeners = append(listeners, li)
+			}
+		}
+		ls.listeners = listeners
+	}
+}
+
+func (ls *RoundEventListeners) Notify(ev *RoundEvent) {
+	ls.lock.Lock()
+	listeners := ls.listeners
+	ls.lock.Unlock()
+
+	for _, l := range listeners {
+		l.cb(ev)
+	}
+}
+
+func maskString(ba *BitArray) string {
+	if ba == nil {
+		return ""
+	}
+	bs := make([]byte, ba.Len())
+	for i := range bs {
+		if ba.Get(i) {
+			bs[i] = '1'
+		} else {
+			bs[i] = '0'
+		}
+	}
+	return string(bs)
+}
+
+func (cs *consensus) roundProposer() module.Address {
+	if cs.validators == nil || cs.validators.Len() == 0 {
+		return nil
+	}
+	v, _ := cs.validators.Get(cs.getProposerIndex(cs.height, cs.round))
+	if v == nil {
+		return nil
+	}
+	return v.Address()
+}
+
+func (cs *consensus) setReason(reason string) {
+	cs.reason = reason
+}
+
+func (cs *consensus) onStepChange() {
+	if cs.validators == nil {
+		return
+	}
+	ev := &RoundEvent{
+		Height:     cs.height,
+		Round:      cs.round,
+		Step:       stepNames[cs.step],
+		Reason:     cs.reason,
+		Timestamp:  time.Now(),
+		Proposer:   cs.roundProposer(),
+		Prevotes:   maskString(cs.hvs.votesFor(cs.round, VoteTypePrevote).getMask()),
+		Precommits: maskString(cs.hvs.votesFor(cs.round, VoteTypePrecommit).getMask()),
+	}
+	if cs.step == stepNewHeight {
+		cs.roundEvents = cs.roundEvents[:0]
+	} else if len(cs.roundEvents) >= configRoundEventHistory {
+		copy(cs.roundEvents, cs.roundEvents[1:])
+		cs.roundEvents = cs.roundEvents[:len(cs.roundEvents)-1]
+	}
+	cs.roundEvents = append(cs.roundEvents, ev)
+	cs.roundListeners.Notify(ev)
+}
+
+func (cs *consensus) GetRoundStatus() *RoundStatus {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	rs := &RoundStatus{
+		Height:      cs.height,
+		Round:       cs.round,
+		Step:        stepNames[cs.step],
+		LockedRound: cs.lockedRound,
+		Sync:        cs.syncing,
+	}
+	if cs.validators == nil {
+		return rs
+	}
+	rs.Proposer = cs.roundProposer()
+	rs.Validators = make([]module.Address, cs.validators.Len())
+	for i := range rs.Validators {
+		if v, ok := cs.validators.Get(i); ok {
+			rs.Validators[i] = v.Address()
+		}
+	}
+	prs := cs.GetRoundState()
+	rs.Prevotes = maskString(prs.PrevotesMask)
+	rs.Precommits = maskString(prs.PrecommitsMask)
+	if !cs.currentBlockParts.IsZero() {
+		rs.BlockParts = maskString(cs.currentBlockParts.GetMask())
+	}
+	rs.Events = make([]*RoundEvent, len(cs.roundEvents))
+	copy(rs.Events, cs.roundEvents)
+	return rs
+}
+
+func (cs *consensus) AddRoundEventListener(l func(ev *RoundEvent)) func() {
+	return cs.roundListeners.Add(l)
+}
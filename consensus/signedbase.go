@@ -4,6 +4,7 @@ import (
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/wallet"
 	"github.com/icon-project/goloop/module"
 )
 
@@ -56,9 +57,20 @@ func (s *signedBase) verify() error {
 }
 
 func (s *signedBase) Sign(wallet module.Wallet) error {
+	return s.signFor(wallet, nil)
+}
+
+// signFor signs with the position of the message, if the wallet needs it.
+func (s *signedBase) signFor(w module.Wallet, sc *wallet.SignContext) error {
 	s._hash = nil
 	s._publicKey = nil
-	sigBS, err := wallet.Sign(s.hash())
+	var sigBS []byte
+	var err error
+	if cs, ok := w.(wallet.ConsensusSigner); ok && sc != nil {
+		sigBS, err = cs.SignConsensus(s.hash(), sc)
+	} else {
+		sigBS, err = w.Sign(s.hash())
+	}
 	if err != nil {
 		return errors.Errorf("sendVote : %v", err)
 	}
@@ -23,6 +23,7 @@ import (
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/codec"
 	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/wallet"
 	"github.com/icon-project/goloop/module"
 )
 
@@ -45,6 +46,13 @@ func (vt VoteType) String() string {
 	}
 }
 
+func signStepFor(vt VoteType) wallet.SignStep {
+	if vt == VoteTypePrecommit {
+		return wallet.SignStepPrecommit
+	}
+	return wallet.SignStepPrevote
+}
+
 type blockVoteBase struct {
 	_HR
 	Type                          VoteType
@@ -423,27 +423,45 @@ func (w *walReader) CloseAndRepair() error {
 		return err
 	}
 
+	for _, a := range w.repairActions() {
+		if a.Size < 0 {
+			if err := os.Remove(a.File); err != nil && !os.IsNotExist(err) {
+				return errors.WithStack(err)
+			}
+		} else {
+			if err := os.Truncate(a.File, a.Size); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+	return nil
+}
+
+// repairActions returns actions to remove data after the last valid entry.
+func (w *walReader) repairActions() []*WALRepairAction {
+	var actions []*WALRepairAction
 	left := w.validOffset
 	idx := w.wi.headIdx
 	for _, s := range w.wi.fileSizes {
 		if left <= s {
 			if left < s {
-				err := os.Truncate(fileFor(w.id, idx), left)
-				if err != nil {
-					return errors.WithStack(err)
-				}
+				actions = append(actions, &WALRepairAction{
+					File: fileFor(w.id, idx),
+					Size: left,
+				})
 			}
 			for i := idx + 1; i <= w.wi.tailIdx; i++ {
-				if err := os.Remove(fileFor(w.id, idx)); err != nil {
-					return errors.WithStack(err)
-				}
+				actions = append(actions, &WALRepairAction{
+					File: fileFor(w.id, i),
+					Size: -1,
+				})
 			}
-			return nil
+			return actions
 		}
 		left -= s
 		idx++
 	}
-	return nil
+	return actions
 }
 
 func IsCorruptedWAL(err error) bool {
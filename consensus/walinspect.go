@@ -0,0 +1,306 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"path"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+// WALNames returns names of WALs written by consensus in the WAL directory
+// of the chain.
+func WALNames() []string {
+	return []string{configRoundWALID, configLockWALID, configCommitWALID}
+}
+
+// WALInspectOption is the option for InspectWAL.
+type WALInspectOption struct {
+	// NID is the network ID of the chain. If it's zero, NID of messages are
+	// not checked.
+	NID uint32
+	// Validators is the list of validators. If it's empty, signers of
+	// messages are not checked.
+	Validators []module.Address
+	// Repair truncates corrupted tail of the WAL. Otherwise, it only
+	// reports the actions for the repair.
+	Repair bool
+}
+
+// WALRecord is a decoded entry of the WAL.
+type WALRecord struct {
+	Offset  int64       `json:"offset"`
+	Size    int         `json:"size"`
+	Type    string      `json:"type"`
+	Message interface{} `json:"message,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// WALRepairAction is an action to repair corrupted tail of the WAL.
+// Size is -1 if the file is removed. Otherwise, the file is truncated
+// to the size.
+type WALRepairAction struct {
+	File string `json:"file"`
+	Size int64  `json:"size"`
+}
+
+// WALInspectResult is the summary of InspectWAL.
+type WALInspectResult struct {
+	ID        string             `json:"id"`
+	Records   int                `json:"records"`
+	Invalid   int                `json:"invalid"`
+	ValidSize int64              `json:"validSize"`
+	TotalSize int64              `json:"totalSize"`
+	Corrupted string             `json:"corrupted,omitempty"`
+	Repair    []*WALRepairAction `json:"repair,omitempty"`
+	Repaired  bool               `json:"repaired"`
+}
+
+type walVerifyContext struct {
+	nid uint32
+}
+
+func (ctx *walVerifyContext) ValidNID(nid uint32) bool {
+	return ctx.nid == 0 || nid == 0 || nid == ctx.nid
+}
+
+func (ctx *walVerifyContext) NID() int {
+	return int(ctx.nid)
+}
+
+type walValidators []module.Address
+
+func (vs walValidators) check(addr module.Address) error {
+	if len(vs) == 0 {
+		return nil
+	}
+	if addr == nil {
+		return errors.New("bad signature")
+	}
+	for _, v := range vs {
+		if v.Equal(addr) {
+			return nil
+		}
+	}
+	return errors.Errorf("not a validator %v", addr)
+}
+
+func addressOf(s *signedBase) *common.Address {
+	if s.publicKey() == nil {
+		return nil
+	}
+	return s.address()
+}
+
+func partSetIDJSON(id *PartSetID) interface{} {
+	if id == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"count": id.Count,
+		"hash":  common.HexBytes(id.Hash),
+	}
+}
+
+func voteJSON(msg *VoteMessage) map[string]interface{} {
+	nid, _ := msg.NID()
+	jso := map[string]interface{}{
+		"type":      msg.Type.String(),
+		"height":    msg.Height,
+		"round":     msg.Round,
+		"blockID":   common.HexBytes(msg.BlockID),
+		"timestamp": msg.Timestamp,
+		"nid":       nid,
+		"signer":    addressOf(&msg.signedBase),
+	}
+	if psid := msg.BlockPartSetIDAndNTSVoteCount; psid != nil {
+		_, ntsVoteCount := destructPSIDAppData(psid.AppData())
+		jso["blockPartSetID"] = partSetIDJSON(psid.ID())
+		jso["ntsVoteCount"] = ntsVoteCount
+	}
+	if len(msg.NTSVoteBases) > 0 {
+		ntsVotes := make([]interface{}, len(msg.NTSVoteBases))
+		for i, nvb := range msg.NTSVoteBases {
+			ntsVotes[i] = map[string]interface{}{
+				"networkTypeID":          nvb.NetworkTypeID,
+				"networkTypeSectionHash": common.HexBytes(nvb.NetworkTypeSectionHash),
+			}
+		}
+		jso["ntsVotes"] = ntsVotes
+	}
+	return jso
+}
+
+// walMessageJSON returns human-readable form of the message and verifies
+// the message.
+func walMessageJSON(msg Message, ctx *walVerifyContext, vs walValidators) (interface{}, error) {
+	switch m := msg.(type) {
+	case *ProposalMessage:
+		jso := map[string]interface{}{
+			"height":         m.Height,
+			"round":          m.Round,
+			"blockPartSetID": partSetIDJSON(m.BlockPartSetID),
+			"polRound":       m.POLRound,
+			"nid":            m.NID,
+			"signer":         addressOf(&m.signedBase),
+		}
+		if err := m.Verify(ctx); err != nil {
+			return jso, err
+		}
+		return jso, vs.check(m.address())
+	case *BlockPartMessage:
+		jso := map[string]interface{}{
+			"height": m.Height,
+			"index":  m.Index,
+			"size":   len(m.BlockPart),
+			"nonce":  m.Nonce,
+		}
+		return jso, m.Verify(ctx)
+	case *VoteMessage:
+		jso := voteJSON(m)
+		if err := m.Verify(ctx); err != nil {
+			return jso, err
+		}
+		return jso, vs.check(m.address())
+	case *VoteListMessage:
+		if m.VoteList == nil {
+			return nil, errors.New("nil VoteList")
+		}
+		if err := m.VoteList.Verify(ctx); err != nil {
+			return nil, err
+		}
+		votes := make([]interface{}, m.VoteList.Len())
+		var err error
+		for i := 0; i < m.VoteList.Len(); i++ {
+			v := m.VoteList.Get(i)
+			votes[i] = voteJSON(v)
+			if e := vs.check(v.address()); e != nil && err == nil {
+				err = e
+			}
+		}
+		return map[string]interface{}{"votes": votes}, err
+	case *RoundStateMessage:
+		return map[string]interface{}{
+			"height":    m.Height,
+			"round":     m.Round,
+			"prevote":   fmt.Sprint(m.PrevotesMask),
+			"precommit": fmt.Sprint(m.PrecommitsMask),
+			"blockPart": fmt.Sprint(m.BlockPartsMask),
+			"sync":      m.Sync,
+		}, m.Verify(ctx)
+	default:
+		return nil, errors.Errorf("unknown message %T", msg)
+	}
+}
+
+func walMessageType(sp uint16) string {
+	switch module.ProtocolInfo(sp) {
+	case ProtoProposal:
+		return "proposal"
+	case ProtoBlockPart:
+		return "blockPart"
+	case ProtoVote:
+		return "vote"
+	case ProtoRoundState:
+		return "roundState"
+	case ProtoVoteList:
+		return "voteList"
+	default:
+		return "unknown"
+	}
+}
+
+// InspectWAL reads the WAL named by id in dir, and calls cb with each
+// decoded entry. Entries failing to decode or verify are reported with
+// the error, and the inspection continues. Corrupted tail of the WAL is
+// truncated only if opt.Repair is set.
+func InspectWAL(dir, id string, opt *WALInspectOption, cb func(r *WALRecord) error) (*WALInspectResult, error) {
+	wr, err := OpenWALForRead(path.Join(dir, id))
+	if err != nil {
+		return nil, err
+	}
+	r := wr.(*walReader)
+	defer func() {
+		_ = r.Close()
+	}()
+	if opt == nil {
+		opt = &WALInspectOption{}
+	}
+	ctx := &walVerifyContext{nid: opt.NID}
+	vs := walValidators(opt.Validators)
+
+	res := &WALInspectResult{ID: id}
+	for _, s := range r.wi.fileSizes {
+		res.TotalSize += s
+	}
+	for {
+		offset := r.validOffset
+		bs, err := r.ReadBytes()
+		if IsEOF(err) {
+			break
+		} else if IsCorruptedWAL(err) {
+			res.Corrupted = fmt.Sprintf("bad crc at offset %d", offset)
+			break
+		} else if IsUnexpectedEOF(err) {
+			res.Corrupted = fmt.Sprintf("unexpected EOF at offset %d", offset)
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		res.Records += 1
+		rec := &WALRecord{
+			Offset: offset,
+			Size:   len(bs),
+		}
+		if len(bs) < 2 {
+			rec.Type = "unknown"
+			rec.Error = errors.Errorf("too short wal message len=%v", len(bs)).Error()
+		} else {
+			sp := binary.BigEndian.Uint16(bs[0:2])
+			rec.Type = walMessageType(sp)
+			if msg, err := UnmarshalMessage(sp, bs[2:]); err != nil {
+				rec.Error = err.Error()
+			} else {
+				rec.Message, err = walMessageJSON(msg, ctx, vs)
+				if err != nil {
+					rec.Error = err.Error()
+				}
+			}
+		}
+		if len(rec.Error) > 0 {
+			res.Invalid += 1
+		}
+		if err := cb(rec); err != nil {
+			return nil, err
+		}
+	}
+	res.ValidSize = r.validOffset
+	if len(res.Corrupted) > 0 {
+		res.Repair = r.repairActions()
+		if opt.Repair {
+			if err := r.CloseAndRepair(); err != nil {
+				return nil, err
+			}
+			res.Repaired = true
+		}
+	}
+	return res, nil
+}
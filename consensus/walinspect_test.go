@@ -0,0 +1,106 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+)
+
+func writeTestWAL(t *testing.T, dir string, msgs ...Message) {
+	ww, err := OpenWALForWrite(path.Join(dir, configRoundWALID), &WALConfig{})
+	assert.NoError(t, err)
+	mw := &WalMessageWriter{ww}
+	for _, msg := range msgs {
+		assert.NoError(t, mw.WriteMessage(msg))
+	}
+	assert.NoError(t, ww.Close())
+}
+
+func inspectTestWAL(t *testing.T, dir string, opt *WALInspectOption) ([]*WALRecord, *WALInspectResult) {
+	var records []*WALRecord
+	res, err := InspectWAL(dir, configRoundWALID, opt, func(r *WALRecord) error {
+		records = append(records, r)
+		return nil
+	})
+	assert.NoError(t, err)
+	return records, res
+}
+
+func TestInspectWAL(t *testing.T) {
+	dir := t.TempDir()
+	w1 := wallet.New()
+	w2 := wallet.New()
+
+	psid := &PartSetID{Count: 1, Hash: make([]byte, 32)}
+	proposal := NewProposalMessage()
+	proposal.Height = 10
+	proposal.Round = 1
+	proposal.BlockPartSetID = psid
+	proposal.POLRound = -1
+	assert.NoError(t, proposal.Sign(w1))
+
+	vote1 := NewVoteMessage(w1, VoteTypePrevote, 10, 1, make([]byte, 32), psid, 1000, nil, nil, 0)
+	vote2 := NewVoteMessage(w2, VoteTypePrevote, 10, 1, make([]byte, 32), psid, 1001, nil, nil, 0)
+	vl := NewVoteList()
+	vl.AddVote(vote1)
+	vl.AddVote(vote2)
+	writeTestWAL(t, dir, proposal, vote1, &VoteListMessage{VoteList: vl})
+
+	records, res := inspectTestWAL(t, dir, &WALInspectOption{
+		Validators: []module.Address{w1.Address()},
+	})
+	assert.Equal(t, 3, len(records))
+	assert.Equal(t, "proposal", records[0].Type)
+	assert.Empty(t, records[0].Error)
+	assert.Equal(t, "vote", records[1].Type)
+	assert.Empty(t, records[1].Error)
+	assert.Equal(t, "voteList", records[2].Type)
+	assert.Contains(t, records[2].Error, "not a validator")
+	assert.Equal(t, 1, res.Invalid)
+	assert.Empty(t, res.Corrupted)
+	assert.Equal(t, res.TotalSize, res.ValidSize)
+
+	// append broken entry
+	f, err := os.OpenFile(fileFor(path.Join(dir, configRoundWALID), 0), os.O_WRONLY|os.O_APPEND, walPermission)
+	assert.NoError(t, err)
+	_, err = f.Write([]byte{0, 0, 0, 0, 0, 0, 0, 10, 1, 2})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	records, res = inspectTestWAL(t, dir, nil)
+	assert.Equal(t, 3, len(records))
+	assert.NotEmpty(t, res.Corrupted)
+	assert.Equal(t, res.TotalSize-10, res.ValidSize)
+	assert.Equal(t, 1, len(res.Repair))
+	assert.Equal(t, res.ValidSize, res.Repair[0].Size)
+	assert.False(t, res.Repaired)
+
+	_, res = inspectTestWAL(t, dir, &WALInspectOption{Repair: true})
+	assert.True(t, res.Repaired)
+
+	records, res = inspectTestWAL(t, dir, nil)
+	assert.Equal(t, 3, len(records))
+	assert.Empty(t, res.Corrupted)
+	assert.Equal(t, res.TotalSize, res.ValidSize)
+}
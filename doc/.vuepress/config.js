@@ -29,6 +29,12 @@ module.exports = {
                     '/btp_extension',
                 ]
             },
+            {
+                title: 'Execution Environment',
+                children: [
+                    '/wasm_ee',
+                ]
+            },
             {
                 title: 'Management',
                 children: [
@@ -386,6 +386,30 @@ var chainMethods = []*chainMethod{
 			scoreapi.Dict,
 		},
 	}, icmodule.RevisionIISS, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "queryIScoreHistory",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"address", scoreapi.Address, nil, nil},
+			{"startHeight", scoreapi.Integer, nil, nil},
+			{"endHeight", scoreapi.Integer, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionIScoreHistory, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getPRepHistory",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"address", scoreapi.Address, nil, nil},
+			{"startHeight", scoreapi.Integer, nil, nil},
+			{"endHeight", scoreapi.Integer, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionPRepHistory, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "registerPRep",
 		scoreapi.FlagExternal | scoreapi.FlagPayable, 7,
@@ -19,6 +19,7 @@ package icon
 import (
 	"bytes"
 	"encoding/hex"
+	"math"
 	"math/big"
 
 	"github.com/icon-project/goloop/common"
@@ -466,6 +467,40 @@ func (s *chainScore) Ex_queryIScore(address module.Address) (map[string]interfac
 	return jso, nil
 }
 
+func (s *chainScore) Ex_queryIScoreHistory(address module.Address, startHeight, endHeight *common.HexInt) (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	if err := s.checkQueryMode(); err != nil {
+		return nil, err
+	}
+	start, end := int64(0), int64(math.MaxInt64)
+	if startHeight != nil {
+		start = startHeight.Int64()
+	}
+	if endHeight != nil {
+		end = endHeight.Int64()
+	}
+	if start < 0 || start > end {
+		return nil, scoreresult.InvalidParameterError.Errorf(
+			"Invalid height range: start=%d end=%d", start, end,
+		)
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	history, err := es.GetIScoreHistory(address, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	jso := make(map[string]interface{})
+	jso["address"] = address
+	jso["history"] = history
+	return jso, nil
+}
+
 func (s *chainScore) Ex_estimateUnstakeLockPeriod() (map[string]interface{}, error) {
 	if err := s.tryChargeCall(true); err != nil {
 		return nil, err
@@ -570,6 +605,40 @@ func (s *chainScore) Ex_getPRepStats() (map[string]interface{}, error) {
 	return es.GetPRepStats(s.newCallContext(s.cc))
 }
 
+func (s *chainScore) Ex_getPRepHistory(address module.Address, startHeight, endHeight *common.HexInt) (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	if err := s.checkQueryMode(); err != nil {
+		return nil, err
+	}
+	start, end := int64(0), int64(math.MaxInt64)
+	if startHeight != nil {
+		start = startHeight.Int64()
+	}
+	if endHeight != nil {
+		end = endHeight.Int64()
+	}
+	if start < 0 || start > end {
+		return nil, scoreresult.InvalidParameterError.Errorf(
+			"Invalid height range: start=%d end=%d", start, end,
+		)
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	history, err := es.GetPRepHistory(address, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	jso := make(map[string]interface{})
+	jso["address"] = address
+	jso["history"] = history
+	return jso, nil
+}
+
 func (s *chainScore) Ex_getPRepStatsOf(address module.Address) (map[string]interface{}, error) {
 	if err := s.tryChargeCall(true); err != nil {
 		return nil, err
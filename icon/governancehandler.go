@@ -51,6 +51,15 @@ func (g *governanceHandler) TraceLogger() *trace.Logger {
 	return g.log
 }
 
+func (g *governanceHandler) TraceCallInfo() *module.TraceCallInfo {
+	if p, ok := g.ch.(interface {
+		TraceCallInfo() *module.TraceCallInfo
+	}); ok {
+		return p.TraceCallInfo()
+	}
+	return &module.TraceCallInfo{}
+}
+
 func applyGovernanceVariablesToSystem(cc contract.CallContext, govAs, sysAs containerdb.BytesStoreState) error {
 	price := scoredb.NewVarDB(govAs, state.VarStepPrice).Int64()
 	if price == 0 {
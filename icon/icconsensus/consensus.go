@@ -40,6 +40,7 @@ type wrapper struct {
 	merkleHeader   *hexary.MerkleHeader
 	lastVoteData   *consensus.LastVoteData
 	timeoutPropose time.Duration
+	listeners      consensus.RoundEventListeners
 }
 
 func New(
@@ -88,6 +89,7 @@ func (c *wrapper) Start() error {
 		c.Consensus = consensus.New(
 			c.c, c.walDir, c.wm, c.timestamper, bpp, c.lastVoteData, c.timeoutPropose,
 		)
+		c.forwardRoundEvents()
 	}
 	return c.Consensus.Start()
 }
@@ -127,6 +129,7 @@ func (c *wrapper) Upgrade(bpp *bpp) {
 
 	c.Consensus.Term()
 	c.Consensus = consensus.New(c.c, c.walDir, c.wm, c.timestamper, bpp, c.lastVoteData, c.timeoutPropose)
+	c.forwardRoundEvents()
 	err := c.Consensus.Start()
 	if err != nil {
 		c.c.Logger().Panicf("fail to start consensus %+v", err)
@@ -141,3 +144,35 @@ func (c *wrapper) Term() {
 		c.Consensus.Term()
 	}
 }
+
+// forwardRoundEvents forwards step transitions of the consensus to the
+// listeners of the wrapper, so they are kept on upgrade.
+func (c *wrapper) forwardRoundEvents() {
+	if rsi, ok := c.Consensus.(consensus.RoundStateInspector); ok {
+		rsi.AddRoundEventListener(c.listeners.Notify)
+	}
+}
+
+func (c *wrapper) GetRoundStatus() *consensus.RoundStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if rsi, ok := c.Consensus.(consensus.RoundStateInspector); ok {
+		return rsi.GetRoundStatus()
+	}
+	return nil
+}
+
+func (c *wrapper) GetEvidences() []*consensus.Evidence {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ei, ok := c.Consensus.(consensus.EvidenceInspector); ok {
+		return ei.GetEvidences()
+	}
+	return nil
+}
+
+func (c *wrapper) AddRoundEventListener(l func(ev *consensus.RoundEvent)) func() {
+	return c.listeners.Add(l)
+}
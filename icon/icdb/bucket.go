@@ -26,4 +26,12 @@ const (
 	// BlockMerkle basically maps node hash to block merkle node for v1 block.
 	// In addition, it also has merkleTreeData.
 	BlockMerkle db.BucketID = "H"
+
+	// RewardHistory maps account and term to the reward of the account for
+	// the term. It's local data of the node, not a part of the state.
+	RewardHistory db.BucketID = "R"
+
+	// PRepHistory maps P-Rep and height to events of the P-Rep at the
+	// height. It's local data of the node, not a part of the state.
+	PRepHistory db.BucketID = "P"
 )
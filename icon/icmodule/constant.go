@@ -39,6 +39,10 @@ var (
 
 const (
 	ConfigFile               = "./icon_config.json"
+	EnvRewardHistory         = "ICON_REWARD_HISTORY"
+	MaxIScoreHistory         = 100
+	EnvPRepHistory           = "ICON_PREP_HISTORY"
+	MaxPRepHistory           = 100
 	IScoreICXRatio           = 1_000
 	VotedRewardMultiplier    = 100
 	InitialTermPeriod        = DayBlock
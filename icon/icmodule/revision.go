@@ -48,6 +48,7 @@ const (
 	Revision26
 	Revision27
 	Revision28
+	Revision29
 	RevisionReserved
 )
 
@@ -129,6 +130,9 @@ const (
 	RevisionRecoverUnderIssuance = Revision27
 
 	RevisionSetBondRequirementRate = Revision28
+
+	RevisionIScoreHistory = Revision29
+	RevisionPRepHistory   = Revision29
 )
 
 var revisionFlags []module.Revision
@@ -0,0 +1,318 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icsim
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/containerdb"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/icon/iiss"
+	"github.com/icon-project/goloop/icon/iiss/icobject"
+	"github.com/icon-project/goloop/icon/iiss/icstage"
+	"github.com/icon-project/goloop/icon/iiss/icstate"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+	"github.com/icon-project/goloop/module"
+)
+
+var stageEventTypeNames = map[int]string{
+	icstage.TypeEventDelegation:   "delegation",
+	icstage.TypeEventBond:         "bond",
+	icstage.TypeEventEnable:       "enable",
+	icstage.TypeEventVotedReward:  "votedReward",
+	icstage.TypeEventDelegationV2: "delegationV2",
+	icstage.TypeEventDelegated:    "delegated",
+}
+
+type addressFilter map[string]bool
+
+func newAddressFilter(addrs []module.Address) addressFilter {
+	if len(addrs) == 0 {
+		return nil
+	}
+	f := make(addressFilter)
+	for _, addr := range addrs {
+		f[icutils.ToKey(addr)] = true
+	}
+	return f
+}
+
+func (f addressFilter) has(addrs ...module.Address) bool {
+	if f == nil {
+		return true
+	}
+	for _, addr := range addrs {
+		if addr != nil && f[icutils.ToKey(addr)] {
+			return true
+		}
+	}
+	return false
+}
+
+func addressesOfVotes(votes icstage.VoteList) []module.Address {
+	addrs := make([]module.Address, len(votes))
+	for i, v := range votes {
+		addrs[i] = v.To()
+	}
+	return addrs
+}
+
+// addressesOfEvent returns addresses related to the event.
+func addressesOfEvent(obj *icobject.Object) []module.Address {
+	switch obj.Tag().Type() {
+	case icstage.TypeEventDelegation, icstage.TypeEventBond, icstage.TypeEventDelegated:
+		e := icstage.ToEventVote(obj)
+		return append(addressesOfVotes(e.Votes()), e.From())
+	case icstage.TypeEventDelegationV2:
+		e := icstage.ToEventDelegationV2(obj)
+		addrs := append(addressesOfVotes(e.Delegated()), addressesOfVotes(e.Delegating())...)
+		return append(addrs, e.From())
+	case icstage.TypeEventEnable:
+		return []module.Address{icstage.ToEventEnable(obj).Target()}
+	}
+	return nil
+}
+
+func addressOfKey(key []byte) (module.Address, error) {
+	keys, err := containerdb.SplitKeys(key)
+	if err != nil {
+		return nil, err
+	}
+	return common.NewAddress(keys[1])
+}
+
+func dumpStage(ss *icstage.Snapshot, f addressFilter) (map[string]interface{}, error) {
+	jso := make(map[string]interface{})
+	if g, err := ss.GetGlobal(); err != nil {
+		return nil, err
+	} else if g != nil {
+		jso["global"] = fmt.Sprintf("%+v", g)
+	}
+	if validators, err := ss.GetValidators(); err != nil {
+		return nil, err
+	} else {
+		vl := make([]interface{}, len(validators))
+		for i, v := range validators {
+			vl[i] = v
+		}
+		jso["validators"] = vl
+	}
+
+	claims := make(map[string]interface{})
+	for iter := ss.Filter(icstage.IScoreClaimKey.Build()); iter.Has(); iter.Next() {
+		o, key, err := iter.Get()
+		if err != nil {
+			return nil, err
+		}
+		addr, err := addressOfKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if f.has(addr) {
+			claims[addr.String()] = icstage.ToIScoreClaim(o).Value()
+		}
+	}
+	jso["claims"] = claims
+
+	rates := make(map[string]interface{})
+	for iter := ss.Filter(icstage.CommissionRateKey.Build()); iter.Has(); iter.Next() {
+		o, key, err := iter.Get()
+		if err != nil {
+			return nil, err
+		}
+		addr, err := addressOfKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if f.has(addr) {
+			rates[addr.String()] = o.(*icobject.Object).Real().(*icstage.CommissionRate).Value().NumInt64()
+		}
+	}
+	jso["commissionRates"] = rates
+
+	events := make(map[string]interface{})
+	for iter := ss.Filter(icstage.EventKey.Build()); iter.Has(); iter.Next() {
+		o, key, err := iter.Get()
+		if err != nil {
+			return nil, err
+		}
+		obj := o.(*icobject.Object)
+		if !f.has(addressesOfEvent(obj)...) {
+			continue
+		}
+		keys, err := containerdb.SplitKeys(key)
+		if err != nil {
+			return nil, err
+		}
+		offset := intconv.BytesToInt64(keys[1])
+		index := intconv.BytesToInt64(keys[2])
+		events[fmt.Sprintf("%d/%d", offset, index)] = map[string]interface{}{
+			"offset": offset,
+			"type":   stageEventTypeNames[obj.Tag().Type()],
+			"value":  fmt.Sprintf("%+v", obj.Real()),
+		}
+	}
+	jso["events"] = events
+
+	if f == nil {
+		bps := make(map[string]interface{})
+		for iter := ss.Filter(icstage.BlockProduceKey.Build()); iter.Has(); iter.Next() {
+			o, key, err := iter.Get()
+			if err != nil {
+				return nil, err
+			}
+			keys, err := containerdb.SplitKeys(key)
+			if err != nil {
+				return nil, err
+			}
+			offset := intconv.BytesToInt64(keys[1])
+			bps[fmt.Sprint(offset)] = fmt.Sprintf("%+v", o.(*icobject.Object).Real())
+		}
+		jso["blockProduces"] = bps
+	}
+	return jso, nil
+}
+
+func dumpReward(es *iiss.ExtensionStateImpl, addr module.Address) (map[string]interface{}, error) {
+	jso := make(map[string]interface{})
+	if is, err := es.Reward.GetIScore(addr); err != nil {
+		return nil, err
+	} else if is != nil {
+		jso["iscore"] = is.Value()
+	}
+	if d, err := es.Reward.GetDelegating(addr); err != nil {
+		return nil, err
+	} else if d != nil {
+		jso["delegating"] = fmt.Sprintf("%+v", d)
+	}
+	if b, err := es.Reward.GetBonding(addr); err != nil {
+		return nil, err
+	} else if b != nil {
+		jso["bonding"] = fmt.Sprintf("%+v", b)
+	}
+	if v, err := es.Reward.GetVoted(addr); err != nil {
+		return nil, err
+	} else if v != nil {
+		jso["voted"] = fmt.Sprintf("%+v", v)
+	}
+	return jso, nil
+}
+
+// DumpIISS returns IISS states of the simulator. Accounts and rewards are
+// returned only for addrs, because they can't be listed. P-Reps and pending
+// entries of icstage are filtered by addrs if it's not empty.
+func (sim *simulatorImpl) DumpIISS(addrs []module.Address) (map[string]interface{}, error) {
+	es, cc := sim.getReadonlyExtensionStateAndCallContext()
+	sc := iiss.NewStateContext(cc, es)
+	f := newAddressFilter(addrs)
+
+	jso := make(map[string]interface{})
+	jso["blockHeight"] = sim.BlockHeight()
+	jso["revision"] = sim.Revision().Value()
+	if network, err := es.State.GetNetworkInfoInJSON(sc.RevisionValue()); err != nil {
+		return nil, err
+	} else {
+		jso["network"] = network
+	}
+	if term := es.State.GetTermSnapshot(); term != nil {
+		jso["term"] = term.ToJSON(sc, es.State)
+	}
+	if rf := es.State.GetRewardFundV2(); rf != nil {
+		jso["rewardFund"] = rf.ToJSON()
+	}
+
+	preps := make(map[string]interface{})
+	for _, prep := range es.State.GetPReps(false) {
+		if f.has(prep.Owner()) {
+			preps[prep.Owner().String()] = prep.ToJSON(sc)
+		}
+	}
+	jso["preps"] = preps
+
+	accounts := make(map[string]interface{})
+	for _, addr := range addrs {
+		account := make(map[string]interface{})
+		as := es.State.GetAccountSnapshot(addr)
+		if as == nil {
+			as = icstate.GetEmptyAccountSnapshot()
+		}
+		account["stake"] = as.GetStakeInJSON(cc.BlockHeight())
+		account["delegation"] = as.GetDelegationInJSON()
+		account["bond"] = as.GetBondInJSON()
+		account["unbonds"] = as.GetUnbondsInJSON()
+		reward, err := dumpReward(es, addr)
+		if err != nil {
+			return nil, err
+		}
+		account["reward"] = reward
+		accounts[addr.String()] = account
+	}
+	jso["accounts"] = accounts
+
+	essi := sim.wss.GetExtensionSnapshot().(*iiss.ExtensionSnapshotImpl)
+	stages := make(map[string]interface{})
+	for name, ss := range map[string]*icstage.Snapshot{
+		"front": essi.Front(),
+		"back1": essi.Back1(),
+		"back2": essi.Back2(),
+	} {
+		stage, err := dumpStage(ss, f)
+		if err != nil {
+			return nil, err
+		}
+		stages[name] = stage
+	}
+	jso["stages"] = stages
+	return jso, nil
+}
+
+// DiffIISS compares two values decoded from JSON (e.g. dumps of DumpIISS
+// for different heights), and returns changed values by their paths.
+// Maps are compared by keys, and other values are compared as a whole.
+func DiffIISS(from, to interface{}) map[string]interface{} {
+	diff := make(map[string]interface{})
+	diffValues(diff, "", from, to)
+	return diff
+}
+
+func diffValues(diff map[string]interface{}, path string, from, to interface{}) {
+	fm, ok1 := from.(map[string]interface{})
+	tm, ok2 := to.(map[string]interface{})
+	if ok1 && ok2 {
+		prefix := path
+		if len(prefix) > 0 {
+			prefix += "."
+		}
+		for k, fv := range fm {
+			diffValues(diff, prefix+k, fv, tm[k])
+		}
+		for k, tv := range tm {
+			if _, ok := fm[k]; !ok {
+				diffValues(diff, prefix+k, nil, tv)
+			}
+		}
+		return
+	}
+	if !reflect.DeepEqual(from, to) {
+		diff[path] = map[string]interface{}{
+			"from": from,
+			"to":   to,
+		}
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icsim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss/icstate"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+	"github.com/icon-project/goloop/module"
+)
+
+func dumpIISSForJSON(t *testing.T, sim Simulator, addrs []module.Address) map[string]interface{} {
+	dump, err := sim.DumpIISS(addrs)
+	assert.NoError(t, err)
+	tobj, err := common.EncodeAny(dump)
+	assert.NoError(t, err)
+	jso, err := common.DecodeAnyForJSON(tobj)
+	assert.NoError(t, err)
+	return jso.(map[string]interface{})
+}
+
+func TestSimulator_DumpIISS(t *testing.T) {
+	env, err := NewEnv(NewSimConfig(), icmodule.RevisionIISS4R1)
+	assert.NoError(t, err)
+	sim := env.Simulator()
+	user := env.users[0]
+	prep := env.preps[0]
+	assert.NoError(t, sim.GoToTermEnd(nil))
+
+	all := dumpIISSForJSON(t, sim, nil)
+	assert.Len(t, all["preps"], len(env.preps))
+	assert.Len(t, all["accounts"], 0)
+	assert.NotNil(t, all["term"])
+	assert.NotNil(t, all["rewardFund"])
+	stages := all["stages"].(map[string]interface{})
+	assert.Contains(t, stages, "front")
+	assert.Contains(t, stages["back1"], "blockProduces")
+
+	before := dumpIISSForJSON(t, sim, []module.Address{user})
+	assert.Len(t, before["preps"], 0)
+	assert.Contains(t, before["accounts"], user.String())
+
+	amount := icutils.ToLoop(3000)
+	receipts, err := sim.GoByTransaction(
+		nil,
+		sim.SetStake(user, amount),
+		sim.SetDelegation(user, icstate.Delegations{
+			icstate.NewDelegation(common.AddressToPtr(prep), amount),
+		}),
+	)
+	assert.NoError(t, err)
+	assert.True(t, CheckReceiptSuccess(receipts...))
+
+	after := dumpIISSForJSON(t, sim, []module.Address{user})
+	events := after["stages"].(map[string]interface{})["front"].(map[string]interface{})["events"]
+	assert.Len(t, events, 1)
+
+	diff := DiffIISS(before, after)
+	assert.Contains(t, diff, "blockHeight")
+	assert.Contains(t, diff, "accounts."+user.String()+".stake.stake")
+	assert.Contains(t, diff, "accounts."+user.String()+".delegation.delegations")
+	for path := range diff {
+		assert.NotContains(t, path, "preps.")
+	}
+	assert.Empty(t, DiffIISS(after, after))
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icsim
+
+import (
+	gblock "github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss"
+	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// NewSimulatorWithSnapshot returns a simulator starting from the world
+// snapshot made by the block at blockHeight. Revision and step price are
+// read from the snapshot, and changes are written to its database.
+func NewSimulatorWithSnapshot(
+	wss state.WorldSnapshot, blockHeight int64, config *SimConfig,
+) (Simulator, error) {
+	if wss.GetExtensionSnapshot() == nil {
+		return nil, errors.InvalidStateError.New("NoExtensionSnapshot")
+	}
+	as := state.NewReadOnlyWorldState(wss).GetAccountState(state.SystemID)
+	rev := int(scoredb.NewVarDB(as, state.VarRevision).Int64())
+	if rev < icmodule.RevisionIISS {
+		return nil, errors.InvalidStateError.Errorf("IISSNotEnabled(rev=%d)", rev)
+	}
+	stepPrice := scoredb.NewVarDB(as, state.VarStepPrice).BigInt()
+	if stepPrice == nil {
+		stepPrice = icmodule.BigIntZero
+	}
+	if config == nil {
+		config = NewSimConfig()
+	}
+	sim := &simulatorImpl{
+		logger:      log.GlobalLogger(),
+		blockHeight: blockHeight,
+		revision:    icmodule.ValueToRevision(rev),
+		stepPrice:   stepPrice,
+		config:      config,
+		wss:         wss,
+	}
+	sim.onFinalize(wss)
+	return sim, nil
+}
+
+// NewSimulatorFromChain forks the state of the chain stored in dbase after
+// executing the block at height. The database is never written, all the
+// changes made by the simulator are kept in memory.
+func NewSimulatorFromChain(dbase db.Database, height int64, config *SimConfig) (Simulator, error) {
+	last, err := gblock.GetLastHeight(dbase)
+	if err != nil {
+		return nil, err
+	}
+	// result of the block is stored in the next block
+	if height < 0 || height >= last {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidHeight(height=%d,last=%d)", height, last)
+	}
+	result, err := gblock.GetBlockResultByHeight(dbase, nil, height+1)
+	if err != nil {
+		return nil, err
+	}
+	vl, err := gblock.GetNextValidatorsByHeight(dbase, nil, height)
+	if err != nil {
+		return nil, err
+	}
+	ed, err := service.ExtensionDataFromResult(result)
+	if err != nil {
+		return nil, err
+	}
+	if len(ed) == 0 {
+		return nil, errors.InvalidStateError.Errorf("NoExtensionData(height=%d)", height)
+	}
+
+	ldb := db.NewLayerDB(dbase)
+	wss, err := service.NewWorldSnapshot(ldb, nil, result, vl)
+	if err != nil {
+		return nil, err
+	}
+	ess := iiss.NewExtensionSnapshot(ldb, ed)
+	if ess == nil {
+		return nil, errors.InvalidStateError.Errorf("InvalidExtensionData(height=%d)", height)
+	}
+	wss = state.NewWorldSnapshot(ldb, wss.StateHash(), wss.GetValidatorSnapshot(), ess, nil)
+	return NewSimulatorWithSnapshot(wss, height, config)
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icsim
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss"
+	"github.com/icon-project/goloop/icon/iiss/icstate"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestSimulator_PRepHistory(t *testing.T) {
+	const (
+		termPeriod                 = int64(100)
+		validationPenaltyCondition = int64(5)
+	)
+
+	cfg := NewSimConfigWithParams(map[SimConfigOption]interface{}{
+		SCOTermPeriod:                        termPeriod,
+		SCOValidationFailurePenaltyCondition: validationPenaltyCondition,
+	})
+	env, err := NewEnv(cfg, icmodule.RevisionIISS4R1)
+	assert.NoError(t, err)
+	sim := env.Simulator()
+
+	rec := new(iiss.PRepHistoryRecorder)
+	rec.SetEnabled(true)
+	goAndRecord := func(csi module.ConsensusInfo, blocks int64) {
+		for i := int64(0); i < blocks; i++ {
+			assert.NoError(t, sim.Go(csi, 1))
+			rec.Record(sim.(*simulatorImpl).wss.GetExtensionSnapshot(), sim.(*simulatorImpl).logger)
+		}
+	}
+	toTermEnd := func(csi module.ConsensusInfo) {
+		goAndRecord(csi, sim.TermSnapshot().GetEndHeight()-sim.BlockHeight())
+	}
+
+	csi := NewConsensusInfoBySim(sim)
+	toTermEnd(csi)
+	goAndRecord(csi, 2)
+
+	idx := 3
+	vl := sim.ValidatorList()
+	owner := vl[idx].Address()
+	goAndRecord(NewConsensusInfoBySim(sim, idx), validationPenaltyCondition)
+	penalized := sim.BlockHeight()
+	assert.True(t, CheckPenalizedPRep(sim.GetPRepByOwner(owner)))
+
+	csi = NewConsensusInfoBySim(sim)
+	toTermEnd(csi)
+	termEnd := sim.BlockHeight()
+	rec.Wait()
+
+	h, err := iiss.NewPRepHistory(sim.Database())
+	assert.NoError(t, err)
+	records, err := h.Query(owner, 0, math.MaxInt64, icmodule.MaxPRepHistory)
+	assert.NoError(t, err)
+	assert.Len(t, records, 4)
+
+	term := records[0]
+	assert.Equal(t, iiss.PHTerm, term.Type)
+	assert.Equal(t, termEnd, term.Height)
+	assert.Equal(t, termEnd-termPeriod+1, term.StartHeight)
+	assert.True(t, term.Total > term.Fail)
+	assert.True(t, term.Fail >= validationPenaltyCondition)
+
+	types := make(map[iiss.PRepHistoryType]*iiss.PRepHistoryRecord)
+	for _, r := range records[1:] {
+		assert.Equal(t, penalized, r.Height)
+		types[r.Type] = r
+	}
+	assert.Equal(t, icmodule.PenaltyValidationFailure, types[iiss.PHPenalty].Penalty)
+	assert.NotNil(t, types[iiss.PHPenalty].Slashed)
+	assert.Equal(t, int(icstate.GradeMain), types[iiss.PHGrade].From)
+	assert.Equal(t, int(icstate.GradeCandidate), types[iiss.PHGrade].To)
+	assert.Zero(t, types[iiss.PHJail].From)
+	assert.NotZero(t, types[iiss.PHJail].To)
+
+	// other validators have only stats of the term
+	records, err = h.Query(vl[0].Address(), 0, math.MaxInt64, icmodule.MaxPRepHistory)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, iiss.PHTerm, records[0].Type)
+	assert.Zero(t, records[0].Fail)
+
+	records, err = h.Query(owner, 0, penalized-1, icmodule.MaxPRepHistory)
+	assert.NoError(t, err)
+	assert.Len(t, records, 0)
+}
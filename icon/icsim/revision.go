@@ -57,6 +57,11 @@ func (sim *simulatorImpl) handleRevisionChange(wc WorldContext, oldRev, newRev i
 			}
 		}
 	}
+	// keep the revision in the state as the chain SCORE does
+	as := wc.GetAccountState(state.SystemID)
+	if err := scoredb.NewVarDB(as, state.VarRevision).Set(newRev); err != nil {
+		return err
+	}
 	sim.revision = icmodule.ValueToRevision(newRev)
 	return nil
 }
@@ -188,4 +188,10 @@ type Simulator interface {
 	// After RevisionSetBondRequirementRate
 	SetBondRequirementRate(from module.Address, rate icmodule.Rate) Transaction
 	GoBySetBondRequirementRate(csi module.ConsensusInfo, from module.Address, rate icmodule.Rate) ([]Receipt, error)
+
+	// Dry-run
+	WhatIf(w *WhatIf) (map[string]interface{}, error)
+
+	// Debug
+	DumpIISS(addrs []module.Address) (map[string]interface{}, error)
 }
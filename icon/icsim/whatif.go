@@ -0,0 +1,354 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icsim
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss"
+	"github.com/icon-project/goloop/icon/iiss/icstate"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// WhatIfTransaction is a hypothetical transaction. Method and Params follow
+// the API of the chain SCORE (e.g. setStake with {"value":"0x1"}), and the
+// transaction isn't signed, so it can be sent from any address.
+type WhatIfTransaction struct {
+	From   *common.Address `json:"from"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// WhatIf is a scenario for the dry-run. Balances are set at first, then
+// Transactions are executed in a block. After that, it goes to the end of
+// the term Terms times. Validators in Offline don't vote for the blocks.
+// I-Scores of Accounts are reported for each term. Note that the reward for
+// a term is calculated during the next term, so it's shown in I-Score from
+// the term after the next.
+type WhatIf struct {
+	Balances     map[string]*common.HexInt `json:"balances,omitempty"`
+	Transactions []*WhatIfTransaction      `json:"transactions,omitempty"`
+	Terms        int                       `json:"terms"`
+	Offline      []*common.Address         `json:"offline,omitempty"`
+	Accounts     []*common.Address         `json:"accounts,omitempty"`
+}
+
+type whatIfNamedValue struct {
+	Name  string        `json:"name"`
+	Value common.HexInt `json:"value"`
+}
+
+type whatIfParams struct {
+	To          *common.Address    `json:"to"`
+	Value       *common.HexInt     `json:"value"`
+	Address     *common.Address    `json:"address"`
+	Rate        *common.HexInt     `json:"rate"`
+	Code        *common.HexInt     `json:"code"`
+	Bond        *common.HexInt     `json:"bond"`
+	Delegations []interface{}      `json:"delegations"`
+	Bonds       []interface{}      `json:"bonds"`
+	BonderList  []interface{}      `json:"bonderList"`
+	Values      []interface{}      `json:"values"`
+	Counts      []whatIfNamedValue `json:"counts"`
+	Rates       []whatIfNamedValue `json:"rates"`
+}
+
+func missingParam(method, name string) error {
+	return errors.IllegalArgumentError.Errorf("MissingParam(method=%s,param=%s)", method, name)
+}
+
+func namedValuesToMap(values []whatIfNamedValue) (map[string]int64, error) {
+	m := make(map[string]int64, len(values))
+	for _, v := range values {
+		if !v.Value.IsInt64() {
+			return nil, errors.IllegalArgumentError.Errorf("Int64Overflow(name=%s)", v.Name)
+		}
+		m[v.Name] = v.Value.Int64()
+	}
+	return m, nil
+}
+
+func (sim *simulatorImpl) newWhatIfTransaction(t *WhatIfTransaction) (Transaction, error) {
+	if t.From == nil {
+		return nil, errors.IllegalArgumentError.Errorf("NoFrom(method=%s)", t.Method)
+	}
+	p := new(whatIfParams)
+	if len(t.Params) > 0 {
+		if err := json.Unmarshal(t.Params, p); err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidParams(method=%s)", t.Method)
+		}
+	}
+	es := sim.getReadonlyExtensionState()
+	from := t.From
+	switch t.Method {
+	case "transfer":
+		if p.To == nil || p.Value == nil {
+			return nil, missingParam(t.Method, "to,value")
+		}
+		return sim.Transfer(from, p.To, p.Value.Value()), nil
+	case "setStake":
+		if p.Value == nil {
+			return nil, missingParam(t.Method, "value")
+		}
+		return sim.SetStake(from, p.Value.Value()), nil
+	case "setDelegation":
+		ds, err := icstate.NewDelegations(p.Delegations, es.State.GetDelegationSlotMax())
+		if err != nil {
+			return nil, err
+		}
+		return sim.SetDelegation(from, ds), nil
+	case "setBond":
+		bonds, err := icstate.NewBonds(p.Bonds, sim.Revision().Value())
+		if err != nil {
+			return nil, err
+		}
+		return sim.SetBond(from, bonds), nil
+	case "setBonderList":
+		bl, err := icstate.NewBonderList(p.BonderList)
+		if err != nil {
+			return nil, err
+		}
+		return sim.SetBonderList(from, bl), nil
+	case "unregisterPRep":
+		return sim.UnregisterPRep(from), nil
+	case "disqualifyPRep":
+		if p.Address == nil {
+			return nil, missingParam(t.Method, "address")
+		}
+		return sim.DisqualifyPRep(from, p.Address), nil
+	case "claimIScore":
+		return sim.ClaimIScore(from), nil
+	case "setRevision":
+		if p.Code == nil {
+			return nil, missingParam(t.Method, "code")
+		}
+		return sim.SetRevision(from, icmodule.ValueToRevision(int(p.Code.Int64()))), nil
+	case "setCommissionRate":
+		if p.Rate == nil {
+			return nil, missingParam(t.Method, "rate")
+		}
+		return sim.SetCommissionRate(from, icmodule.Rate(p.Rate.Int64())), nil
+	case "requestUnjail":
+		return NewTransaction(TypeRequestUnjail, from), nil
+	case "setMinimumBond":
+		if p.Bond == nil {
+			return nil, missingParam(t.Method, "bond")
+		}
+		return sim.SetMinimumBond(from, p.Bond.Value()), nil
+	case "setBondRequirementRate":
+		if p.Rate == nil {
+			return nil, missingParam(t.Method, "rate")
+		}
+		return sim.SetBondRequirementRate(from, icmodule.Rate(p.Rate.Int64())), nil
+	case "setRewardFundAllocation2":
+		values, err := icstate.NewRewardFund2Allocation(p.Values)
+		if err != nil {
+			return nil, err
+		}
+		return sim.SetRewardFundAllocation2(from, values), nil
+	case "setPRepCountConfig":
+		counts, err := namedValuesToMap(p.Counts)
+		if err != nil {
+			return nil, err
+		}
+		return sim.SetPRepCountConfig(from, counts), nil
+	case "setSlashingRates":
+		values, err := namedValuesToMap(p.Rates)
+		if err != nil {
+			return nil, err
+		}
+		rates := make(map[string]icmodule.Rate, len(values))
+		for k, v := range values {
+			rates[k] = icmodule.Rate(v)
+		}
+		return sim.SetSlashingRates(from, rates), nil
+	default:
+		return nil, errors.IllegalArgumentError.Errorf("UnsupportedMethod(method=%s)", t.Method)
+	}
+}
+
+// setBalances sets balances of the accounts directly, and total supply is
+// adjusted by the differences.
+func (sim *simulatorImpl) setBalances(balances map[string]*common.HexInt) error {
+	if len(balances) == 0 {
+		return nil
+	}
+	ws := newWorldState(sim.wss, false)
+	tsVarDB := scoredb.NewVarDB(ws.GetAccountState(state.SystemID), state.VarTotalSupply)
+	totalSupply := tsVarDB.BigInt()
+	if totalSupply == nil {
+		totalSupply = new(big.Int)
+	}
+	for k, v := range balances {
+		addr, err := common.NewAddressFromString(k)
+		if err != nil {
+			return errors.IllegalArgumentError.Wrapf(err, "InvalidAddress(addr=%s)", k)
+		}
+		as := ws.GetAccountState(addr.ID())
+		totalSupply.Sub(totalSupply, as.GetBalance())
+		if err = setBalance(addr, as, v.Value()); err != nil {
+			return err
+		}
+		totalSupply.Add(totalSupply, v.Value())
+	}
+	if err := tsVarDB.Set(totalSupply); err != nil {
+		return err
+	}
+	wss := ws.GetSnapshot()
+	if err := wss.Flush(); err != nil {
+		return err
+	}
+	sim.wss = wss
+	return nil
+}
+
+func (sim *simulatorImpl) newWhatIfConsensusInfo(offline []*common.Address) module.ConsensusInfo {
+	vl := sim.ValidatorList()
+	voted := make([]bool, len(vl))
+	for i, v := range vl {
+		voted[i] = true
+		for _, addr := range offline {
+			if v.Address().Equal(addr) {
+				voted[i] = false
+				break
+			}
+		}
+	}
+	return NewConsensusInfo(sim.Database(), vl, voted)
+}
+
+func penaltyEventToJSON(blockHeight int64, signature string, indexed, data []any) map[string]interface{} {
+	jso := map[string]interface{}{
+		"blockHeight": blockHeight,
+		"owner":       indexed[0],
+	}
+	switch signature {
+	case iiss.EventPenaltyImposed:
+		jso["status"] = data[0]
+		jso["type"] = icmodule.PenaltyType(data[1].(*big.Int).Int64()).String()
+	case iiss.EventSlashed:
+		jso["bonder"] = data[0]
+		jso["amount"] = data[1]
+	}
+	return jso
+}
+
+func (sim *simulatorImpl) whatIfTerm(w *WhatIf) (map[string]interface{}, error) {
+	tss := sim.TermSnapshot()
+	penalties := make([]interface{}, 0)
+	slashed := make([]interface{}, 0)
+	for sim.BlockHeight() < tss.GetEndHeight() {
+		receipts, err := sim.GoByBlock(sim.newWhatIfConsensusInfo(w.Offline), nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range receipts[0].Events() {
+			signature, indexed, data, err := e.DecodeParams()
+			if err != nil || len(indexed) != 1 || len(data) != 2 {
+				continue
+			}
+			switch signature {
+			case iiss.EventPenaltyImposed:
+				penalties = append(penalties, penaltyEventToJSON(sim.BlockHeight(), signature, indexed, data))
+			case iiss.EventSlashed:
+				slashed = append(slashed, penaltyEventToJSON(sim.BlockHeight(), signature, indexed, data))
+			}
+		}
+	}
+	iscores := make(map[string]interface{}, len(w.Accounts))
+	for _, addr := range w.Accounts {
+		iscores[addr.String()] = sim.QueryIScore(addr)
+	}
+	return map[string]interface{}{
+		"sequence":    tss.Sequence(),
+		"startHeight": tss.StartHeight(),
+		"endHeight":   tss.GetEndHeight(),
+		"iscore":      iscores,
+		"penalties":   penalties,
+		"slashed":     slashed,
+	}, nil
+}
+
+// WhatIf runs the scenario and returns the results of the transactions,
+// I-Scores of the accounts and penalties for each term, and status of
+// P-Reps and the network at the end.
+func (sim *simulatorImpl) WhatIf(w *WhatIf) (map[string]interface{}, error) {
+	if w.Terms < 0 {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidTerms(terms=%d)", w.Terms)
+	}
+	startHeight := sim.BlockHeight()
+	iscores := make(map[string]interface{}, len(w.Accounts))
+	for _, addr := range w.Accounts {
+		iscores[addr.String()] = sim.QueryIScore(addr)
+	}
+	if err := sim.setBalances(w.Balances); err != nil {
+		return nil, err
+	}
+
+	txs := make([]interface{}, 0, len(w.Transactions))
+	if len(w.Transactions) > 0 {
+		blk := NewBlock()
+		for _, t := range w.Transactions {
+			tx, err := sim.newWhatIfTransaction(t)
+			if err != nil {
+				return nil, err
+			}
+			blk.AddTransaction(tx)
+		}
+		receipts, err := sim.GoByBlock(sim.newWhatIfConsensusInfo(w.Offline), blk)
+		if err != nil {
+			return nil, err
+		}
+		for i, t := range w.Transactions {
+			rct := receipts[i+1]
+			jso := map[string]interface{}{
+				"from":   t.From,
+				"method": t.Method,
+				"status": rct.Status(),
+			}
+			if rct.Error() != nil {
+				jso["failure"] = rct.Error().Error()
+			}
+			txs = append(txs, jso)
+		}
+	}
+
+	terms := make([]interface{}, 0, w.Terms)
+	for i := 0; i < w.Terms; i++ {
+		term, err := sim.whatIfTerm(w)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+
+	return map[string]interface{}{
+		"startHeight":  startHeight,
+		"blockHeight":  sim.BlockHeight(),
+		"iscore":       iscores,
+		"transactions": txs,
+		"terms":        terms,
+		"preps":        sim.GetPRepsInJSON(),
+		"network":      sim.GetNetworkInfoInJSON(),
+	}, nil
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icsim
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func forkSimulator(t *testing.T, sim Simulator) Simulator {
+	wss := sim.(*simulatorImpl).wss
+	ldb := db.NewLayerDB(wss.Database())
+	ess := iiss.NewExtensionSnapshot(ldb, wss.GetExtensionSnapshot().Bytes())
+	fwss := state.NewWorldSnapshot(ldb, wss.StateHash(), wss.GetValidatorSnapshot(), ess, nil)
+	fork, err := NewSimulatorWithSnapshot(fwss, sim.BlockHeight(), nil)
+	assert.NoError(t, err)
+	return fork
+}
+
+func TestSimulator_WhatIf(t *testing.T) {
+	const termPeriod = 100
+
+	c := NewSimConfigWithParams(map[SimConfigOption]interface{}{
+		SCOTermPeriod: int64(termPeriod),
+	})
+	env, err := NewEnv(c, icmodule.RevisionIISS4R1)
+	assert.NoError(t, err)
+	sim := env.Simulator()
+	assert.NoError(t, sim.GoToTermEnd(nil))
+
+	fork := forkSimulator(t, sim)
+	assert.Equal(t, sim.BlockHeight(), fork.BlockHeight())
+	assert.Equal(t, sim.Revision(), fork.Revision())
+
+	user := newDummyAddress(5000)
+	scenario := fmt.Sprintf(`{
+		"balances": {"%[1]s": "%#[2]x"},
+		"transactions": [
+			{"from": "%[1]s", "method": "setStake", "params": {"value": "%#[3]x"}},
+			{"from": "%[1]s", "method": "setDelegation", "params": {"delegations": [
+				{"address": "%[4]s", "value": "%#[3]x"}
+			]}},
+			{"from": "%[1]s", "method": "setStake", "params": {"value": "%#[5]x"}}
+		],
+		"terms": 3,
+		"accounts": ["%[1]s"]
+	}`, user, icutils.ToLoop(10000), icutils.ToLoop(5000), env.preps[0], icutils.ToLoop(1000))
+	w := new(WhatIf)
+	assert.NoError(t, json.Unmarshal([]byte(scenario), w))
+
+	res, err := fork.WhatIf(w)
+	assert.NoError(t, err)
+	txs := res["transactions"].([]interface{})
+	assert.Len(t, txs, 3)
+	assert.Equal(t, Success, txs[0].(map[string]interface{})["status"])
+	assert.Equal(t, Success, txs[1].(map[string]interface{})["status"])
+	// stake can't be less than voting amount
+	assert.Equal(t, Failure, txs[2].(map[string]interface{})["status"])
+	assert.NotEmpty(t, txs[2].(map[string]interface{})["failure"])
+
+	// reward for the first term is calculated during the second term,
+	// then it's applied in the third term.
+	terms := res["terms"].([]interface{})
+	assert.Len(t, terms, 3)
+	iscore := terms[1].(map[string]interface{})["iscore"].(map[string]interface{})[user.String()].(*big.Int)
+	assert.Zero(t, iscore.Sign())
+	term := terms[2].(map[string]interface{})
+	assert.Equal(t, fork.BlockHeight(), term["endHeight"])
+	iscore = term["iscore"].(map[string]interface{})[user.String()].(*big.Int)
+	assert.True(t, iscore.Sign() > 0)
+	assert.Equal(t, sim.BlockHeight()+3*termPeriod, fork.BlockHeight())
+	assert.NotNil(t, res["preps"])
+	_, err = common.EncodeAny(res)
+	assert.NoError(t, err)
+
+	// the original simulator is not affected
+	assert.Zero(t, sim.GetBalance(user).Sign())
+	assert.Zero(t, sim.QueryIScore(user).Sign())
+
+	_, err = fork.WhatIf(&WhatIf{
+		Transactions: []*WhatIfTransaction{{From: user.(*common.Address), Method: "unknown"}},
+	})
+	assert.Error(t, err)
+}
@@ -44,6 +44,7 @@ type calculator struct {
 	global      icstage.Global
 	temp        *icreward.State
 	stats       *Stats
+	records     *rewardRecords
 
 	lock    sync.Mutex
 	waiters []*sync.Cond
@@ -156,6 +157,9 @@ func (c *calculator) UpdateIScore(addr module.Address, reward *big.Int, t Reward
 	}
 	c.log.Tracef("Update IScore %s by %d: %+v + %s = %+v", addr, t, iScore, reward, nIScore)
 	c.stats.IncreaseReward(t, reward)
+	if c.records != nil {
+		c.records.add(addr, reward, t)
+	}
 	return nil
 }
 
@@ -186,10 +190,23 @@ func (c *calculator) run() (ret error) {
 	}
 
 	c.log.Infof("Calculation statistics: %s", c.stats)
+	if c.records != nil {
+		if err = c.storeHistory(); err != nil {
+			c.log.Warnf("Failed to store reward history. %+v", err)
+		}
+	}
 	c.setResult(c.temp.GetSnapshot(), nil)
 	return nil
 }
 
+func (c *calculator) storeHistory() error {
+	h, err := NewRewardHistory(c.database)
+	if err != nil {
+		return err
+	}
+	return h.store(c.records)
+}
+
 func processClaim(ctx Context) error {
 	back := ctx.Back()
 	temp := ctx.Temp()
@@ -302,7 +319,9 @@ func processCommissionRate(ctx Context) error {
 
 const InitBlockHeight = -1
 
-func New(database db.Database, back *icstage.Snapshot, reward *icreward.Snapshot, logger log.Logger) *calculator {
+// New returns a new calculator and starts calculation. If history is true,
+// it stores rewards of accounts to RewardHistory.
+func New(database db.Database, back *icstage.Snapshot, reward *icreward.Snapshot, logger log.Logger, history bool) *calculator {
 	var err error
 	var global icstage.Global
 	var startHeight int64
@@ -328,6 +347,12 @@ func New(database db.Database, back *icstage.Snapshot, reward *icreward.Snapshot
 		startHeight: startHeight,
 		stats:       NewStats(),
 	}
+	if history && global != nil {
+		c.records = newRewardRecords(RewardTerm{
+			StartHeight: startHeight,
+			EndHeight:   startHeight + int64(global.GetOffsetLimit()),
+		})
+	}
 	if startHeight != InitBlockHeight {
 		go c.run()
 	}
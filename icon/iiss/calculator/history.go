@@ -0,0 +1,222 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package calculator
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/icon/icdb"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+	"github.com/icon-project/goloop/module"
+)
+
+var keyRewardTerms = []byte("terms")
+
+// RewardTerm is the term of the reward calculation.
+type RewardTerm struct {
+	StartHeight int64
+	EndHeight   int64
+}
+
+// RewardRecord is the reward of an account for a term, and it's grouped
+// by the type of the reward.
+type RewardRecord struct {
+	RewardTerm
+	BlockProduce *big.Int
+	PRep         *big.Int
+	Voter        *big.Int
+	Bonder       *big.Int
+}
+
+func newRewardRecord(term RewardTerm) *RewardRecord {
+	return &RewardRecord{
+		RewardTerm:   term,
+		BlockProduce: new(big.Int),
+		PRep:         new(big.Int),
+		Voter:        new(big.Int),
+		Bonder:       new(big.Int),
+	}
+}
+
+func (r *RewardRecord) add(t RewardType, reward *big.Int) {
+	switch t {
+	case RTBlockProduce:
+		r.BlockProduce.Add(r.BlockProduce, reward)
+	case RTPRep:
+		r.PRep.Add(r.PRep, reward)
+	case RTVoter:
+		r.Voter.Add(r.Voter, reward)
+	case RTBonder:
+		r.Bonder.Add(r.Bonder, reward)
+	}
+}
+
+func (r *RewardRecord) Total() *big.Int {
+	total := new(big.Int).Add(r.BlockProduce, r.PRep)
+	total.Add(total, r.Voter)
+	return total.Add(total, r.Bonder)
+}
+
+func (r *RewardRecord) ToJSON() map[string]interface{} {
+	jso := make(map[string]interface{})
+	jso["startHeight"] = r.StartHeight
+	jso["endHeight"] = r.EndHeight
+	jso["iscore"] = r.Total()
+	jso["blockProduce"] = r.BlockProduce
+	jso["prep"] = r.PRep
+	jso["voter"] = r.Voter
+	jso["bonder"] = r.Bonder
+	return jso
+}
+
+// rewardRecords collects rewards of accounts during the calculation.
+type rewardRecords struct {
+	term    RewardTerm
+	records map[string]*RewardRecord
+}
+
+func (rs *rewardRecords) add(addr module.Address, reward *big.Int, t RewardType) {
+	if reward.Sign() == 0 {
+		return
+	}
+	key := icutils.ToKey(addr)
+	r, ok := rs.records[key]
+	if !ok {
+		r = newRewardRecord(rs.term)
+		rs.records[key] = r
+	}
+	r.add(t, reward)
+}
+
+func newRewardRecords(term RewardTerm) *rewardRecords {
+	return &rewardRecords{
+		term:    term,
+		records: make(map[string]*RewardRecord),
+	}
+}
+
+// RewardHistory keeps rewards of accounts for each term in the database.
+// It's written by the calculator if it's enabled.
+type RewardHistory struct {
+	bk db.Bucket
+}
+
+var historyLock sync.Mutex
+
+func keyOfRecord(key string, startHeight int64) []byte {
+	return append([]byte(key), intconv.Int64ToBytes(startHeight)...)
+}
+
+// Terms returns terms having records in ascending order.
+func (h *RewardHistory) Terms() ([]RewardTerm, error) {
+	bs, err := h.bk.Get(keyRewardTerms)
+	if err != nil {
+		return nil, err
+	}
+	var terms []RewardTerm
+	if len(bs) > 0 {
+		if _, err = codec.BC.UnmarshalFromBytes(bs, &terms); err != nil {
+			return nil, err
+		}
+	}
+	return terms, nil
+}
+
+func (h *RewardHistory) Get(addr module.Address, startHeight int64) (*RewardRecord, error) {
+	bs, err := h.bk.Get(keyOfRecord(icutils.ToKey(addr), startHeight))
+	if err != nil || len(bs) == 0 {
+		return nil, err
+	}
+	r := new(RewardRecord)
+	if _, err = codec.BC.UnmarshalFromBytes(bs, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Query returns records of the account for terms overlapping with the
+// range of heights. It returns at most limit records from the latest one.
+func (h *RewardHistory) Query(addr module.Address, from, to int64, limit int) ([]*RewardRecord, error) {
+	terms, err := h.Terms()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*RewardRecord, 0)
+	for i := len(terms) - 1; i >= 0 && len(records) < limit; i-- {
+		term := terms[i]
+		if term.EndHeight < from || term.StartHeight > to {
+			continue
+		}
+		r, err := h.Get(addr, term.StartHeight)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+func (h *RewardHistory) store(rs *rewardRecords) error {
+	historyLock.Lock()
+	defer historyLock.Unlock()
+
+	for key, r := range rs.records {
+		bs, err := codec.BC.MarshalToBytes(r)
+		if err != nil {
+			return err
+		}
+		if err = h.bk.Set(keyOfRecord(key, rs.term.StartHeight), bs); err != nil {
+			return err
+		}
+	}
+
+	// calculation may be done again for the same term after restart.
+	terms, err := h.Terms()
+	if err != nil {
+		return err
+	}
+	idx := sort.Search(len(terms), func(i int) bool {
+		return terms[i].StartHeight >= rs.term.StartHeight
+	})
+	if idx < len(terms) && terms[idx].StartHeight == rs.term.StartHeight {
+		terms[idx] = rs.term
+	} else {
+		terms = append(terms, RewardTerm{})
+		copy(terms[idx+1:], terms[idx:])
+		terms[idx] = rs.term
+	}
+	bs, err := codec.BC.MarshalToBytes(terms)
+	if err != nil {
+		return err
+	}
+	return h.bk.Set(keyRewardTerms, bs)
+}
+
+func NewRewardHistory(dbase db.Database) (*RewardHistory, error) {
+	bk, err := dbase.GetBucket(icdb.RewardHistory)
+	if err != nil {
+		return nil, err
+	}
+	return &RewardHistory{bk: bk}, nil
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package calculator
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+)
+
+func TestRewardHistory(t *testing.T) {
+	dbase := db.NewMapDB()
+	h, err := NewRewardHistory(dbase)
+	assert.NoError(t, err)
+
+	a1 := common.MustNewAddressFromString("hx1")
+	a2 := common.MustNewAddressFromString("hx2")
+
+	terms := []RewardTerm{{100, 199}, {200, 299}, {300, 399}}
+	for i, term := range terms {
+		rs := newRewardRecords(term)
+		rs.add(a1, big.NewInt(int64(10*(i+1))), RTPRep)
+		rs.add(a1, big.NewInt(int64(i+1)), RTVoter)
+		rs.add(a1, big.NewInt(2), RTBonder)
+		rs.add(a2, new(big.Int), RTVoter)
+		assert.NoError(t, h.store(rs))
+	}
+	// store the same term again
+	rs := newRewardRecords(terms[1])
+	rs.add(a1, big.NewInt(20), RTPRep)
+	rs.add(a1, big.NewInt(2), RTVoter)
+	rs.add(a1, big.NewInt(2), RTBonder)
+	assert.NoError(t, h.store(rs))
+
+	ts, err := h.Terms()
+	assert.NoError(t, err)
+	assert.Equal(t, terms, ts)
+
+	records, err := h.Query(a1, 0, 1000, 10)
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+	assert.Equal(t, int64(300), records[0].StartHeight)
+	assert.Equal(t, int64(30), records[0].PRep.Int64())
+	assert.Equal(t, int64(3), records[0].Voter.Int64())
+	assert.Equal(t, int64(2), records[0].Bonder.Int64())
+	assert.Equal(t, int64(35), records[0].Total().Int64())
+
+	records, err = h.Query(a1, 250, 300, 10)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, int64(300), records[0].StartHeight)
+	assert.Equal(t, int64(200), records[1].StartHeight)
+
+	records, err = h.Query(a1, 0, 1000, 1)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	// zero rewards are not recorded
+	records, err = h.Query(a2, 0, 1000, 10)
+	assert.NoError(t, err)
+	assert.Len(t, records, 0)
+}
@@ -17,6 +17,8 @@
 package calculator
 
 import (
+	"math/big"
+
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/containerdb"
 	"github.com/icon-project/goloop/common/intconv"
@@ -227,8 +229,7 @@ func (r *iiss4Reward) processVoterReward() error {
 			r.ve.SetCalculated(addr)
 		}
 
-		iscore := voter.CalculateReward(r.pi)
-		if err = r.UpdateIScore(voter.Owner(), iscore, RTVoter); err != nil {
+		if err = r.updateVoterReward(voter); err != nil {
 			return err
 		}
 	}
@@ -273,8 +274,7 @@ func (r *iiss4Reward) processVoterReward() error {
 			r.ve.SetCalculated(addr)
 		}
 
-		iscore := voter.CalculateReward(r.pi)
-		if err = r.UpdateIScore(voter.Owner(), iscore, RTVoter); err != nil {
+		if err = r.updateVoterReward(voter); err != nil {
 			return err
 		}
 	}
@@ -293,8 +293,7 @@ func (r *iiss4Reward) processVoterReward() error {
 		}
 		r.ve.SetCalculated(addr)
 
-		iscore := voter.CalculateReward(r.pi)
-		if err = r.UpdateIScore(voter.Owner(), iscore, RTVoter); err != nil {
+		if err = r.updateVoterReward(voter); err != nil {
 			return err
 		}
 	}
@@ -302,6 +301,19 @@ func (r *iiss4Reward) processVoterReward() error {
 	return nil
 }
 
+// updateVoterReward writes voter reward to icreward.IScore. The part of the
+// reward by bonds is accounted as RTBonder.
+func (r *iiss4Reward) updateVoterReward(voter *Voter) error {
+	iscore := voter.CalculateReward(r.pi)
+	if bond := voter.BondReward(); bond.Sign() > 0 {
+		if err := r.UpdateIScore(voter.Owner(), bond, RTBonder); err != nil {
+			return err
+		}
+		iscore = new(big.Int).Sub(iscore, bond)
+	}
+	return r.UpdateIScore(voter.Owner(), iscore, RTVoter)
+}
+
 func NewIISS4Reward(c Context) (*iiss4Reward, error) {
 	global, err := c.Back().GetGlobal()
 	if err != nil {
@@ -22,6 +22,7 @@ const (
 	RTBlockProduce RewardType = iota
 	RTPRep
 	RTVoter
+	RTBonder
 )
 
 func (r RewardType) String() string {
@@ -32,6 +33,8 @@ func (r RewardType) String() string {
 		return "PRep"
 	case RTVoter:
 		return "Voter"
+	case RTBonder:
+		return "Bonder"
 	default:
 		return "Unknown"
 	}
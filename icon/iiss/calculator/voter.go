@@ -157,6 +157,8 @@ func NewVoteEvents() *VoteEvents {
 type Voter struct {
 	owner            module.Address
 	accumulatedVotes map[string]*big.Int
+	accumulatedBonds map[string]*big.Int
+	bondReward       *big.Int
 
 	log log.Logger
 }
@@ -165,25 +167,33 @@ func (v *Voter) Owner() module.Address {
 	return v.owner
 }
 
-func (v *Voter) applyVoting(voting icstate.Voting, period *big.Int) {
+func accumulate(m map[string]*big.Int, key string, amount *big.Int) {
+	if value, ok := m[key]; ok {
+		m[key] = new(big.Int).Add(value, amount)
+	} else {
+		m[key] = amount
+	}
+}
+
+func (v *Voter) applyVoting(voting icstate.Voting, period *big.Int, bond bool) {
 	key := icutils.ToKey(voting.To())
 	amount := new(big.Int).Mul(voting.Amount(), period)
-	if value, ok := v.accumulatedVotes[key]; ok {
-		v.accumulatedVotes[key] = new(big.Int).Add(value, amount)
-	} else {
-		v.accumulatedVotes[key] = amount
+	accumulate(v.accumulatedVotes, key, amount)
+	if bond {
+		accumulate(v.accumulatedBonds, key, amount)
 	}
 }
 
 func (v *Voter) ApplyVoting(voting icreward.Voting, period int64) {
 	v.log.Tracef("Add voting to %s: %+v, %d", v.owner, voting, period)
 	pr := big.NewInt(period)
+	_, bond := voting.(*icreward.Bonding)
 	iter := voting.Iterator()
 	for ; iter.Has(); iter.Next() {
 		if vote, err := iter.Get(); err != nil {
 			continue
 		} else {
-			v.applyVoting(vote, pr)
+			v.applyVoting(vote, pr, bond)
 		}
 	}
 }
@@ -191,8 +201,9 @@ func (v *Voter) ApplyVoting(voting icreward.Voting, period int64) {
 func (v *Voter) ApplyEvent(event *VoteEvent, period int) {
 	v.log.Tracef("Add event to %s: %+v, %d", v.owner, event, period)
 	pr := big.NewInt(int64(period))
+	bond := event.Type() == vtBond
 	for _, vote := range event.Votes() {
-		v.applyVoting(vote, pr)
+		v.applyVoting(vote, pr, bond)
 	}
 }
 
@@ -208,17 +219,31 @@ func (v *Voter) CalculateReward(pInfo *PRepInfo) *big.Int {
 			v.log.Tracef("vote reward for %s: %d = %d * %d / %d",
 				prep.Owner(), r, prep.VoterReward(), av, prep.AccumulatedVoted())
 			iScore.Add(iScore, r)
+
+			if ab, ok := v.accumulatedBonds[k]; ok {
+				br := new(big.Int).Mul(ab, prep.VoterReward())
+				br.Div(br, prep.AccumulatedVoted())
+				v.bondReward.Add(v.bondReward, br)
+			}
 		}
 	}
-	v.log.Tracef("Voter reward of %s = %d", v.owner, iScore)
+	v.log.Tracef("Voter reward of %s = %d (bond=%d)", v.owner, iScore, v.bondReward)
 
 	return iScore
 }
 
+// BondReward returns the part of the reward by bonds. It's valid after
+// CalculateReward.
+func (v *Voter) BondReward() *big.Int {
+	return v.bondReward
+}
+
 func NewVoter(owner module.Address, logger log.Logger) *Voter {
 	return &Voter{
 		owner:            owner,
 		accumulatedVotes: make(map[string]*big.Int),
+		accumulatedBonds: make(map[string]*big.Int),
+		bondReward:       new(big.Int),
 		log:              logger,
 	}
 }
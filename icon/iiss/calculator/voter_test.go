@@ -459,4 +459,10 @@ func TestVoter(t *testing.T) {
 	expectReward := big.NewInt(prep1.VoterReward().Int64() * voter.accumulatedVotes[key].Int64() / prep1.AccumulatedVoted().Int64())
 	r := voter.CalculateReward(pInfo)
 	assert.Equal(t, expectReward, r)
+
+	// BondReward
+	expectBonds := big.NewInt(100*pInfo.GetTermPeriod() + int64(10*(pInfo.OffsetLimit()-10)))
+	assert.Equal(t, expectBonds, voter.accumulatedBonds[key])
+	expectBondReward := big.NewInt(prep1.VoterReward().Int64() * expectBonds.Int64() / prep1.AccumulatedVoted().Int64())
+	assert.Equal(t, expectBondReward, voter.BondReward())
 }
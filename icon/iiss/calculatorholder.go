@@ -25,8 +25,18 @@ import (
 )
 
 type CalculatorHolder struct {
-	lock   sync.Mutex
-	runner Calculator
+	lock    sync.Mutex
+	runner  Calculator
+	history bool
+}
+
+// SetHistory sets whether calculators store rewards of accounts for
+// each term. It's applied to calculators started after it.
+func (h *CalculatorHolder) SetHistory(enabled bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.history = enabled
 }
 
 func (h *CalculatorHolder) Start(ess state.ExtensionSnapshot, logger log.Logger) {
@@ -34,7 +44,7 @@ func (h *CalculatorHolder) Start(ess state.ExtensionSnapshot, logger log.Logger)
 	defer h.lock.Unlock()
 
 	if ess != nil {
-		h.runner = updateCalculator(h.runner, ess, logger)
+		h.runner = updateCalculator(h.runner, ess, logger, h.history)
 	} else {
 		if h.runner != nil {
 			h.runner.Stop()
@@ -50,7 +60,7 @@ func (h *CalculatorHolder) Get() Calculator {
 	return h.runner
 }
 
-func updateCalculator(c Calculator, ess state.ExtensionSnapshot, logger log.Logger) Calculator {
+func updateCalculator(c Calculator, ess state.ExtensionSnapshot, logger log.Logger, history bool) Calculator {
 	essi := ess.(*ExtensionSnapshotImpl)
 	back := essi.Back2()
 	reward := essi.Reward()
@@ -60,5 +70,5 @@ func updateCalculator(c Calculator, ess state.ExtensionSnapshot, logger log.Logg
 		}
 		c.Stop()
 	}
-	return calculator.New(essi.DB(), back, reward, logger)
+	return calculator.New(essi.DB(), back, reward, logger, history)
 }
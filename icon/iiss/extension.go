@@ -30,6 +30,7 @@ import (
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/common/merkle"
 	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss/calculator"
 	"github.com/icon-project/goloop/icon/iiss/icobject"
 	"github.com/icon-project/goloop/icon/iiss/icreward"
 	"github.com/icon-project/goloop/icon/iiss/icstage"
@@ -49,12 +50,18 @@ type ExtensionSnapshotImpl struct {
 	back1  *icstage.Snapshot
 	back2  *icstage.Snapshot
 	reward *icreward.Snapshot
+
+	events prepEvents
 }
 
 func (s *ExtensionSnapshotImpl) DB() db.Database {
 	return s.database
 }
 
+func (s *ExtensionSnapshotImpl) Front() *icstage.Snapshot {
+	return s.front
+}
+
 func (s *ExtensionSnapshotImpl) Back1() *icstage.Snapshot {
 	return s.back1
 }
@@ -169,6 +176,7 @@ type ExtensionStateImpl struct {
 	log              []ExtensionLog
 	illegalDelegated map[string]*icstate.PRepStatusState
 	claimed          map[string]*Claimed
+	events           prepEvents
 
 	State  *icstate.State
 	Front  *icstage.State
@@ -195,6 +203,7 @@ func (es *ExtensionStateImpl) GetSnapshot() state.ExtensionSnapshot {
 		back1:    es.Back1.GetSnapshot(),
 		back2:    es.Back2.GetSnapshot(),
 		reward:   es.Reward.GetSnapshot(),
+		events:   es.events.clone(),
 	}
 }
 
@@ -207,6 +216,7 @@ func (es *ExtensionStateImpl) Reset(isnapshot state.ExtensionSnapshot) {
 	es.Back1.Reset(snapshot.back1)
 	es.Back2.Reset(snapshot.back2)
 	es.Reward.Reset(snapshot.reward)
+	es.events = snapshot.events.clone()
 }
 
 // ClearCache clear cache. It's called before executing first transaction
@@ -595,7 +605,7 @@ func (es *ExtensionStateImpl) DisqualifyPRep(cc icmodule.CallContext, address mo
 	pt := icmodule.PenaltyPRepDisqualification
 	ps := es.State.GetPRepStatusByOwner(address, false)
 	// Record PenaltyImposed eventlog
-	EmitPenaltyImposedEvent(cc, ps, pt)
+	es.onPenaltyImposed(cc, ps, pt)
 	if cc.Revision().Value() >= icmodule.RevisionEnableIISS3 {
 		rate, _ := es.State.GetSlashingRate(cc.Revision().Value(), pt)
 		return es.slash(cc, address, rate)
@@ -610,7 +620,7 @@ func (es *ExtensionStateImpl) PenalizeNonVoters(cc icmodule.CallContext, address
 		return icmodule.NotFoundError.Errorf("PRepNotFound(%s)", address)
 	}
 	pt := icmodule.PenaltyMissedNetworkProposalVote
-	EmitPenaltyImposedEvent(cc, ps, pt)
+	es.onPenaltyImposed(cc, ps, pt)
 	rate, _ := es.State.GetSlashingRate(cc.Revision().Value(), pt)
 	return es.slash(cc, address, rate)
 }
@@ -839,6 +849,7 @@ func (es *ExtensionStateImpl) OnExecutionBegin(wc icmodule.WorldContext) error {
 
 func (es *ExtensionStateImpl) OnExecutionEnd(wc icmodule.WorldContext, totalFee *big.Int, calculator Calculator) error {
 	var err error
+	es.events.height = wc.BlockHeight()
 	if err = es.handleTimerJob(wc); err != nil {
 		return err
 	}
@@ -1413,6 +1424,52 @@ func validateEndpoint(cc icmodule.CallContext, p2pEndpoint *string) error {
 	return nil
 }
 
+// GetIScoreHistory returns rewards of the account for terms overlapping with
+// the range of heights. Rewards are stored by the calculator only if it's
+// enabled by the node.
+func (es *ExtensionStateImpl) GetIScoreHistory(from module.Address, start, end int64) ([]interface{}, error) {
+	h, err := calculator.NewRewardHistory(es.database)
+	if err != nil {
+		return nil, err
+	}
+	records, err := h.Query(from, start, end, icmodule.MaxIScoreHistory)
+	if err != nil {
+		return nil, scoreresult.UnknownFailureError.Wrapf(
+			err,
+			"Failed to get IScore history: from=%v",
+			from,
+		)
+	}
+	history := make([]interface{}, len(records))
+	for i, r := range records {
+		history[i] = r.ToJSON()
+	}
+	return history, nil
+}
+
+// GetPRepHistory returns events of the P-Rep in the range of heights.
+// Events are recorded on the finalization of blocks only if it's enabled
+// by the node.
+func (es *ExtensionStateImpl) GetPRepHistory(owner module.Address, start, end int64) ([]interface{}, error) {
+	h, err := NewPRepHistory(es.database)
+	if err != nil {
+		return nil, err
+	}
+	records, err := h.Query(owner, start, end, icmodule.MaxPRepHistory)
+	if err != nil {
+		return nil, scoreresult.UnknownFailureError.Wrapf(
+			err,
+			"Failed to get PRep history: owner=%v",
+			owner,
+		)
+	}
+	history := make([]interface{}, len(records))
+	for i, r := range records {
+		history[i] = r.ToJSON()
+	}
+	return history, nil
+}
+
 func (es *ExtensionStateImpl) GetIScore(from module.Address, revision int, txID []byte) (*big.Int, error) {
 	iScore := new(big.Int)
 	if es.Reward == nil {
@@ -1986,7 +2043,7 @@ func (es *ExtensionStateImpl) HandleDoubleSignReport(
 	if err := es.State.ImposePenalty(sc, pt, ps); err != nil {
 		return err
 	}
-	EmitPenaltyImposedEvent(cc, ps, pt)
+	es.onPenaltyImposed(cc, ps, pt)
 
 	rate, err := es.State.GetSlashingRate(sc.RevisionValue(), pt)
 	if err != nil {
@@ -57,7 +57,7 @@ func (es *ExtensionStateImpl) handleBlockValidationPenaltyBeforeRevIISS4R0(
 		return err
 	}
 	// Emit PenaltyImposed eventlog for ValidationFailurePenalty
-	EmitPenaltyImposedEvent(cc, ps, icmodule.PenaltyValidationFailure)
+	es.onPenaltyImposed(cc, ps, icmodule.PenaltyValidationFailure)
 
 	// Slashing for AccumulatedValidationFailurePenalty
 	revision := cc.Revision().Value()
@@ -107,7 +107,7 @@ func (es *ExtensionStateImpl) handleBlockValidationPenaltyAfterRevIISS4R0(
 
 	isIISS4Activated := sc.TermIISSVersion() >= icstate.IISSVersion4
 	for _, pt = range penaltyTypes {
-		EmitPenaltyImposedEvent(cc, ps, pt)
+		es.onPenaltyImposed(cc, ps, pt)
 
 		if isIISS4Activated || pt == icmodule.PenaltyAccumulatedValidationFailure {
 			// Slashing
@@ -129,6 +129,13 @@ func (es *ExtensionStateImpl) handleBlockValidationPenaltyAfterRevIISS4R0(
 	}
 }
 
+// onPenaltyImposed emits PenaltyImposed event, and keeps it for P-Rep history.
+func (es *ExtensionStateImpl) onPenaltyImposed(
+	cc icmodule.CallContext, ps *icstate.PRepStatusState, pt icmodule.PenaltyType) {
+	EmitPenaltyImposedEvent(cc, ps, pt)
+	es.events.addPenalty(cc.BlockHeight(), ps.Owner(), pt)
+}
+
 func (es *ExtensionStateImpl) slash(cc icmodule.CallContext, owner module.Address, rate icmodule.Rate) error {
 	if !rate.IsValid() {
 		return errors.Errorf("Invalid slashRate %d", rate.Percent())
@@ -210,6 +217,7 @@ func (es *ExtensionStateImpl) slash(cc icmodule.CallContext, owner module.Addres
 		return err
 	}
 	err := cc.HandleBurn(state.SystemAddress, slashedStakeSum)
+	es.events.addSlashed(owner, slashedStakeSum)
 
 	logger.TSystemf(
 		"IISS slash end owner=%s slashedBondSum=%v slashedStakeSum=%v oldTotalStake=%v newTotalStake=%v",
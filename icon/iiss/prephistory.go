@@ -0,0 +1,494 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iiss
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/icon/icdb"
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss/icstate"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+type PRepHistoryType int
+
+const (
+	PHTerm PRepHistoryType = iota
+	PHPenalty
+	PHGrade
+	PHStatus
+	PHJail
+)
+
+var prepHistoryTypeNames = []string{
+	"term",
+	"penalty",
+	"grade",
+	"status",
+	"jail",
+}
+
+func (t PRepHistoryType) String() string {
+	if t >= PHTerm && t <= PHJail {
+		return prepHistoryTypeNames[t]
+	}
+	return ""
+}
+
+// PRepHistoryRecord is an event of a P-Rep at the height.
+// Fields other than Type and Height are used depending on the type.
+type PRepHistoryRecord struct {
+	Type   PRepHistoryType
+	Height int64
+
+	// PHTerm: validation stats for the term ended at Height
+	StartHeight int64
+	Total       int64
+	Fail        int64
+
+	// PHPenalty: type of the penalty and the amount of slashed stake
+	Penalty icmodule.PenaltyType
+	Slashed *big.Int
+
+	// PHGrade, PHStatus and PHJail: values before and after the change
+	From int
+	To   int
+}
+
+func (r *PRepHistoryRecord) ToJSON() map[string]interface{} {
+	jso := make(map[string]interface{})
+	jso["type"] = r.Type.String()
+	jso["height"] = r.Height
+	switch r.Type {
+	case PHTerm:
+		jso["startHeight"] = r.StartHeight
+		jso["endHeight"] = r.Height
+		jso["total"] = r.Total
+		jso["validated"] = r.Total - r.Fail
+		jso["missed"] = r.Fail
+	case PHPenalty:
+		jso["penaltyType"] = int(r.Penalty)
+		jso["slashed"] = r.Slashed
+	default:
+		jso["from"] = r.From
+		jso["to"] = r.To
+	}
+	return jso
+}
+
+type prepPenalty struct {
+	owner  module.Address
+	record *PRepHistoryRecord
+}
+
+// prepEvents keeps penalties imposed while executing a block, which can't
+// be known from the state. They're carried by ExtensionSnapshotImpl until
+// the block is finalized.
+type prepEvents struct {
+	height    int64
+	penalties []prepPenalty
+}
+
+func (e *prepEvents) clone() prepEvents {
+	var penalties []prepPenalty
+	if len(e.penalties) > 0 {
+		penalties = make([]prepPenalty, len(e.penalties))
+		copy(penalties, e.penalties)
+	}
+	return prepEvents{
+		height:    e.height,
+		penalties: penalties,
+	}
+}
+
+func (e *prepEvents) addPenalty(height int64, owner module.Address, pt icmodule.PenaltyType) {
+	e.penalties = append(e.penalties, prepPenalty{
+		owner: owner,
+		record: &PRepHistoryRecord{
+			Type:    PHPenalty,
+			Height:  height,
+			Penalty: pt,
+			Slashed: new(big.Int),
+		},
+	})
+}
+
+// addSlashed adds the amount to the last penalty of the owner.
+func (e *prepEvents) addSlashed(owner module.Address, amount *big.Int) {
+	for i := len(e.penalties) - 1; i >= 0; i-- {
+		if e.penalties[i].owner.Equal(owner) {
+			// records may be shared with snapshots
+			r := *e.penalties[i].record
+			r.Slashed = new(big.Int).Add(r.Slashed, amount)
+			e.penalties[i].record = &r
+			return
+		}
+	}
+}
+
+var keyPRepHistoryBase = []byte("base")
+
+// prepTermBase is the validation stats of a P-Rep at the start of the term.
+type prepTermBase struct {
+	StartHeight int64
+	Total       int64
+	Fail        int64
+}
+
+// PRepHistory keeps events of P-Reps in the database. It's written on the
+// finalization of blocks by PRepHistoryRecorder if it's enabled.
+type PRepHistory struct {
+	bk db.Bucket
+}
+
+func keyOfPRepHistory(key string, height int64) []byte {
+	return append([]byte(key), intconv.Int64ToBytes(height)...)
+}
+
+func keyOfPRepTermBase(key string) []byte {
+	return append(append([]byte{}, keyPRepHistoryBase...), key...)
+}
+
+// Heights returns heights having records of the P-Rep in ascending order.
+func (h *PRepHistory) Heights(owner module.Address) ([]int64, error) {
+	return h.heights(icutils.ToKey(owner))
+}
+
+func (h *PRepHistory) heights(key string) ([]int64, error) {
+	bs, err := h.bk.Get([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+	var heights []int64
+	if len(bs) > 0 {
+		if _, err = codec.BC.UnmarshalFromBytes(bs, &heights); err != nil {
+			return nil, err
+		}
+	}
+	return heights, nil
+}
+
+func (h *PRepHistory) Get(owner module.Address, height int64) ([]*PRepHistoryRecord, error) {
+	return h.get(icutils.ToKey(owner), height)
+}
+
+func (h *PRepHistory) get(key string, height int64) ([]*PRepHistoryRecord, error) {
+	bs, err := h.bk.Get(keyOfPRepHistory(key, height))
+	if err != nil || len(bs) == 0 {
+		return nil, err
+	}
+	var records []*PRepHistoryRecord
+	if _, err = codec.BC.UnmarshalFromBytes(bs, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Query returns records of the P-Rep in the range of heights.
+// It returns at most limit records from the latest one.
+func (h *PRepHistory) Query(owner module.Address, from, to int64, limit int) ([]*PRepHistoryRecord, error) {
+	key := icutils.ToKey(owner)
+	heights, err := h.heights(key)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*PRepHistoryRecord, 0)
+	for i := len(heights) - 1; i >= 0 && len(records) < limit; i-- {
+		height := heights[i]
+		if height < from {
+			break
+		}
+		if height > to {
+			continue
+		}
+		rs, err := h.get(key, height)
+		if err != nil {
+			return nil, err
+		}
+		for j := len(rs) - 1; j >= 0 && len(records) < limit; j-- {
+			records = append(records, rs[j])
+		}
+	}
+	return records, nil
+}
+
+func (h *PRepHistory) add(key string, height int64, records []*PRepHistoryRecord) error {
+	rs, err := h.get(key, height)
+	if err != nil {
+		return err
+	}
+	bs, err := codec.BC.MarshalToBytes(append(rs, records...))
+	if err != nil {
+		return err
+	}
+	if err = h.bk.Set(keyOfPRepHistory(key, height), bs); err != nil {
+		return err
+	}
+	if len(rs) > 0 {
+		return nil
+	}
+
+	heights, err := h.heights(key)
+	if err != nil {
+		return err
+	}
+	idx := sort.Search(len(heights), func(i int) bool {
+		return heights[i] >= height
+	})
+	heights = append(heights, 0)
+	copy(heights[idx+1:], heights[idx:])
+	heights[idx] = height
+	if bs, err = codec.BC.MarshalToBytes(heights); err != nil {
+		return err
+	}
+	return h.bk.Set([]byte(key), bs)
+}
+
+func (h *PRepHistory) getTermBase(key string) (*prepTermBase, error) {
+	bs, err := h.bk.Get(keyOfPRepTermBase(key))
+	if err != nil || len(bs) == 0 {
+		return nil, err
+	}
+	base := new(prepTermBase)
+	if _, err = codec.BC.UnmarshalFromBytes(bs, base); err != nil {
+		return nil, err
+	}
+	return base, nil
+}
+
+func (h *PRepHistory) setTermBase(key string, base *prepTermBase) error {
+	bs, err := codec.BC.MarshalToBytes(base)
+	if err != nil {
+		return err
+	}
+	return h.bk.Set(keyOfPRepTermBase(key), bs)
+}
+
+func NewPRepHistory(dbase db.Database) (*PRepHistory, error) {
+	bk, err := dbase.GetBucket(icdb.PRepHistory)
+	if err != nil {
+		return nil, err
+	}
+	return &PRepHistory{bk: bk}, nil
+}
+
+type prepHistoryState struct {
+	owner  module.Address
+	grade  icstate.Grade
+	status icstate.Status
+	jail   int
+}
+
+func newPRepHistoryState(ps *icstate.PRepStatusState) prepHistoryState {
+	return prepHistoryState{
+		owner:  ps.Owner(),
+		grade:  ps.Grade(),
+		status: ps.Status(),
+		jail:   ps.JailFlags(),
+	}
+}
+
+// PRepHistoryRecorder writes events of P-Reps to PRepHistory on the
+// finalization of blocks. Changes of grade, status and jail are found by
+// comparing the states of P-Reps with the ones of the previous block, so
+// the changes while the node is stopped are not recorded.
+// Snapshots of finalized blocks are queued, and they are recorded in order
+// by the worker in the background like the calculator, so that it doesn't
+// delay the finalization of blocks.
+type PRepHistoryRecorder struct {
+	lock    sync.Mutex
+	enabled bool
+	running bool
+	queue   []*ExtensionSnapshotImpl
+	worker  sync.WaitGroup
+
+	// used only by the worker
+	height    int64
+	termStart int64
+	preps     map[string]prepHistoryState
+}
+
+// SetEnabled sets whether events of P-Reps are recorded. Queued snapshots
+// are dropped if it's disabled.
+func (r *PRepHistoryRecorder) SetEnabled(enabled bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.enabled = enabled
+	if !enabled {
+		r.queue = nil
+	}
+}
+
+// Record queues the snapshot of the finalized block to be recorded, and
+// starts the worker if it's not running.
+func (r *PRepHistoryRecorder) Record(ess state.ExtensionSnapshot, logger log.Logger) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.enabled || ess == nil {
+		return
+	}
+	r.queue = append(r.queue, ess.(*ExtensionSnapshotImpl))
+	if !r.running {
+		r.running = true
+		r.worker.Add(1)
+		go r.run(logger)
+	}
+}
+
+// Wait waits for the worker to record queued snapshots.
+func (r *PRepHistoryRecorder) Wait() {
+	r.worker.Wait()
+}
+
+func (r *PRepHistoryRecorder) next() *ExtensionSnapshotImpl {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if len(r.queue) == 0 {
+		r.running = false
+		return nil
+	}
+	essi := r.queue[0]
+	r.queue[0] = nil
+	r.queue = r.queue[1:]
+	return essi
+}
+
+func (r *PRepHistoryRecorder) run(logger log.Logger) {
+	defer r.worker.Done()
+	for essi := r.next(); essi != nil; essi = r.next() {
+		if err := r.record(essi); err != nil {
+			logger.Warnf("Failed to record P-Rep history err=%+v", err)
+		}
+	}
+}
+
+func (r *PRepHistoryRecorder) record(essi *ExtensionSnapshotImpl) error {
+	height := essi.events.height
+	if height <= r.height {
+		// the block is not executed by the node or it's already recorded
+		return nil
+	}
+	r.height = height
+
+	h, err := NewPRepHistory(essi.database)
+	if err != nil {
+		return err
+	}
+	records := make(map[string][]*PRepHistoryRecord)
+	for _, p := range essi.events.penalties {
+		key := icutils.ToKey(p.owner)
+		records[key] = append(records[key], p.record)
+	}
+
+	es := essi.NewState(true).(*ExtensionStateImpl)
+	pss, err := es.State.GetPRepStatuses()
+	if err != nil {
+		return err
+	}
+	statuses := make(map[string]*icstate.PRepStatusState, len(pss))
+	for _, ps := range pss {
+		statuses[icutils.ToKey(ps.Owner())] = ps
+	}
+	for key, old := range r.preps {
+		if _, ok := statuses[key]; !ok {
+			if ps := es.State.GetPRepStatusByOwner(old.owner, false); ps != nil {
+				statuses[key] = ps
+			}
+		}
+	}
+
+	preps := make(map[string]prepHistoryState, len(statuses))
+	for key, ps := range statuses {
+		cur := newPRepHistoryState(ps)
+		preps[key] = cur
+		old, ok := r.preps[key]
+		if !ok {
+			continue
+		}
+		if old.grade != cur.grade {
+			records[key] = append(records[key], &PRepHistoryRecord{
+				Type: PHGrade, Height: height, From: int(old.grade), To: int(cur.grade),
+			})
+		}
+		if old.status != cur.status {
+			records[key] = append(records[key], &PRepHistoryRecord{
+				Type: PHStatus, Height: height, From: int(old.status), To: int(cur.status),
+			})
+		}
+		if old.jail != cur.jail {
+			records[key] = append(records[key], &PRepHistoryRecord{
+				Type: PHJail, Height: height, From: old.jail, To: cur.jail,
+			})
+		}
+	}
+	r.preps = preps
+
+	// stats of the term are recorded at the last block of the term
+	if term := es.State.GetTermSnapshot(); term != nil && term.StartHeight() != r.termStart {
+		if r.termStart != 0 {
+			for key, ps := range statuses {
+				base, err := h.getTermBase(key)
+				if err != nil {
+					return err
+				}
+				if base == nil || base.StartHeight != r.termStart {
+					continue
+				}
+				total := ps.GetVTotal(height) - base.Total
+				if total <= 0 {
+					continue
+				}
+				records[key] = append(records[key], &PRepHistoryRecord{
+					Type:        PHTerm,
+					Height:      height,
+					StartHeight: base.StartHeight,
+					Total:       total,
+					Fail:        ps.GetVFail(height) - base.Fail,
+				})
+			}
+			for _, ps := range pss {
+				if err = h.setTermBase(icutils.ToKey(ps.Owner()), &prepTermBase{
+					StartHeight: term.StartHeight(),
+					Total:       ps.GetVTotal(height),
+					Fail:        ps.GetVFail(height),
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		r.termStart = term.StartHeight()
+	}
+
+	for key, rs := range records {
+		if err = h.add(key, height, rs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
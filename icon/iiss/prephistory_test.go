@@ -0,0 +1,148 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iiss
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss/icstate"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+)
+
+func TestPRepHistory(t *testing.T) {
+	dbase := db.NewMapDB()
+	h, err := NewPRepHistory(dbase)
+	assert.NoError(t, err)
+
+	p1 := common.MustNewAddressFromString("hx1")
+	p2 := common.MustNewAddressFromString("hx2")
+	k1 := icutils.ToKey(p1)
+
+	// heights are stored in ascending order regardless of the order of writes
+	for _, height := range []int64{100, 300, 200} {
+		assert.NoError(t, h.add(k1, height, []*PRepHistoryRecord{
+			{Type: PHTerm, Height: height, StartHeight: height - 99, Total: 100, Fail: 10},
+		}))
+	}
+	assert.NoError(t, h.add(k1, 200, []*PRepHistoryRecord{
+		{Type: PHPenalty, Height: 200, Penalty: icmodule.PenaltyValidationFailure, Slashed: big.NewInt(5)},
+		{Type: PHGrade, Height: 200, From: int(icstate.GradeMain), To: int(icstate.GradeCandidate)},
+	}))
+
+	heights, err := h.Heights(p1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{100, 200, 300}, heights)
+
+	records, err := h.Get(p1, 200)
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+	assert.Equal(t, PHPenalty, records[1].Type)
+	assert.Equal(t, icmodule.PenaltyValidationFailure, records[1].Penalty)
+	assert.Equal(t, int64(5), records[1].Slashed.Int64())
+
+	records, err = h.Query(p1, 0, 1000, 10)
+	assert.NoError(t, err)
+	assert.Len(t, records, 5)
+	assert.Equal(t, int64(300), records[0].Height)
+	assert.Equal(t, PHGrade, records[1].Type)
+	assert.Equal(t, int64(100), records[4].Height)
+
+	records, err = h.Query(p1, 150, 250, 10)
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+	for _, r := range records {
+		assert.Equal(t, int64(200), r.Height)
+	}
+
+	records, err = h.Query(p1, 0, 1000, 2)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	jso := records[1].ToJSON()
+	assert.Equal(t, "grade", jso["type"])
+	assert.Equal(t, int(icstate.GradeCandidate), jso["to"])
+	jso = records[0].ToJSON()
+	assert.Equal(t, "term", jso["type"])
+	assert.Equal(t, int64(90), jso["validated"])
+	assert.Equal(t, int64(10), jso["missed"])
+
+	records, err = h.Query(p2, 0, 1000, 10)
+	assert.NoError(t, err)
+	assert.Len(t, records, 0)
+}
+
+func TestPRepEvents(t *testing.T) {
+	p1 := common.MustNewAddressFromString("hx1")
+	p2 := common.MustNewAddressFromString("hx2")
+
+	var e prepEvents
+	e.height = 10
+	e.addPenalty(10, p1, icmodule.PenaltyValidationFailure)
+	e.addPenalty(10, p2, icmodule.PenaltyValidationFailure)
+	snapshot := e.clone()
+
+	e.addPenalty(10, p1, icmodule.PenaltyAccumulatedValidationFailure)
+	e.addSlashed(p1, big.NewInt(7))
+	e.addSlashed(p2, big.NewInt(3))
+
+	assert.Len(t, e.penalties, 3)
+	assert.Zero(t, e.penalties[0].record.Slashed.Sign())
+	assert.Equal(t, int64(3), e.penalties[1].record.Slashed.Int64())
+	assert.Equal(t, int64(7), e.penalties[2].record.Slashed.Int64())
+
+	// the snapshot is not affected
+	assert.Len(t, snapshot.penalties, 2)
+	assert.Equal(t, int64(10), snapshot.height)
+	assert.Zero(t, snapshot.penalties[1].record.Slashed.Sign())
+}
+
+func TestPRepHistoryRecorder_Record(t *testing.T) {
+	dbase := db.NewMapDB()
+	p1 := common.MustNewAddressFromString("hx1")
+	newSnapshot := func(height int64) *ExtensionSnapshotImpl {
+		essi := NewExtensionSnapshot(dbase, nil).(*ExtensionSnapshotImpl)
+		essi.events.height = height
+		essi.events.addPenalty(height, p1, icmodule.PenaltyValidationFailure)
+		return essi
+	}
+
+	var r PRepHistoryRecorder
+	logger := icutils.NewIconLogger(nil)
+	r.Record(newSnapshot(10), logger)
+
+	r.SetEnabled(true)
+	for _, height := range []int64{11, 12, 12} {
+		r.Record(newSnapshot(height), logger)
+	}
+
+	r.Wait()
+
+	h, err := NewPRepHistory(dbase)
+	assert.NoError(t, err)
+	heights, err := h.Heights(p1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{11, 12}, heights)
+	records, err := h.Get(p1, 12)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+}
@@ -21,6 +21,7 @@ import (
 	"math/big"
 	"os"
 	"path"
+	"strconv"
 
 	"github.com/icon-project/goloop/block"
 	"github.com/icon-project/goloop/chain/base"
@@ -50,8 +51,9 @@ import (
 )
 
 type platform struct {
-	calculator iiss.CalculatorHolder
-	base       string
+	calculator  iiss.CalculatorHolder
+	prepHistory iiss.PRepHistoryRecorder
+	base        string
 }
 
 func (p *platform) NewContractManager(dbase db.Database, dir string, logger log.Logger) (contract.ContractManager, error) {
@@ -115,6 +117,8 @@ func (p *platform) NewBaseTransaction(wc state.WorldContext) (module.Transaction
 }
 
 func (p *platform) OnExtensionSnapshotFinalization(ess state.ExtensionSnapshot, logger log.Logger) {
+	p.prepHistory.Record(ess, logger)
+
 	// Start background calculator if it's not started.
 	p.calculator.Start(ess, logger)
 }
@@ -284,10 +288,37 @@ func (p *platform) SetBlockV1Proof(root []byte, size int64, votes *blockv0.Block
 	return os.WriteFile(file, bs, os.FileMode(0500))
 }
 
+func lookupBoolEnv(name string) (value bool, ok bool, err error) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return false, false, nil
+	}
+	value, err = strconv.ParseBool(v)
+	if err != nil {
+		return false, true, errors.IllegalArgumentError.Wrapf(err,
+			"InvalidBoolOption(%s=%s)", name, v)
+	}
+	return value, true, nil
+}
+
 func NewPlatform(base string, cid int) (base.Platform, error) {
-	return &platform{
+	p := &platform{
 		base: base,
-	}, nil
+	}
+	// Rewards of accounts for each term and events of P-Reps are stored
+	// only if it's enabled, because they're not a part of the state and
+	// they take space.
+	if enabled, ok, err := lookupBoolEnv(icmodule.EnvRewardHistory); err != nil {
+		return nil, err
+	} else if ok {
+		p.calculator.SetHistory(enabled)
+	}
+	if enabled, ok, err := lookupBoolEnv(icmodule.EnvPRepHistory); err != nil {
+		return nil, err
+	} else if ok {
+		p.prepHistory.SetEnabled(enabled)
+	}
+	return p, nil
 }
 
 func init() {
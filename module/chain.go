@@ -37,6 +37,9 @@ type Chain interface {
 	TransactionTimeout() time.Duration
 	ChildrenLimit() int
 	NephewsLimit() int
+	PeerBanDuration() time.Duration
+	PeerBanThreshold() int
+	PeerPenaltyDecayTime() time.Duration
 	ValidateTxOnSend() bool
 	Genesis() []byte
 	GenesisStorage() GenesisStorage
@@ -1,6 +1,9 @@
 package module
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type NetworkManager interface {
 	Start() error
@@ -21,6 +24,26 @@ type NetworkManager interface {
 
 	SetTrustSeeds(seeds string)
 	SetInitialRoles(roles ...Role)
+	// SetBandwidthLimits sets limits of sending bytes per second for
+	// reactors in the form of "NAME:BYTES_PER_SECOND,...".
+	SetBandwidthLimits(limits string) error
+
+	// Bans returns active bans of peers.
+	Bans() []PeerBan
+	// Ban bans the peer with the id or the ip for the duration. If the
+	// duration is not positive, it uses the configured duration.
+	Ban(id PeerID, ip string, d time.Duration, reason string) error
+	// Unban removes bans matching the id or the ip. It returns false
+	// if there is no matching ban.
+	Unban(id PeerID, ip string) bool
+}
+
+// PeerBan is a ban of the peer by ID or IP. Until is in unix time seconds.
+type PeerBan struct {
+	ID     string `json:"id,omitempty"`
+	IP     string `json:"ip,omitempty"`
+	Until  int64  `json:"until"`
+	Reason string `json:"reason,omitempty"`
 }
 
 type Reactor interface {
@@ -39,6 +39,7 @@ const (
 	ReportDoubleSign
 	FixJCLSteps
 	ReportConfigureEvents
+	BLSNetworkType
 	LastRevisionBit
 
 	UseNIDInConsensusMessage = ReportDoubleSign
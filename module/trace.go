@@ -41,6 +41,7 @@ const (
 	TraceModeNone TraceMode = iota
 	TraceModeInvoke
 	TraceModeBalanceChange
+	TraceModeCallTree
 )
 
 type OpType int
@@ -81,3 +82,23 @@ type TraceCallback interface {
 	OnFrameExit(success bool) error
 	OnBalanceChange(opType OpType, from, to Address, amount *big.Int) error
 }
+
+// TraceCallInfo is information of the call made in the frame.
+// Method is empty if it's not a contract call (ex. transfer).
+type TraceCallInfo struct {
+	From   Address
+	To     Address
+	Value  *big.Int
+	Method string
+	Params interface{}
+}
+
+// CallTraceCallback is implemented by TraceCallback supporting
+// TraceModeCallTree. OnCallStart is called right after OnFrameEnter,
+// and OnCallEnd is called right before OnFrameExit.
+// Params and result are decoded for JSON.
+type CallTraceCallback interface {
+	OnCallStart(info *TraceCallInfo) error
+	OnCallEnd(status error, stepUsed *big.Int, result interface{}) error
+	OnEvent(log EventLog) error
+}
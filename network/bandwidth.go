@@ -0,0 +1,81 @@
+package network
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// bandwidthLimiter limits sending bytes per second with token bucket.
+// It allows burst up to the limit, and the bucket may go negative by
+// the last packet, then it drops packets until it's refilled.
+type bandwidthLimiter struct {
+	mtx    sync.Mutex
+	limit  int64
+	tokens int64
+	last   time.Time
+}
+
+func newBandwidthLimiter(limit int64) *bandwidthLimiter {
+	return &bandwidthLimiter{
+		limit:  limit,
+		tokens: limit,
+		last:   time.Now(),
+	}
+}
+
+func (l *bandwidthLimiter) _refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens += int64(float64(l.limit) * elapsed.Seconds())
+		if l.tokens > l.limit {
+			l.tokens = l.limit
+		}
+		l.last = now
+	}
+}
+
+// allow returns whether it can send now.
+func (l *bandwidthLimiter) allow(now time.Time) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l._refill(now)
+	return l.tokens > 0
+}
+
+// consume takes tokens for sent bytes.
+func (l *bandwidthLimiter) consume(n int64) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.tokens -= n
+}
+
+// ParseBandwidthLimits parses bandwidth limits in the form of
+// "NAME:BYTES_PER_SECOND,...". NAME is the name of the reactor.
+func ParseBandwidthLimits(s string) (map[string]int64, error) {
+	limits := make(map[string]int64)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		kv := strings.SplitN(item, ":", 2)
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"InvalidBandwidthLimit(limit=%s)", item)
+		}
+		v, err := strconv.ParseInt(kv[1], 0, 64)
+		if err != nil || v < 0 {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"InvalidBandwidthLimit(limit=%s)", item)
+		}
+		if v > 0 {
+			limits[kv[0]] = v
+		}
+	}
+	return limits, nil
+}
@@ -0,0 +1,45 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_bandwidthLimiter(t *testing.T) {
+	l := newBandwidthLimiter(1000)
+	now := l.last
+
+	assert.True(t, l.allow(now))
+	l.consume(600)
+	assert.True(t, l.allow(now))
+	l.consume(600)
+	assert.False(t, l.allow(now))
+
+	// refilled by elapsed time
+	assert.False(t, l.allow(now.Add(100*time.Millisecond)))
+	assert.True(t, l.allow(now.Add(300*time.Millisecond)))
+
+	// it doesn't exceed the limit
+	assert.True(t, l.allow(now.Add(time.Hour)))
+	assert.Equal(t, int64(1000), l.tokens)
+}
+
+func Test_ParseBandwidthLimits(t *testing.T) {
+	limits, err := ParseBandwidthLimits("")
+	assert.NoError(t, err)
+	assert.Len(t, limits, 0)
+
+	limits, err = ParseBandwidthLimits("transaction:1000, fastsync:0x100 ,consensus:0")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{
+		"transaction": 1000,
+		"fastsync":    256,
+	}, limits)
+
+	for _, s := range []string{"transaction", ":100", "transaction:-1", "transaction:abc"} {
+		_, err = ParseBandwidthLimits(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -22,6 +22,9 @@ const (
 	DuplicatedPeerError
 	InvalidMessageSequenceError
 	InvalidSignatureError
+	BannedPeerError
+	BandwidthExceededError
+	TooManyPenaltiesError
 )
 
 var (
@@ -41,6 +44,9 @@ var (
 	ErrDuplicatedPeer            = errors.NewBase(DuplicatedPeerError, "DuplicatedPeer")
 	ErrInvalidMessageSequence    = errors.NewBase(InvalidMessageSequenceError, "InvalidMessageSequence")
 	ErrInvalidSignature          = errors.NewBase(InvalidSignatureError, "InvalidSignatureError")
+	ErrBannedPeer                = errors.NewBase(BannedPeerError, "BannedPeer")
+	ErrBandwidthExceeded         = errors.NewBase(BandwidthExceededError, "BandwidthExceeded")
+	ErrTooManyPenalties          = errors.NewBase(TooManyPenaltiesError, "TooManyPenalties")
 	ErrIllegalArgument           = errors.ErrIllegalArgument
 )
 
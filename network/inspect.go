@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/metric"
 )
 
 func Inspect(c module.Chain, informal bool) map[string]interface{} {
@@ -120,3 +121,50 @@ func protocolHandlerToMap(ph *protocolHandler) map[string]interface{} {
 	}
 	return m
 }
+
+// TrafficView shows traffic statistics of the chain for each protocol
+// and each connected peer. Protocols are named by their reactors.
+type TrafficView struct {
+	Protocols map[string]metric.TrafficStat `json:"protocols"`
+	Peers     map[string]metric.TrafficStat `json:"peers"`
+	Limits    map[string]int64              `json:"limits,omitempty"`
+}
+
+func Traffic(c module.Chain) *TrafficView {
+	var mgr *manager
+	if nm := c.NetworkManager(); nm == nil {
+		return nil
+	} else {
+		mgr = nm.(*manager)
+	}
+	mgr.mtx.RLock()
+	names := make(map[uint16]string, len(mgr.protocolHandlers)+1)
+	names[p2pProtoControl.Uint16()] = "p2p"
+	for k, ph := range mgr.protocolHandlers {
+		names[k] = ph.getName()
+	}
+	limits := make(map[string]int64, len(mgr.bwLimits))
+	for k, v := range mgr.bwLimits {
+		limits[k] = v
+	}
+	mgr.mtx.RUnlock()
+
+	v := &TrafficView{
+		Protocols: make(map[string]metric.TrafficStat),
+		Peers:     make(map[string]metric.TrafficStat),
+		Limits:    limits,
+	}
+	for k, stat := range mgr.mtr.Traffic() {
+		name, ok := names[k]
+		if !ok {
+			name = fmt.Sprintf("%#04x", k)
+		}
+		v.Protocols[name] = stat
+	}
+	for _, p := range mgr.p2p.findPeers(nil) {
+		if id := idOf(p.ID()); len(id) > 0 {
+			v.Peers[id] = p.Traffic()
+		}
+	}
+	return v
+}
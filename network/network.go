@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
@@ -35,6 +36,8 @@ type manager struct {
 	mtr *metric.NetworkMetric
 
 	streamReactors []*streamReactor
+
+	bwLimits map[string]int64
 }
 
 func NewManager(c module.Chain, nt module.NetworkTransport, trustSeeds string, roles ...module.Role) module.NetworkManager {
@@ -58,6 +61,15 @@ func NewManager(c module.Chain, nt module.NetworkTransport, trustSeeds string, r
 	m.p2p.setConnectionLimit(p2pConnTypeChildren, c.ChildrenLimit())
 	m.p2p.setConnectionLimit(p2pConnTypeNephew, c.NephewsLimit())
 
+	m.p2p.rp.setDuration(c.PeerBanDuration())
+	m.p2p.rp.setThreshold(c.PeerBanThreshold())
+	m.p2p.rp.setDecayTime(c.PeerPenaltyDecayTime())
+	if dbase := c.Database(); dbase != nil {
+		if err := m.p2p.rp.load(dbase); err != nil {
+			m.logger.Warnf("fail to load peer bans err=%+v", err)
+		}
+	}
+
 	m.logger.Infof("NetworkManager use channel=%s for cid=%#x nid=%#x",
 		m.channel, c.CID(), c.NID())
 	return m
@@ -159,6 +171,7 @@ func (m *manager) RegisterReactor(
 
 		ph = newProtocolHandler(m, pi, piList, reactor, name, priority, policy, m.logger)
 		m.p2p.setCbFunc(pi, ph.onPacket, ph.onEvent, p2pEventJoin, p2pEventLeave, p2pEventDuplicate)
+		m.p2p.setBandwidthLimit(pi, m.bwLimits[name])
 		m.protocolHandlers[k] = ph
 		m.t.addProtocol(m.channel, pi)
 	}
@@ -250,6 +263,39 @@ func (m *manager) SetInitialRoles(roles ...module.Role) {
 	m.p2p.setRole(NewPeerRoleFlag(roles...))
 }
 
+func (m *manager) SetBandwidthLimits(limits string) error {
+	bwLimits, err := ParseBandwidthLimits(limits)
+	if err != nil {
+		return err
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.bwLimits = bwLimits
+	for k, ph := range m.protocolHandlers {
+		m.p2p.setBandwidthLimit(module.ProtocolInfo(k), bwLimits[ph.getName()])
+	}
+	return nil
+}
+
+func (m *manager) Bans() []module.PeerBan {
+	return m.p2p.rp.list()
+}
+
+func (m *manager) Ban(id module.PeerID, ip string, d time.Duration, reason string) error {
+	b, err := m.p2p.rp.ban(id, ip, d, reason)
+	if err != nil {
+		return err
+	}
+	m.p2p.closeBannedPeers(b)
+	return nil
+}
+
+func (m *manager) Unban(id module.PeerID, ip string) bool {
+	return m.p2p.rp.unban(id, ip)
+}
+
 func ChannelOfNetID(id int) string {
 	return strconv.FormatInt(int64(id), 16)
 }
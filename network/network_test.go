@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 )
@@ -296,6 +297,10 @@ func (c *dummyChain) Logger() log.Logger                    { return c.logger }
 func (c *dummyChain) MetricContext() context.Context        { return c.metricCtx }
 func (c *dummyChain) ChildrenLimit() int                    { return -1 }
 func (c *dummyChain) NephewsLimit() int                     { return -1 }
+func (c *dummyChain) PeerBanDuration() time.Duration        { return 0 }
+func (c *dummyChain) PeerBanThreshold() int                 { return 0 }
+func (c *dummyChain) PeerPenaltyDecayTime() time.Duration   { return 0 }
+func (c *dummyChain) Database() db.Database                 { return nil }
 func (c *dummyChain) NetworkManager() module.NetworkManager { return c.nm }
 
 type dummyReactor struct{}
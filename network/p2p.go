@@ -97,6 +97,13 @@ type PeerToPeer struct {
 	allowedSeeds *PeerIDSet
 	allowedPeers *PeerIDSet
 
+	//reputation and bans
+	rp *reputation
+
+	//bandwidth limits
+	limiters   map[uint16]*bandwidthLimiter
+	limiterMtx sync.RWMutex
+
 	//connection limit
 	cLimit    map[PeerConnectionType]int
 	cLimitMtx sync.RWMutex
@@ -144,6 +151,9 @@ func newPeerToPeer(channel string, self *Peer, d *Dialer, mtr *metric.NetworkMet
 		allowedSeeds: NewPeerIDSet(),
 		allowedPeers: NewPeerIDSet(),
 		//
+		rp:       newReputation(l),
+		limiters: make(map[uint16]*bandwidthLimiter),
+		//
 		cLimit: make(map[PeerConnectionType]int),
 		//
 		mtr: mtr,
@@ -226,6 +236,10 @@ func (p2p *PeerToPeer) supportedProtocols() []module.ProtocolInfo {
 }
 
 func (p2p *PeerToPeer) dial(na NetAddress) error {
+	if b := p2p.rp.isBanned(nil, ipOf(string(na))); b != nil {
+		p2p.logger.Debugln("Dial ignore banned", na)
+		return ErrBannedPeer
+	}
 	if err := p2p.dialer.Dial(string(na)); err != nil {
 		if err == ErrAlreadyDialing {
 			p2p.logger.Infoln("Dial ignore", na, err)
@@ -277,6 +291,11 @@ func (p2p *PeerToPeer) unsetEventCbFunc(k uint16) {
 //callback from PeerDispatcher.onPeer
 func (p2p *PeerToPeer) onPeer(p *Peer) {
 	p2p.logger.Debugln("onPeer", p)
+	if b := p2p.rp.isBanned(p.ID(), p.RemoteIP()); b != nil {
+		p2p.onEvent(p2pEventNotAllowed, p)
+		p.CloseByError(ErrBannedPeer)
+		return
+	}
 	if !p2p.allowedPeers.IsEmpty() && !p2p.allowedPeers.Contains(p.ID()) {
 		p2p.onEvent(p2pEventNotAllowed, p)
 		p.CloseByError(fmt.Errorf("onPeer not allowed connection"))
@@ -296,6 +315,31 @@ func (p2p *PeerToPeer) onClose(p *Peer) {
 
 	p2p.logger.Debugln("onClose", p.CloseInfo(), p)
 	p2p._onClose(p)
+	p2p.rp.onClose(p.ID())
+}
+
+// penalize adds penalty points to the peer, and closes connections of
+// the peer if it's banned. Validators and seeds are not banned, because
+// the network may not work without them. They are only disconnected.
+func (p2p *PeerToPeer) penalize(p *Peer, points int, reason string) {
+	exempt := p2p.allowedRoots.Contains(p.ID()) || p2p.allowedSeeds.Contains(p.ID())
+	over, b := p2p.rp.penalize(p.ID(), points, reason, exempt)
+	if b != nil {
+		p2p.closeBannedPeers(b)
+	} else if over {
+		p.CloseByError(ErrTooManyPenalties)
+	}
+}
+
+func (p2p *PeerToPeer) closeBannedPeers(b *module.PeerBan) {
+	ps := p2p.findPeers(func(p *Peer) bool {
+		return (len(b.ID) > 0 && p.ID().String() == b.ID) ||
+			(len(b.IP) > 0 && p.RemoteIP() == b.IP)
+	})
+	for _, p := range ps {
+		p2p.onEvent(p2pEventNotAllowed, p)
+		p.CloseByError(ErrBannedPeer)
+	}
 }
 
 func (p2p *PeerToPeer) onEvent(evt string, p *Peer) {
@@ -846,6 +890,13 @@ Loop:
 				}
 				pkt := ctx.Value(p2pContextKeyPacket).(*Packet)
 				c := ctx.Value(p2pContextKeyCounter).(*Counter)
+				l := p2p.getBandwidthLimiter(pkt.protocol)
+				if l != nil && !l.allow(time.Now()) {
+					atomic.StoreInt32(&c.fixed, 1)
+					p2p.mtr.OnDrop(pkt.protocol.Uint16(), pkt.lengthOfPayload)
+					p2p.onFailure(ErrBandwidthExceeded, pkt, c)
+					continue
+				}
 				_ = pkt.updateHash(false)
 				r := p2p.Role()
 				switch pkt.dest {
@@ -895,6 +946,9 @@ Loop:
 					}
 				default:
 				}
+				if l != nil {
+					l.consume(int64(c.enqueue) * int64(pkt.lengthOfPayload))
+				}
 
 				if c.alternate < 1 {
 					atomic.StoreInt32(&c.fixed, 1)
@@ -1019,6 +1073,7 @@ func (p2p *PeerToPeer) Send(pkt *Packet) error {
 	ctx = context.WithValue(ctx, p2pContextKeyCounter, &Counter{})
 	if ok := p2p.sendQueue.Push(ctx, int(pkt.protocol.ID())); !ok {
 		p2p.logger.Infoln("Send", "Queue Push failure", pkt.protocol, pkt.subProtocol)
+		p2p.mtr.OnDrop(pkt.protocol.Uint16(), pkt.lengthOfPayload)
 		return ErrQueueOverflow
 	}
 	return nil
@@ -1063,6 +1118,29 @@ func (c *Counter) Close() int {
 	return c.close
 }
 
+// setBandwidthLimit sets the limit of sending bytes per second for the
+// protocol. If limit is not positive, it removes the limit.
+func (p2p *PeerToPeer) setBandwidthLimit(pi module.ProtocolInfo, limit int64) {
+	p2p.limiterMtx.Lock()
+	defer p2p.limiterMtx.Unlock()
+
+	if limit > 0 {
+		if l, ok := p2p.limiters[pi.Uint16()]; ok && l.limit == limit {
+			return
+		}
+		p2p.limiters[pi.Uint16()] = newBandwidthLimiter(limit)
+	} else {
+		delete(p2p.limiters, pi.Uint16())
+	}
+}
+
+func (p2p *PeerToPeer) getBandwidthLimiter(pi module.ProtocolInfo) *bandwidthLimiter {
+	p2p.limiterMtx.RLock()
+	defer p2p.limiterMtx.RUnlock()
+
+	return p2p.limiters[pi.Uint16()]
+}
+
 func (p2p *PeerToPeer) getPeerByProtocol(id module.PeerID, pi module.ProtocolInfo) (p *Peer) {
 	return p2p.findPeer(func(p *Peer) bool {
 		return p.ID().Equal(id) && p.ProtocolInfos().Exists(pi)
@@ -1,9 +1,12 @@
 package network
 
 import (
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
 )
 
 func Test_PeerToPeer_resolveConnection(t *testing.T) {
@@ -166,3 +169,42 @@ func Test_PeerToPeer_resolveConnection(t *testing.T) {
 		assert.Equal(t, arg.expected.invalidResp, invalidResp)
 	}
 }
+
+func Test_PeerToPeer_penalize(t *testing.T) {
+	p2p := &PeerToPeer{
+		peerHandler:  newPeerHandler(generatePeerID(), log.GlobalLogger()),
+		m:            make(map[PeerConnectionType]*PeerSet),
+		allowedRoots: NewPeerIDSet(),
+		allowedSeeds: NewPeerIDSet(),
+		rp:           newReputation(log.GlobalLogger()),
+	}
+	newTestPeer := func() *Peer {
+		conn, _ := net.Pipe()
+		p := newPeer(conn, true, "", log.GlobalLogger())
+		p.setID(generatePeerID())
+		return p
+	}
+	penalize := func(p *Peer) {
+		for i := 0; i < DefaultBanThreshold/DefaultPenaltyForAbuse; i++ {
+			p2p.penalize(p, DefaultPenaltyForAbuse, "invalid")
+		}
+	}
+
+	validator := newTestPeer()
+	p2p.allowedRoots.Add(validator.ID())
+	seed := newTestPeer()
+	p2p.allowedSeeds.Add(seed.ID())
+	for _, p := range []*Peer{validator, seed} {
+		penalize(p)
+		assert.True(t, p.IsClosed())
+		assert.Nil(t, p2p.rp.isBanned(p.ID(), ""))
+	}
+
+	other := newTestPeer()
+	penalize(other)
+	b := p2p.rp.isBanned(other.ID(), "")
+	if assert.NotNil(t, b) {
+		assert.Equal(t, other.ID().String(), b.ID)
+		assert.Empty(t, b.IP)
+	}
+}
@@ -57,6 +57,7 @@ type Peer struct {
 	pisMtx        sync.RWMutex
 	attr          map[string]interface{}
 	attrMtx       sync.RWMutex
+	traffic       metric.TrafficCounter
 
 	//
 	secureKey *secureKey
@@ -117,6 +118,14 @@ func (p *Peer) ConnString() string {
 	}
 }
 
+// RemoteIP returns IP of the remote side of the connection.
+func (p *Peer) RemoteIP() string {
+	if p == nil || p.conn == nil {
+		return ""
+	}
+	return ipOf(p.conn.RemoteAddr().String())
+}
+
 func (p *Peer) In() bool {
 	return p.in
 }
@@ -391,6 +400,7 @@ func (p *Peer) receiveRoutine() {
 		pkt.sender = p.ID()
 		p.pool.Put(pkt.hashOfPacket)
 		p.getMetric().OnRecv(pkt.dest, pkt.ttl, pkt.extendInfo.hint(), pkt.protocol.Uint16(), pkt.lengthOfPayload)
+		p.onTraffic(pkt, false)
 		if cbFunc := p.getPacketCbFunc(); cbFunc != nil {
 			cbFunc(pkt, p)
 		} else {
@@ -435,6 +445,7 @@ Loop:
 				}
 				p.pool.Put(pkt.hashOfPacket)
 				p.getMetric().OnSend(pkt.dest, pkt.ttl, pkt.extendInfo.hint(), pkt.protocol.Uint16(), pkt.lengthOfPayload)
+				p.onTraffic(pkt, true)
 			}
 		case <-secondTick.C:
 			p.pool.RemoveBefore(DefaultPeerPoolExpireSecond)
@@ -470,6 +481,8 @@ func (p *Peer) send(ctx context.Context) error {
 	}
 	if ok := p.q.Push(ctx, int(pkt.priority)); !ok {
 		c.overflow++
+		p.traffic.OnDrop(pkt.lengthOfPayload)
+		p.getMetric().OnDrop(pkt.protocol.Uint16(), pkt.lengthOfPayload)
 		return ErrQueueOverflow
 	}
 	c.enqueue++
@@ -485,6 +498,21 @@ func (p *Peer) sendPacket(pkt *Packet) error {
 	return p.send(ctx)
 }
 
+// onTraffic counts traffic of the peer. It's not exported as metrics,
+// because series for each peer would grow without bound.
+func (p *Peer) onTraffic(pkt *Packet, send bool) {
+	if send {
+		p.traffic.OnSend(pkt.lengthOfPayload)
+	} else {
+		p.traffic.OnRecv(pkt.lengthOfPayload)
+	}
+}
+
+// Traffic returns traffic statistics of the peer.
+func (p *Peer) Traffic() metric.TrafficStat {
+	return p.traffic.Stat()
+}
+
 func (p *Peer) setMetric(nm *metric.NetworkMetric) {
 	p.metricMtx.Lock()
 	defer p.metricMtx.Unlock()
@@ -25,7 +25,8 @@ type protocolHandler struct {
 	run chan bool
 	mtx sync.RWMutex
 
-	currentPkt *Packet
+	currentPkt  *Packet
+	currentPeer *Peer
 }
 
 func newProtocolHandler(
@@ -127,7 +128,7 @@ func (ph *protocolHandler) getSubProtocols() []module.ProtocolInfo {
 
 var ErrInProgress = errors.NewBase(errors.UnknownError, "InProgressError")
 
-func (ph *protocolHandler) onPacketResult(pkt *Packet, isRelay bool, err error) {
+func (ph *protocolHandler) onPacketResult(pkt *Packet, p *Peer, isRelay bool, err error) {
 	if isRelay && pkt.ttl == byte(module.BroadcastAll) && pkt.dest != p2pDestPeer {
 		if err := ph.m.send(pkt); err != nil {
 			ph.logger.Tracef("fail to relay error:{%+v} pkt=%s", err, pkt)
@@ -135,6 +136,9 @@ func (ph *protocolHandler) onPacketResult(pkt *Packet, isRelay bool, err error)
 	}
 	if err != nil {
 		ph.logger.Tracef("OnReceive returns err=%+v", err)
+		if points := penaltyOf(err); points > 0 {
+			ph.m.p2p.penalize(p, points, ph.name+": "+err.Error())
+		}
 	}
 }
 
@@ -142,11 +146,11 @@ func (ph *protocolHandler) HandleInBackground() (module.OnResult, error) {
 	if ph.currentPkt == nil {
 		return nil, errors.InvalidStateError.New("NotOnReceive()")
 	}
-	pkt := ph.currentPkt
-	ph.currentPkt = nil
+	pkt, p := ph.currentPkt, ph.currentPeer
+	ph.currentPkt, ph.currentPeer = nil, nil
 
 	return func(isRelay bool, err error) {
-		ph.onPacketResult(pkt, isRelay, err)
+		ph.onPacketResult(pkt, p, isRelay, err)
 	}, ErrInProgress
 }
 
@@ -165,11 +169,11 @@ Loop:
 				pkt := ctx.Value(p2pContextKeyPacket).(*Packet)
 				p := ctx.Value(p2pContextKeyPeer).(*Peer)
 				r := ph.getReactor()
-				ph.currentPkt = pkt
+				ph.currentPkt, ph.currentPeer = pkt, p
 				isRelay, err := r.OnReceive(pkt.subProtocol, pkt.payload, p.ID())
 				if err != ErrInProgress || ph.currentPkt != nil {
-					ph.currentPkt = nil
-					ph.onPacketResult(pkt, isRelay, err)
+					ph.currentPkt, ph.currentPeer = nil, nil
+					ph.onPacketResult(pkt, p, isRelay, err)
 				}
 			}
 		}
@@ -0,0 +1,319 @@
+package network
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	DefaultPeerBanDuration  = time.Hour
+	DefaultBanThreshold     = 100
+	DefaultPenaltyForAbuse  = 10
+	DefaultPenaltyDecayTime = time.Second
+)
+
+const (
+	keyPeerBans = "network.peer_bans"
+)
+
+// penaltyOf returns penalty points for the error returned by the reactor.
+// Only errors for malformed data or data of other networks make penalty.
+// Other errors (e.g. unknown errors, errors caused by the local state or
+// duplicate transactions) don't make penalty, because honest peers may
+// cause them.
+func penaltyOf(err error) int {
+	if err == nil {
+		return 0
+	}
+	switch errors.CodeOf(err) {
+	case errors.InvalidNetworkError, errors.CriticalFormatError,
+		errors.CriticalHashError:
+		return DefaultPenaltyForAbuse
+	default:
+		return 0
+	}
+}
+
+func ipOf(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+type peerScore struct {
+	points int
+	last   time.Time
+}
+
+// decay reduces a point for each unit of elapsed time since the last
+// update.
+func (s *peerScore) decay(now time.Time, unit time.Duration) {
+	if d := int(now.Sub(s.last) / unit); d > 0 {
+		if s.points > d {
+			s.points -= d
+		} else {
+			s.points = 0
+		}
+		s.last = s.last.Add(time.Duration(d) * unit)
+	}
+}
+
+type reputation struct {
+	mtx       sync.Mutex
+	bk        db.Bucket
+	duration  time.Duration
+	threshold int
+	decayTime time.Duration
+	scores    map[string]*peerScore
+	bans      []*module.PeerBan
+	logger    log.Logger
+}
+
+func newReputation(l log.Logger) *reputation {
+	return &reputation{
+		duration:  DefaultPeerBanDuration,
+		threshold: DefaultBanThreshold,
+		decayTime: DefaultPenaltyDecayTime,
+		scores:    make(map[string]*peerScore),
+		logger:    l,
+	}
+}
+
+// load loads bans from the database, and bans are stored to the database
+// on changes.
+func (r *reputation) load(dbase db.Database) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	bs, err := bk.Get([]byte(keyPeerBans))
+	if err != nil {
+		return err
+	}
+	var bans []*module.PeerBan
+	if len(bs) > 0 {
+		if _, err := codec.BC.UnmarshalFromBytes(bs, &bans); err != nil {
+			return err
+		}
+	}
+	r.bk = bk
+	r.bans = bans
+	r._purge(time.Now())
+	return nil
+}
+
+func (r *reputation) setDuration(d time.Duration) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if d > 0 {
+		r.duration = d
+	} else {
+		r.duration = DefaultPeerBanDuration
+	}
+}
+
+func (r *reputation) setThreshold(points int) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if points > 0 {
+		r.threshold = points
+	} else {
+		r.threshold = DefaultBanThreshold
+	}
+}
+
+func (r *reputation) setDecayTime(d time.Duration) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if d > 0 {
+		r.decayTime = d
+	} else {
+		r.decayTime = DefaultPenaltyDecayTime
+	}
+}
+
+func (r *reputation) _flush() {
+	if r.bk == nil {
+		return
+	}
+	bs, err := codec.BC.MarshalToBytes(r.bans)
+	if err != nil {
+		r.logger.Warnf("fail to encode peer bans err=%+v", err)
+		return
+	}
+	if err := r.bk.Set([]byte(keyPeerBans), bs); err != nil {
+		r.logger.Warnf("fail to store peer bans err=%+v", err)
+	}
+}
+
+// _purge removes expired bans and returns whether it removes any.
+func (r *reputation) _purge(now time.Time) bool {
+	bans := r.bans[:0]
+	for _, b := range r.bans {
+		if b.Until > now.Unix() {
+			bans = append(bans, b)
+		}
+	}
+	purged := len(bans) != len(r.bans)
+	for i := len(bans); i < len(r.bans); i++ {
+		r.bans[i] = nil
+	}
+	r.bans = bans
+	return purged
+}
+
+func (r *reputation) _find(id, ip string) *module.PeerBan {
+	for _, b := range r.bans {
+		if (len(id) > 0 && b.ID == id) || (len(ip) > 0 && b.IP == ip) {
+			return b
+		}
+	}
+	return nil
+}
+
+func (r *reputation) _ban(id, ip string, d time.Duration, reason string, now time.Time) *module.PeerBan {
+	if d <= 0 {
+		d = r.duration
+	}
+	b := &module.PeerBan{
+		ID:     id,
+		IP:     ip,
+		Until:  now.Add(d).Unix(),
+		Reason: reason,
+	}
+	r.bans = append(r.bans, b)
+	if len(id) > 0 {
+		delete(r.scores, id)
+	}
+	r._flush()
+	r.logger.Infof("ban peer id=%s ip=%s until=%s reason=%s",
+		id, ip, time.Unix(b.Until, 0).Format(time.RFC3339), reason)
+	return b
+}
+
+func idOf(id module.PeerID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+// isBanned returns the ban for the id or the ip if there is.
+func (r *reputation) isBanned(id module.PeerID, ip string) *module.PeerBan {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r._purge(time.Now()) {
+		r._flush()
+	}
+	return r._find(idOf(id), ip)
+}
+
+// penalize adds points to the score of the peer, and returns whether the
+// score reaches the threshold. Then it bans the peer by the ID and returns
+// the ban, or only resets the score if the peer is exempted from bans.
+// Bans by the IP are made only by the administrator, because honest peers
+// may share the IP with the peer.
+func (r *reputation) penalize(id module.PeerID, points int, reason string, exempt bool) (bool, *module.PeerBan) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	now := time.Now()
+	key := idOf(id)
+	s, ok := r.scores[key]
+	if !ok {
+		s = &peerScore{last: now}
+		r.scores[key] = s
+	} else {
+		s.decay(now, r.decayTime)
+	}
+	s.points += points
+	if s.points < r.threshold {
+		return false, nil
+	}
+	if exempt {
+		delete(r.scores, key)
+		r.logger.Infof("peer id=%s is exempted from bans reason=%s", key, reason)
+		return true, nil
+	}
+	return true, r._ban(key, "", 0, reason, now)
+}
+
+func (r *reputation) ban(id module.PeerID, ip string, d time.Duration, reason string) (*module.PeerBan, error) {
+	if id == nil && len(ip) == 0 {
+		return nil, errors.IllegalArgumentError.New("NoPeerIDOrIP")
+	}
+	if len(ip) > 0 && net.ParseIP(ip) == nil {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidIP(ip=%s)", ip)
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return r._ban(idOf(id), ip, d, reason, time.Now()), nil
+}
+
+func (r *reputation) unban(id module.PeerID, ip string) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	key := idOf(id)
+	bans := r.bans[:0]
+	for _, b := range r.bans {
+		if (len(key) > 0 && b.ID == key) || (len(ip) > 0 && b.IP == ip) {
+			r.logger.Infof("unban peer id=%s ip=%s", b.ID, b.IP)
+			continue
+		}
+		bans = append(bans, b)
+	}
+	if len(bans) == len(r.bans) {
+		return false
+	}
+	for i := len(bans); i < len(r.bans); i++ {
+		r.bans[i] = nil
+	}
+	r.bans = bans
+	r._flush()
+	return true
+}
+
+func (r *reputation) list() []module.PeerBan {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r._purge(time.Now()) {
+		r._flush()
+	}
+	res := make([]module.PeerBan, len(r.bans))
+	for i, b := range r.bans {
+		res[i] = *b
+	}
+	return res
+}
+
+func (r *reputation) onClose(id module.PeerID) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	// keep the score while it's not decayed, so that reconnecting doesn't
+	// reset the score.
+	if s, ok := r.scores[idOf(id)]; ok {
+		s.decay(time.Now(), r.decayTime)
+		if s.points == 0 {
+			delete(r.scores, idOf(id))
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+func Test_reputation_penaltyOf(t *testing.T) {
+	assert.Equal(t, 0, penaltyOf(nil))
+	assert.Equal(t, 0, penaltyOf(errors.New("unknown")))
+	assert.Equal(t, 0, penaltyOf(errors.IllegalArgumentError.New("invalid")))
+	assert.Equal(t, DefaultPenaltyForAbuse, penaltyOf(errors.CriticalFormatError.New("invalid")))
+	assert.Equal(t, DefaultPenaltyForAbuse, penaltyOf(errors.CriticalHashError.New("invalid")))
+	assert.Equal(t, DefaultPenaltyForAbuse, penaltyOf(errors.InvalidNetworkError.New("invalid")))
+	assert.Equal(t, 0, penaltyOf(errors.InvalidStateError.New("local")))
+	assert.Equal(t, 0, penaltyOf(ErrDuplicatedPacket))
+}
+
+func Test_reputation_penalize(t *testing.T) {
+	r := newReputation(log.GlobalLogger())
+	id := generatePeerID()
+
+	for i := 0; i < DefaultBanThreshold/DefaultPenaltyForAbuse-1; i++ {
+		over, b := r.penalize(id, DefaultPenaltyForAbuse, "invalid", false)
+		assert.False(t, over)
+		assert.Nil(t, b)
+	}
+	over, b := r.penalize(id, DefaultPenaltyForAbuse, "invalid", false)
+	assert.True(t, over)
+	assert.NotNil(t, b)
+	assert.Equal(t, id.String(), b.ID)
+	assert.Empty(t, b.IP)
+
+	assert.NotNil(t, r.isBanned(id, ""))
+	assert.NotNil(t, r.isBanned(id, "10.0.0.1"))
+	assert.Nil(t, r.isBanned(generatePeerID(), "10.0.0.1"))
+
+	assert.True(t, r.unban(id, ""))
+	assert.False(t, r.unban(id, ""))
+	assert.Nil(t, r.isBanned(id, "10.0.0.1"))
+}
+
+func Test_reputation_penalizeExempted(t *testing.T) {
+	r := newReputation(log.GlobalLogger())
+	id := generatePeerID()
+
+	for i := 0; i < DefaultBanThreshold/DefaultPenaltyForAbuse-1; i++ {
+		over, b := r.penalize(id, DefaultPenaltyForAbuse, "invalid", true)
+		assert.False(t, over)
+		assert.Nil(t, b)
+	}
+	over, b := r.penalize(id, DefaultPenaltyForAbuse, "invalid", true)
+	assert.True(t, over)
+	assert.Nil(t, b)
+	assert.Nil(t, r.isBanned(id, ""))
+	assert.Empty(t, r.list())
+
+	// the score is reset
+	over, _ = r.penalize(id, DefaultPenaltyForAbuse, "invalid", true)
+	assert.False(t, over)
+}
+
+func Test_reputation_decay(t *testing.T) {
+	s := &peerScore{points: 10, last: time.Now().Add(-3 * DefaultPenaltyDecayTime)}
+	s.decay(time.Now(), DefaultPenaltyDecayTime)
+	assert.Equal(t, 7, s.points)
+
+	s.decay(time.Now().Add(time.Hour), DefaultPenaltyDecayTime)
+	assert.Equal(t, 0, s.points)
+}
+
+func Test_reputation_config(t *testing.T) {
+	r := newReputation(log.GlobalLogger())
+	r.setThreshold(20)
+	r.setDecayTime(time.Hour)
+	id := generatePeerID()
+
+	_, b := r.penalize(id, DefaultPenaltyForAbuse, "invalid", false)
+	assert.Nil(t, b)
+	r.scores[id.String()].last = time.Now().Add(-time.Minute)
+	_, b = r.penalize(id, DefaultPenaltyForAbuse, "invalid", false)
+	assert.NotNil(t, b)
+
+	r.setThreshold(0)
+	r.setDecayTime(0)
+	assert.Equal(t, DefaultBanThreshold, r.threshold)
+	assert.Equal(t, DefaultPenaltyDecayTime, r.decayTime)
+}
+
+func Test_reputation_persist(t *testing.T) {
+	dbase := db.NewMapDB()
+	r := newReputation(log.GlobalLogger())
+	assert.NoError(t, r.load(dbase))
+
+	id := generatePeerID()
+	_, err := r.ban(id, "", time.Hour, "manual")
+	assert.NoError(t, err)
+	_, err = r.ban(nil, "10.0.0.1", time.Hour, "manual")
+	assert.NoError(t, err)
+	_, err = r.ban(nil, "invalid", time.Hour, "manual")
+	assert.Error(t, err)
+	_, err = r.ban(nil, "", time.Hour, "manual")
+	assert.Error(t, err)
+
+	r2 := newReputation(log.GlobalLogger())
+	assert.NoError(t, r2.load(dbase))
+	bans := r2.list()
+	assert.Len(t, bans, 2)
+	assert.Equal(t, id.String(), bans[0].ID)
+	assert.Equal(t, "manual", bans[0].Reason)
+	assert.Equal(t, "10.0.0.1", bans[1].IP)
+
+	// expired bans are removed
+	r2.mtx.Lock()
+	r2.bans[0].Until = time.Now().Unix() - 1
+	r2.mtx.Unlock()
+	assert.Nil(t, r2.isBanned(id, ""))
+
+	r3 := newReputation(log.GlobalLogger())
+	assert.NoError(t, r3.load(dbase))
+	assert.Len(t, r3.list(), 1)
+}
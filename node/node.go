@@ -14,6 +14,7 @@ import (
 
 	"github.com/icon-project/goloop/chain"
 	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
@@ -413,6 +414,8 @@ func (n *Node) JoinChain(
 		ChildrenLimit:    p.ChildrenLimit,
 		NephewsLimit:     p.NephewsLimit,
 		ValidateTxOnSend: p.ValidateTxOnSend,
+		BLSKey:           p.BLSKey,
+		BLSKeySecret:     p.BLSKeySecret,
 	}
 
 	if err := cfg.Save(); err != nil {
@@ -505,6 +508,85 @@ func (n *Node) VerifyChain(cid int) error {
 	return c.Verify()
 }
 
+func (n *Node) _networkManagerOf(cid int) (module.NetworkManager, error) {
+	c, err := n._get(cid)
+	if err != nil {
+		return nil, err
+	}
+	nm := c.NetworkManager()
+	if nm == nil {
+		return nil, errors.InvalidStateError.Errorf("NoNetworkManager(cid=%#x)", cid)
+	}
+	return nm, nil
+}
+
+func peerIDFromString(s string) (module.PeerID, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	addr, err := common.NewAddressFromString(s)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidPeerID(id=%s)", s)
+	}
+	return network.NewPeerIDFromAddress(addr), nil
+}
+
+func (n *Node) GetChainBans(cid int) ([]module.PeerBan, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	nm, err := n._networkManagerOf(cid)
+	if err != nil {
+		return nil, err
+	}
+	return nm.Bans(), nil
+}
+
+func (n *Node) GetChainTraffic(cid int) (*network.TrafficView, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	c, err := n._get(cid)
+	if err != nil {
+		return nil, err
+	}
+	v := network.Traffic(c)
+	if v == nil {
+		return nil, errors.InvalidStateError.Errorf("NoNetworkManager(cid=%#x)", cid)
+	}
+	return v, nil
+}
+
+func (n *Node) BanPeer(cid int, id, ip string, d time.Duration, reason string) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	nm, err := n._networkManagerOf(cid)
+	if err != nil {
+		return err
+	}
+	pid, err := peerIDFromString(id)
+	if err != nil {
+		return err
+	}
+	return nm.Ban(pid, ip, d, reason)
+}
+
+func (n *Node) UnbanPeer(cid int, id, ip string) (bool, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	nm, err := n._networkManagerOf(cid)
+	if err != nil {
+		return false, err
+	}
+	pid, err := peerIDFromString(id)
+	if err != nil {
+		return false, err
+	}
+	return nm.Unban(pid, ip), nil
+}
+
 func (n *Node) ImportChain(cid int, s string, height int64) error {
 	defer n.mtx.RUnlock()
 	n.mtx.RLock()
@@ -660,6 +742,11 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			}
 			pr := network.PeerRoleFlag(c.cfg.Role)
 			c.NetworkManager().SetInitialRoles(pr.ToRoles()...)
+		case "bandwidthLimits":
+			if err := c.NetworkManager().SetBandwidthLimits(value); err != nil {
+				return err
+			}
+			c.cfg.BandwidthLimits = value
 		case "autoStart":
 			if as, err := strconv.ParseBool(value); err != nil {
 				return err
@@ -720,6 +807,29 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			} else {
 				c.cfg.TxTimeout = intVal
 			}
+		case "banDuration":
+			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.BanDuration = intVal
+			}
+		case "banThreshold":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.BanThreshold = intVal
+			}
+		case "penaltyDecay":
+			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.PenaltyDecay = intVal
+			}
+		case "bandwidthLimits":
+			if _, err := network.ParseBandwidthLimits(value); err != nil {
+				return err
+			}
+			c.cfg.BandwidthLimits = value
 		case "channel":
 			if err := n._canAdd(c.CID(), c.NID(), value, true); err != nil {
 				return err
@@ -99,10 +99,16 @@ type ChainConfig struct {
 	DefWaitTimeout   int64  `json:"defaultWaitTimeout"`
 	MaxWaitTimeout   int64  `json:"maxWaitTimeout"`
 	TxTimeout        int64  `json:"txTimeout"`
+	BanDuration      int64  `json:"banDuration,omitempty"`
+	BanThreshold     int    `json:"banThreshold,omitempty"`
+	PenaltyDecay     int64  `json:"penaltyDecay,omitempty"`
+	BandwidthLimits  string `json:"bandwidthLimits,omitempty"`
 	AutoStart        bool   `json:"autoStart"`
 	ChildrenLimit    *int   `json:"childrenLimit,omitempty"`
 	NephewsLimit     *int   `json:"nephewsLimit,omitempty"`
 	ValidateTxOnSend bool   `json:"validateTxOnSend,omitempty"`
+	BLSKey           string `json:"blsKey,omitempty"`
+	BLSKeySecret     string `json:"blsKeySecret,omitempty"`
 }
 
 type ChainResetParam struct {
@@ -124,6 +130,28 @@ type ChainBackupParam struct {
 	Manual bool `json:"manual,omitempty"`
 }
 
+type ChainSnapshotParam struct {
+	Height int64  `json:"height"`
+	File   string `json:"file"`
+}
+
+type ChainImportSnapshotParam struct {
+	File      string          `json:"file"`
+	BlockHash common.HexBytes `json:"blockHash"`
+}
+
+type ChainBanParam struct {
+	ID       string `json:"id,omitempty"`
+	IP       string `json:"ip,omitempty"`
+	Duration int64  `json:"duration,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type ChainUnbanParam struct {
+	ID string `json:"id,omitempty"`
+	IP string `json:"ip,omitempty"`
+}
+
 type ConfigureParam struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
@@ -181,10 +209,16 @@ func NewChainConfig(cfg *chain.Config) *ChainConfig {
 		DefWaitTimeout:   cfg.DefWaitTimeout,
 		MaxWaitTimeout:   cfg.MaxWaitTimeout,
 		TxTimeout:        cfg.TxTimeout,
+		BanDuration:      cfg.BanDuration,
+		BanThreshold:     cfg.BanThreshold,
+		PenaltyDecay:     cfg.PenaltyDecay,
+		BandwidthLimits:  cfg.BandwidthLimits,
 		AutoStart:        cfg.AutoStart,
 		ChildrenLimit:    cfg.ChildrenLimit,
 		NephewsLimit:     cfg.NephewsLimit,
 		ValidateTxOnSend: cfg.ValidateTxOnSend,
+		BLSKey:           cfg.BLSKey,
+		BLSKeySecret:     cfg.BLSKeySecret,
 	}
 	return v
 }
@@ -243,6 +277,10 @@ func (r *Rest) RegisterChainHandlers(g *echo.Group) {
 	}
 	g.GET(UrlChainRes+"/configure", r.GetChainConfig, r.ChainInjector)
 	g.POST(UrlChainRes+"/configure", r.ConfigureChain, r.ChainInjector)
+	g.GET(UrlChainRes+"/traffic", r.GetChainTraffic, r.ChainInjector)
+	g.GET(UrlChainRes+"/bans", r.GetChainBans, r.ChainInjector)
+	g.POST(UrlChainRes+"/ban", r.BanPeer, r.ChainInjector)
+	g.POST(UrlChainRes+"/unban", r.UnbanPeer, r.ChainInjector)
 	g.POST(UrlChainRes+"/:"+TaskID, r.RunChainTask, r.ChainInjector)
 }
 
@@ -451,6 +489,57 @@ func (r *Rest) ConfigureChain(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, "OK")
 }
 
+func (r *Rest) GetChainTraffic(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	v, err := r.n.GetChainTraffic(c.CID())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, v)
+}
+
+func (r *Rest) GetChainBans(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	bans, err := r.n.GetChainBans(c.CID())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, bans)
+}
+
+func (r *Rest) BanPeer(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	param := &ChainBanParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	if len(param.ID) == 0 && len(param.IP) == 0 {
+		return echo.ErrBadRequest
+	}
+	d := time.Duration(param.Duration) * time.Millisecond
+	if err := r.n.BanPeer(c.CID(), param.ID, param.IP, d, param.Reason); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) UnbanPeer(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	param := &ChainUnbanParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	if len(param.ID) == 0 && len(param.IP) == 0 {
+		return echo.ErrBadRequest
+	}
+	if ok, err := r.n.UnbanPeer(c.CID(), param.ID, param.IP); err != nil {
+		return err
+	} else if !ok {
+		return ctx.String(http.StatusNotFound, "NoMatchingBan")
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
 func (r *Rest) RunChainTask(ctx echo.Context) error {
 	c := ctx.Get("chain").(*Chain)
 	task := ctx.Param(TaskID)
@@ -22,10 +22,12 @@ import (
 	"io"
 	"os"
 	"path"
+	"strings"
 	"sync"
 
 	"github.com/icon-project/goloop/chain"
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 )
 
@@ -125,6 +127,12 @@ func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrit
 			info.Codec, codec.BC.Name())
 	}
 
+	if info.DBType != "" && !db.IsRegisteredBackendType(info.DBType) {
+		return errors.IllegalArgumentError.Errorf(
+			"UnsupportedDBType(backup=%s,system=%s)",
+			info.DBType, strings.Join(db.RegisteredBackendTypes(), ","))
+	}
+
 	if err := node.CanAdd(int(info.CID.Value), int(info.NID.Value), info.Channel, overwrite); err != nil {
 		return err
 	}
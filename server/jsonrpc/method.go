@@ -132,6 +132,12 @@ func (mr *MethodRepository) handle(ctx *Context, raw json.RawMessage) *Response
 	return resp
 }
 
+// HandleMessage handles a single request message, and returns the response.
+// It returns nil for the notification request.
+func (mr *MethodRepository) HandleMessage(ctx *Context, raw json.RawMessage) *Response {
+	return mr.handle(ctx, raw)
+}
+
 func (mr *MethodRepository) Handle(c echo.Context) error {
 	ctx := NewContext(c)
 	raw := c.Get("raw").(json.RawMessage)
@@ -57,24 +57,48 @@ var (
 		"icx_getVotesByHeight":       msRetrieve,
 		"icx_getProofForResult":      msRetrieve,
 		"icx_getProofForEvents":      msRetrieve,
+		"icx_getProofForState":       msRetrieve,
 		"icx_getScoreStatus":         msRetrieve,
 		"icx_getNetworkInfo":         msRetrieve,
-		"btp_getNetworkInfo":         msRetrieve,
-		"btp_getNetworkTypeInfo":     msRetrieve,
-		"btp_getMessages":            msRetrieve,
-		"btp_getHeader":              msRetrieve,
-		"btp_getProof":               msRetrieve,
-		"btp_getSourceInformation":   msRetrieve,
+		"icx_getLogs": {
+			stats.Int64("jsonrpc_get_logs", "jsonrpc icx_getLogs method", "ns"),
+			stats.Int64("jsonrpc_get_logs_avg", "moving average of jsonrpc icx_getLogs method", "ns"),
+			emptyMks,
+		},
+		"btp_getNetworkInfo":       msRetrieve,
+		"btp_getNetworkTypeInfo":   msRetrieve,
+		"btp_getMessages":          msRetrieve,
+		"btp_getHeader":            msRetrieve,
+		"btp_getProof":             msRetrieve,
+		"btp_getSourceInformation": msRetrieve,
 		"debug_getTrace": {
 			stats.Int64("jsonrpc_get_trace", "jsonrpc debug_getTrace method", "ns"),
 			stats.Int64("jsonrpc_get_trace_avg", "moving average of jsonrpc debug_getTrace method", "ns"),
 			emptyMks,
 		},
+		"debug_traceBlock": {
+			stats.Int64("jsonrpc_trace_block", "jsonrpc debug_traceBlock method", "ns"),
+			stats.Int64("jsonrpc_trace_block_avg", "moving average of jsonrpc debug_traceBlock method", "ns"),
+			emptyMks,
+		},
 		"debug_estimateStep": {
 			stats.Int64("jsonrpc_estimate_step", "jsonrpc debug_estimateStep method", "ns"),
 			stats.Int64("jsonrpc_estimate_step_avg", "moving average of jsonrpc debug_estimateStep method", "ns"),
 			emptyMks,
 		},
+		"debug_call": {
+			stats.Int64("jsonrpc_debug_call", "jsonrpc debug_call method", "ns"),
+			stats.Int64("jsonrpc_debug_call_avg", "moving average of jsonrpc debug_call method", "ns"),
+			emptyMks,
+		},
+		"debug_simulateTransactions": {
+			stats.Int64("jsonrpc_simulate_transactions", "jsonrpc debug_simulateTransactions method", "ns"),
+			stats.Int64("jsonrpc_simulate_transactions_avg", "moving average of jsonrpc debug_simulateTransactions method", "ns"),
+			emptyMks,
+		},
+		"debug_getTxPool":             msRetrieve,
+		"debug_getRoundState":         msRetrieve,
+		"debug_getDoubleSignEvidence": msRetrieve,
 		"rosetta_getTrace": {
 			stats.Int64("jsonrpc_rosetta_trace_", "jsonrpc rosetta_getTrace method", "ns"),
 			stats.Int64("jsonrpc_rosetta_trace_avg", "moving average of jsonrpc rosetta_getTTrace method", "ns"),
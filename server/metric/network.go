@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
@@ -13,9 +14,11 @@ import (
 var (
 	msSend     = stats.Int64("network_send", "send", stats.UnitBytes)
 	msRecv     = stats.Int64("network_recv", "recv", stats.UnitBytes)
+	msDrop     = stats.Int64("network_drop", "drop", stats.UnitBytes)
 	mkDest     = NewMetricKey("dest")
 	mkProtocol = NewMetricKey("protocol")
 	networkMks = []tag.Key{mkDest, mkProtocol}
+	dropMks    = []tag.Key{mkProtocol}
 )
 
 func RegisterNetwork() {
@@ -23,12 +26,59 @@ func RegisterNetwork() {
 	RegisterMetricView(msSend, view.Sum(), networkMks)
 	RegisterMetricView(msRecv, view.Count(), networkMks)
 	RegisterMetricView(msRecv, view.Sum(), networkMks)
+	RegisterMetricView(msDrop, view.Count(), dropMks)
+	RegisterMetricView(msDrop, view.Sum(), dropMks)
+}
+
+// TrafficStat is a snapshot of TrafficCounter.
+type TrafficStat struct {
+	SendBytes   int64 `json:"sendBytes"`
+	SendPackets int64 `json:"sendPackets"`
+	RecvBytes   int64 `json:"recvBytes"`
+	RecvPackets int64 `json:"recvPackets"`
+	DropBytes   int64 `json:"dropBytes"`
+	DropPackets int64 `json:"dropPackets"`
+}
+
+// TrafficCounter counts bytes and packets for both directions, and
+// packets dropped by send queues. It's safe for concurrent use.
+type TrafficCounter struct {
+	stat TrafficStat
+}
+
+func (c *TrafficCounter) OnSend(n uint32) {
+	atomic.AddInt64(&c.stat.SendBytes, int64(n))
+	atomic.AddInt64(&c.stat.SendPackets, 1)
+}
+
+func (c *TrafficCounter) OnRecv(n uint32) {
+	atomic.AddInt64(&c.stat.RecvBytes, int64(n))
+	atomic.AddInt64(&c.stat.RecvPackets, 1)
+}
+
+func (c *TrafficCounter) OnDrop(n uint32) {
+	atomic.AddInt64(&c.stat.DropBytes, int64(n))
+	atomic.AddInt64(&c.stat.DropPackets, 1)
+}
+
+func (c *TrafficCounter) Stat() TrafficStat {
+	return TrafficStat{
+		SendBytes:   atomic.LoadInt64(&c.stat.SendBytes),
+		SendPackets: atomic.LoadInt64(&c.stat.SendPackets),
+		RecvBytes:   atomic.LoadInt64(&c.stat.RecvBytes),
+		RecvPackets: atomic.LoadInt64(&c.stat.RecvPackets),
+		DropBytes:   atomic.LoadInt64(&c.stat.DropBytes),
+		DropPackets: atomic.LoadInt64(&c.stat.DropPackets),
+	}
 }
 
 type NetworkMetric struct {
 	ctx    context.Context
 	ctxMap map[string]context.Context
 	ctxMtx sync.RWMutex
+
+	protocols   map[uint16]*TrafficCounter
+	protocolMtx sync.RWMutex
 }
 
 func (m *NetworkMetric) get(key string) (context.Context, bool) {
@@ -59,19 +109,68 @@ func (m *NetworkMetric) getMetricContext(dest byte, ttl byte, hint byte, protoco
 	return ctx
 }
 
+func (m *NetworkMetric) getContextWith(mk *tag.Key, prefix string, v string) context.Context {
+	key := prefix + v
+	ctx, ok := m.get(key)
+	if !ok {
+		ctx = GetMetricContext(m.ctx, mk, v)
+		m.put(key, ctx)
+	}
+	return ctx
+}
+
+func (m *NetworkMetric) counterOf(protocol uint16) *TrafficCounter {
+	m.protocolMtx.RLock()
+	c, ok := m.protocols[protocol]
+	m.protocolMtx.RUnlock()
+	if ok {
+		return c
+	}
+
+	m.protocolMtx.Lock()
+	defer m.protocolMtx.Unlock()
+	if c, ok = m.protocols[protocol]; !ok {
+		c = new(TrafficCounter)
+		m.protocols[protocol] = c
+	}
+	return c
+}
+
 func (m *NetworkMetric) OnSend(dest byte, ttl byte, hint byte, protocol uint16, pktLen uint32) {
 	ctx := m.getMetricContext(dest, ttl, hint, protocol)
 	stats.Record(ctx, msSend.M(int64(pktLen)))
+	m.counterOf(protocol).OnSend(pktLen)
 }
 
 func (m *NetworkMetric) OnRecv(dest byte, ttl byte, hint byte, protocol uint16, pktLen uint32) {
 	ctx := m.getMetricContext(dest, ttl, hint, protocol)
 	stats.Record(ctx, msRecv.M(int64(pktLen)))
+	m.counterOf(protocol).OnRecv(pktLen)
+}
+
+// OnDrop records the packet dropped by send queues or bandwidth limits.
+func (m *NetworkMetric) OnDrop(protocol uint16, pktLen uint32) {
+	ctx := m.getContextWith(&mkProtocol, "drop", fmt.Sprintf("%#04x", protocol))
+	stats.Record(ctx, msDrop.M(int64(pktLen)))
+	m.counterOf(protocol).OnDrop(pktLen)
+}
+
+// Traffic returns traffic statistics for each protocol.
+func (m *NetworkMetric) Traffic() map[uint16]TrafficStat {
+	m.protocolMtx.RLock()
+	defer m.protocolMtx.RUnlock()
+
+	res := make(map[uint16]TrafficStat, len(m.protocols))
+	for k, c := range m.protocols {
+		res[k] = c.Stat()
+	}
+	return res
 }
 
 func NewNetworkMetric(ctx context.Context) *NetworkMetric {
 	return &NetworkMetric{
-		ctx: ctx,
-		ctxMap: make(map[string]context.Context),
+		ctx:       ctx,
+		ctxMap:    make(map[string]context.Context),
+		protocols: make(map[uint16]*TrafficCounter),
 	}
 }
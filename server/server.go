@@ -217,14 +217,7 @@ func (srv *Manager) RegisterAPIHandler(g *echo.Group) {
 			srv.logger.Printf("response=%s", resBody)
 		}
 	}))
-	rpc.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(ctx echo.Context) error {
-			ctx.Set("includeDebug", srv.IncludeDebug())
-			ctx.Set("batchLimit", srv.BatchLimit())
-			ctx.Set("rosetta", srv.Rosetta())
-			return next(ctx)
-		}
-	})
+	rpc.Use(srv.OptionInjector())
 
 	// v3 APIs
 	mr := v3.MethodRepository(srv.mtr)
@@ -255,6 +248,21 @@ func (srv *Manager) RegisterAPIHandler(g *echo.Group) {
 	ws.GET("/v3/:channel/block", srv.wssm.RunBlockSession, ChainInjector(srv))
 	ws.GET("/v3/:channel/event", srv.wssm.RunEventSession, ChainInjector(srv))
 	ws.GET("/v3/:channel/btp", srv.wssm.RunBtpSession, ChainInjector(srv))
+	ws.GET("/v3/:channel/transaction", srv.wssm.RunPendingTxSession, ChainInjector(srv))
+	ws.GET("/v3/:channel/ws", srv.wssm.RunMuxSession(mr), ChainInjector(srv), srv.OptionInjector())
+	ws.GET("/v3d/:channel/consensus", srv.wssm.RunRoundEventSession, srv.CheckDebug(), ChainInjector(srv))
+}
+
+// OptionInjector sets options of the server for JSON-RPC handlers.
+func (srv *Manager) OptionInjector() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			ctx.Set("includeDebug", srv.IncludeDebug())
+			ctx.Set("batchLimit", srv.BatchLimit())
+			ctx.Set("rosetta", srv.Rosetta())
+			return next(ctx)
+		}
+	}
 }
 
 func (srv *Manager) RegisterMetricsHandler(g *echo.Group) {
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -16,6 +18,7 @@ import (
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
 	"github.com/icon-project/goloop/server/metric"
@@ -27,6 +30,7 @@ import (
 
 const (
 	ConfigShowPatchTransaction = false
+	ConfigMaxLogsRange         = 1000
 )
 
 func MethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
@@ -51,8 +55,10 @@ func MethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
 	mr.RegisterMethod("icx_getVotesByHeight", getVotesByHeight)
 	mr.RegisterMethod("icx_getProofForResult", getProofForResult)
 	mr.RegisterMethod("icx_getProofForEvents", getProofForEvents)
+	mr.RegisterMethod("icx_getProofForState", getProofForState)
 	mr.RegisterMethod("icx_getScoreStatus", getScoreStatus)
 	mr.RegisterMethod("icx_getNetworkInfo", getNetworkInfo)
+	mr.RegisterMethod("icx_getLogs", getLogs)
 
 	mr.RegisterMethod("btp_getNetworkInfo", getBTPNetworkInfo)
 	mr.RegisterMethod("btp_getNetworkTypeInfo", getBTPNetworkTypeInfo)
@@ -295,6 +301,17 @@ func getBlockByHash(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{},
 }
 
 func call(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	return callWithParams(ctx, params, false)
+}
+
+// debugCall is same as call, but it also accepts overrides of the state and
+// the block. It's only for the debug endpoint, because code overrides are
+// stored and executed by the node.
+func debugCall(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	return callWithParams(ctx, params, true)
+}
+
+func callWithParams(ctx *jsonrpc.Context, params *jsonrpc.Params, override bool) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
 		return nil, err
@@ -304,15 +321,31 @@ func call(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	if err := params.Convert(&param); err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
 	}
+	if !override {
+		if len(param.StateOverride) > 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.New("StateOverrideNotAllowed")
+		}
+		if param.BlockOverride != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.New("BlockOverrideNotAllowed")
+		}
+	}
 
 	blk, err := c.GetBlockByHeight(param.Height)
 	if err != nil {
 		return nil, err
 	}
 
+	var result interface{}
 	bi := common.NewBlockInfo(blk.Height(), blk.Timestamp())
-	result, err := c.sm.Call(blk.Result(), blk.NextValidators(), params.RawMessage(), bi)
+	if len(param.StateOverride) > 0 || param.BlockOverride != nil {
+		result, err = callWithOverride(&c, blk, bi, &param, params.RawMessage())
+	} else {
+		result, err = c.sm.Call(blk.Result(), blk.NextValidators(), params.RawMessage(), bi)
+	}
 	if err != nil {
+		if _, ok := err.(*jsonrpc.Error); ok {
+			return nil, err
+		}
 		if service.InvalidQueryError.Equals(err) {
 			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
 		} else if scoreresult.IsValid(err) {
@@ -325,6 +358,21 @@ func call(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	}
 }
 
+func callWithOverride(c *contextWithSM, blk module.Block, bi module.BlockInfo, param *CallParam, js []byte) (interface{}, error) {
+	sim, err := service.SimulatorOf(c.chain)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, c.debug)
+	}
+	aos, err := param.StateOverride.AccountOverrides()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	if bi, err = param.BlockOverride.BlockInfo(bi); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	return sim.CallWithOverride(blk.Result(), blk.NextValidators(), js, bi, aos)
+}
+
 func getBalance(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
@@ -704,6 +752,39 @@ func getProofForEvents(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{
 	return proofs, nil
 }
 
+func getProofForState(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param ProofStateParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	blk, err := c.GetBlockByID(param.BlockHash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	prover, err := service.StateProverOf(c.chain)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, c.debug)
+	}
+	keys := make([][]byte, len(param.Keys))
+	for i, key := range param.Keys {
+		keys[i] = key
+	}
+	proofs, err := prover.GetProofForState(blk.Result(), param.Address.Address(), keys)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, c.debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	return proofs, nil
+}
+
 func getScoreStatus(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
@@ -762,6 +843,130 @@ func getNetworkInfo(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{},
 	}, nil
 }
 
+type LogEntry struct {
+	BlockHeight common.HexInt64 `json:"blockHeight"`
+	BlockHash   common.HexBytes `json:"blockHash"`
+	TxIndex     common.HexInt32 `json:"txIndex"`
+	TxHash      common.HexBytes `json:"txHash"`
+	EventIndex  common.HexInt32 `json:"eventIndex"`
+	EventLog    module.EventLog `json:"eventLog"`
+}
+
+func getLogs(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param LogsParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	filters, err := param.Compile()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	from, err := param.FromHeight.Int64()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	if err = c.CheckBaseHeight(from); err != nil {
+		return nil, err
+	}
+
+	last, err := c.bm.GetLastBlock()
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	// receipts of transactions in a block are stored in the next block,
+	// so transactions in the last block can't be queried yet.
+	to := last.Height() - 1
+	if param.ToHeight != "" {
+		h, err := param.ToHeight.Int64()
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		if h < from {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidRange(from=%d,to=%d)", from, h)
+		}
+		if h < to {
+			to = h
+		}
+	}
+	if to-from+1 > ConfigMaxLogsRange {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"TooLargeRange(from=%d,to=%d,max=%d)", from, to, ConfigMaxLogsRange)
+	}
+
+	logs := make([]*LogEntry, 0)
+	if from > to {
+		return logs, nil
+	}
+	blk, err := c.bm.GetBlockByHeight(from)
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	for height := from; height <= to; height++ {
+		select {
+		case <-c.Request().Context().Done():
+			return nil, nil
+		default:
+		}
+		nblk, err := c.bm.GetBlockByHeight(height + 1)
+		if err != nil {
+			return nil, c.AsRPCError(err)
+		}
+		if filters2, contained := filters.FilteredByLogBloom(nblk.LogsBloom()); contained {
+			logs, err = appendMatchedLogs(logs, c.sm, blk, nblk.Result(), filters2)
+			if err != nil {
+				return nil, c.AsRPCError(err)
+			}
+		}
+		blk = nblk
+	}
+	return logs, nil
+}
+
+// appendMatchedLogs appends logs of the transactions in the block matched
+// by the filters. result is the result of the next block which has the
+// receipts of the transactions.
+func appendMatchedLogs(logs []*LogEntry, sm module.ServiceManager, blk module.Block, result []byte, filters EventFilters) ([]*LogEntry, error) {
+	rl, err := sm.ReceiptListFromResult(result, module.TransactionGroupNormal)
+	if err != nil {
+		return nil, err
+	}
+	txs := blk.NormalTransactions()
+	index := 0
+	for rit := rl.Iterator(); rit.Has(); rit.Next() {
+		r, err := rit.Get()
+		if err != nil {
+			return nil, err
+		}
+		var txHash []byte
+		err = filters.filterEvents(r, func(fi, idx int, el module.EventLog) {
+			if txHash == nil {
+				if tx, err := txs.Get(index); err == nil {
+					txHash = tx.ID()
+				}
+			}
+			logs = append(logs, &LogEntry{
+				BlockHeight: common.HexInt64{Value: blk.Height()},
+				BlockHash:   blk.ID(),
+				TxIndex:     common.HexInt32{Value: int32(index)},
+				TxHash:      txHash,
+				EventIndex:  common.HexInt32{Value: int32(idx)},
+				EventLog:    el,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+		index++
+	}
+	return logs, nil
+}
+
 func getBTPNetworkInfo(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
@@ -1148,7 +1353,13 @@ func DebugMethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository
 	RegisterValidationRule(mr.Validator())
 
 	mr.RegisterMethod("debug_getTrace", getTrace)
+	mr.RegisterMethod("debug_traceBlock", traceBlock)
 	mr.RegisterMethod("debug_estimateStep", estimateStep)
+	mr.RegisterMethod("debug_call", debugCall)
+	mr.RegisterMethod("debug_simulateTransactions", simulateTransactions)
+	mr.RegisterMethod("debug_getTxPool", getTxPool)
+	mr.RegisterMethod("debug_getRoundState", getRoundState)
+	mr.RegisterMethod("debug_getDoubleSignEvidence", getDoubleSignEvidence)
 
 	return mr
 }
@@ -1159,7 +1370,7 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 		return nil, err
 	}
 
-	var param TransactionHashParam
+	var param TraceParam
 	if err := params.Convert(&param); err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
 	}
@@ -1210,8 +1421,13 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 		logs:    make([]interface{}, 0, 100),
 		channel: make(chan interface{}, 10),
 	}
+	traceMode := module.TraceModeInvoke
+	if param.Mode == TraceModeCallTree {
+		traceMode = module.TraceModeCallTree
+		cb.ct = trace.NewCallTracer(1)
+	}
 	ti := module.TraceInfo{
-		TraceMode: module.TraceModeInvoke,
+		TraceMode: traceMode,
 		Range:     module.TraceRangeTransaction,
 		Group:     txInfo.Group(),
 		Index:     txInfo.Index(),
@@ -1230,11 +1446,97 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 			return nil, jsonrpc.ErrorCodeSystemTimeout.Errorf(
 				"Not enough time to get result of %x", param.Hash.Bytes())
 		case <-cb.channel:
+			if cb.ct != nil {
+				return cb.callTreeToJSON(), nil
+			}
 			return cb.invokeTraceToJSON(), nil
 		}
 	}
 }
 
+func traceBlock(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param TraceBlockParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	var blk module.Block
+	var err error
+	if len(param.Hash) > 0 {
+		blk, err = c.GetBlockByID(param.Hash.Bytes())
+	} else if len(param.Height) > 0 {
+		blk, err = c.GetBlockByHeight(param.Height)
+	} else {
+		return nil, jsonrpc.ErrorCodeInvalidParams.New("NoHeightOrHash")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nblk, err := c.bm.GetBlockByHeight(blk.Height() + 1)
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeExecuting.New("Executing")
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	csi, err := c.bm.NewConsensusInfo(blk)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	tr1, err := c.sm.CreateInitialTransition(blk.Result(), blk.NextValidators())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	tr2, err := c.sm.CreateTransition(tr1, blk.NormalTransactions(), blk, csi, true)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	tr2 = c.sm.PatchTransition(tr2, nblk.PatchTransactions(), nblk)
+
+	rl, err := c.sm.ReceiptListFromResult(nblk.Result(), module.TransactionGroupNormal)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+
+	cb := &traceCallback{
+		channel: make(chan interface{}, 10),
+	}
+	traceMode := module.TraceModeInvoke
+	if param.Mode == TraceModeCallTree {
+		traceMode = module.TraceModeCallTree
+		cb.ct = trace.NewCallTracer(10)
+	} else {
+		cb.txs = make([]*txTraceLogs, 0, 10)
+	}
+	ti := module.TraceInfo{
+		TraceMode:  traceMode,
+		TraceBlock: trace.NewTraceBlock(blk.ID(), rl),
+		Range:      module.TraceRangeBlock,
+		Callback:   cb,
+	}
+	canceller, err := tr2.ExecuteForTrace(ti)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+
+	timer := time.After(time.Second * 60)
+	for {
+		select {
+		case <-timer:
+			canceller()
+			return nil, jsonrpc.ErrorCodeSystemTimeout.Errorf(
+				"Not enough time to trace block %#x", blk.ID())
+		case <-cb.channel:
+			return cb.blockTraceToJSON(blk), nil
+		}
+	}
+}
+
 func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
@@ -1261,13 +1563,25 @@ func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, er
 	bi := common.NewBlockInfo(blk.Height()+1, newTS)
 
 	// execute transaction
-	rct, err := c.sm.ExecuteTransaction(
-		blk.Result(),
-		blk.NextValidators().Hash(),
-		params.RawMessage(),
-		bi,
-	)
+	js, err := withoutOverrides(params.RawMessage())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	var rct module.Receipt
+	if len(param.StateOverride) > 0 || param.BlockOverride != nil {
+		rct, err = executeWithOverride(&c, blk, bi, &param, js)
+	} else {
+		rct, err = c.sm.ExecuteTransaction(
+			blk.Result(),
+			blk.NextValidators().Hash(),
+			js,
+			bi,
+		)
+	}
 	if err != nil {
+		if _, ok := err.(*jsonrpc.Error); ok {
+			return nil, err
+		}
 		return nil, jsonrpc.ErrorCodeServer.Wrap(err, c.debug)
 	}
 	if status := rct.Status(); status != module.StatusSuccess {
@@ -1283,6 +1597,335 @@ func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, er
 	return steps, nil
 }
 
+func executeWithOverride(c *contextWithSM, blk module.Block, bi module.BlockInfo, param *TransactionParamForEstimate, js []byte) (module.Receipt, error) {
+	sim, err := service.SimulatorOf(c.chain)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, c.debug)
+	}
+	aos, err := param.StateOverride.AccountOverrides()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	if bi, err = param.BlockOverride.BlockInfo(bi); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	return sim.ExecuteTransactionWithOverride(
+		blk.Result(),
+		blk.NextValidators().Hash(),
+		js,
+		bi,
+		aos,
+	)
+}
+
+func simulateTransactions(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param SimulateTransactionsParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	traceBalance, err := param.BalanceChanges.Bool()
+	if len(param.BalanceChanges) > 0 && err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	txs := make([][]byte, len(param.Transactions))
+	for i, tx := range param.Transactions {
+		if len(tx.StateOverride) > 0 || tx.BlockOverride != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"OverrideInTransaction(idx=%d)", i)
+		}
+		js, err := json.Marshal(tx)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		txs[i] = js
+	}
+	aos, err := param.StateOverride.AccountOverrides()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	sim, err := service.SimulatorOf(c.chain)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeMethodNotFound.Wrap(err, c.debug)
+	}
+	blk, err := c.GetBlockByHeight(param.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	// new block information based on the block
+	oldTS := blk.Timestamp()
+	newTS := common.UnixMicroFromTime(time.Now())
+	if newTS <= oldTS {
+		newTS = oldTS + 1
+	}
+	bi, err := param.BlockOverride.BlockInfo(common.NewBlockInfo(blk.Height()+1, newTS))
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	var ti *module.TraceInfo
+	var cb *traceCallback
+	if traceBalance {
+		cb = &traceCallback{
+			bt: trace.NewBalanceTracer(len(txs), nil),
+		}
+		ti = &module.TraceInfo{
+			TraceMode: module.TraceModeBalanceChange,
+			Callback:  cb,
+		}
+	}
+	rcts, err := sim.SimulateTransactions(
+		blk.Result(),
+		blk.NextValidators().Hash(),
+		txs,
+		bi,
+		aos,
+		ti,
+	)
+	if err != nil {
+		if scoreresult.InvalidParameterError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, c.debug)
+	}
+
+	results := make([]interface{}, len(rcts))
+	for i, rct := range rcts {
+		jso, err := rct.ToJSON(module.JSONVersionLast)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+		}
+		if rctMap, ok := jso.(map[string]interface{}); ok {
+			rctMap["txIndex"] = "0x" + strconv.FormatInt(int64(i), 16)
+		}
+		results[i] = jso
+	}
+	result := map[string]interface{}{
+		"blockHeight": "0x" + strconv.FormatInt(bi.Height(), 16),
+		"receipts":    results,
+	}
+	if cb != nil {
+		result["balanceChanges"] = cb.bt.ToJSON(bi.Height())
+	}
+	return result, nil
+}
+
+type TxPoolInfo struct {
+	Size    common.HexInt32            `json:"size"`
+	Used    common.HexInt32            `json:"used"`
+	Senders map[string]common.HexInt32 `json:"senders"`
+}
+
+type TxDropReason struct {
+	Code  common.HexInt32 `json:"code"`
+	Count common.HexInt32 `json:"count"`
+}
+
+type TxDropInfo struct {
+	TxHash  common.HexBytes `json:"txHash"`
+	Code    common.HexInt32 `json:"code"`
+	Message string          `json:"message"`
+}
+
+type TxDropsInfo struct {
+	Reasons []*TxDropReason `json:"reasons"`
+	Recent  []*TxDropInfo   `json:"recent"`
+}
+
+func getTxPool(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param struct{}
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	tpi, err := service.TxPoolInspectorOf(c.chain)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, c.debug)
+	}
+
+	result := make(map[string]interface{})
+	for _, ps := range tpi.TxPoolStatus() {
+		senders := make(map[string]common.HexInt32, len(ps.Senders))
+		for addr, cnt := range ps.Senders {
+			senders[addr] = common.HexInt32{Value: int32(cnt)}
+		}
+		info := &TxPoolInfo{
+			Size:    common.HexInt32{Value: int32(ps.Size)},
+			Used:    common.HexInt32{Value: int32(ps.Used)},
+			Senders: senders,
+		}
+		switch ps.Group {
+		case module.TransactionGroupNormal:
+			result["normal"] = info
+		case module.TransactionGroupPatch:
+			result["patch"] = info
+		}
+	}
+
+	ds := tpi.TxDropStatus()
+	drops := &TxDropsInfo{
+		Reasons: make([]*TxDropReason, 0, len(ds.Reasons)),
+		Recent:  make([]*TxDropInfo, 0, len(ds.Recent)),
+	}
+	for code, cnt := range ds.Reasons {
+		drops.Reasons = append(drops.Reasons, &TxDropReason{
+			Code:  common.HexInt32{Value: int32(code)},
+			Count: common.HexInt32{Value: int32(cnt)},
+		})
+	}
+	sort.Slice(drops.Reasons, func(i, j int) bool {
+		return drops.Reasons[i].Code.Value < drops.Reasons[j].Code.Value
+	})
+	for _, d := range ds.Recent {
+		drops.Recent = append(drops.Recent, &TxDropInfo{
+			TxHash:  d.ID,
+			Code:    common.HexInt32{Value: int32(errors.CodeOf(d.Err))},
+			Message: d.Err.Error(),
+		})
+	}
+	result["drops"] = drops
+	return result, nil
+}
+
+type RoundEventInfo struct {
+	Height     common.HexInt64 `json:"height"`
+	Round      common.HexInt32 `json:"round"`
+	Step       string          `json:"step"`
+	Reason     string          `json:"reason"`
+	Timestamp  common.HexInt64 `json:"timestamp"`
+	Proposer   module.Address  `json:"proposer,omitempty"`
+	Prevotes   string          `json:"prevotes"`
+	Precommits string          `json:"precommits"`
+}
+
+func NewRoundEventInfo(ev *consensus.RoundEvent) *RoundEventInfo {
+	return &RoundEventInfo{
+		Height:     common.HexInt64{Value: ev.Height},
+		Round:      common.HexInt32{Value: ev.Round},
+		Step:       ev.Step,
+		Reason:     ev.Reason,
+		Timestamp:  common.HexInt64{Value: ev.Timestamp.UnixMicro()},
+		Proposer:   ev.Proposer,
+		Prevotes:   ev.Prevotes,
+		Precommits: ev.Precommits,
+	}
+}
+
+type RoundStateInfo struct {
+	Height      common.HexInt64   `json:"height"`
+	Round       common.HexInt32   `json:"round"`
+	Step        string            `json:"step"`
+	Proposer    module.Address    `json:"proposer,omitempty"`
+	Validators  []module.Address  `json:"validators"`
+	Prevotes    string            `json:"prevotes"`
+	Precommits  string            `json:"precommits"`
+	BlockParts  string            `json:"blockParts,omitempty"`
+	LockedRound common.HexInt32   `json:"lockedRound"`
+	Sync        common.HexBool    `json:"sync"`
+	Events      []*RoundEventInfo `json:"events"`
+}
+
+func getRoundState(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithCS
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param struct{}
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	rsi, err := consensus.RoundStateInspectorOf(c.chain)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, c.debug)
+	}
+	rs := rsi.GetRoundStatus()
+	if rs == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("NoRoundState")
+	}
+	info := &RoundStateInfo{
+		Height:      common.HexInt64{Value: rs.Height},
+		Round:       common.HexInt32{Value: rs.Round},
+		Step:        rs.Step,
+		Proposer:    rs.Proposer,
+		Validators:  rs.Validators,
+		Prevotes:    rs.Prevotes,
+		Precommits:  rs.Precommits,
+		BlockParts:  rs.BlockParts,
+		LockedRound: common.HexInt32{Value: rs.LockedRound},
+		Sync:        common.HexBool{Value: rs.Sync},
+		Events:      make([]*RoundEventInfo, 0, len(rs.Events)),
+	}
+	if info.Validators == nil {
+		info.Validators = []module.Address{}
+	}
+	for _, ev := range rs.Events {
+		info.Events = append(info.Events, NewRoundEventInfo(ev))
+	}
+	return info, nil
+}
+
+type EvidenceInfo struct {
+	Type     string            `json:"type"`
+	Height   common.HexInt64   `json:"height"`
+	Round    common.HexInt32   `json:"round"`
+	Signer   *common.Address   `json:"signer"`
+	Data     []common.HexBytes `json:"data"`
+	Detected common.HexInt64   `json:"detected"`
+	Status   string            `json:"status"`
+	Attempts common.HexInt32   `json:"attempts"`
+	Error    string            `json:"error,omitempty"`
+}
+
+func getDoubleSignEvidence(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithCS
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param struct{}
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	ei, err := consensus.EvidenceInspectorOf(c.chain)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, c.debug)
+	}
+	evidences := ei.GetEvidences()
+	result := make([]*EvidenceInfo, 0, len(evidences))
+	for _, e := range evidences {
+		data := make([]common.HexBytes, len(e.Data))
+		for i, d := range e.Data {
+			data[i] = d
+		}
+		result = append(result, &EvidenceInfo{
+			Type:     e.Type,
+			Height:   common.HexInt64{Value: e.Height},
+			Round:    common.HexInt32{Value: e.Round},
+			Signer:   e.Signer,
+			Data:     data,
+			Detected: common.HexInt64{Value: e.Detected},
+			Status:   e.Status,
+			Attempts: common.HexInt32{Value: int32(e.Attempts)},
+			Error:    e.Error,
+		})
+	}
+	return result, nil
+}
+
 type MissingTransactionInfo interface {
 	ReplaceID(height int64, id []byte) []byte
 	GetLocationOf(id []byte) (int64, int, bool)
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+type testBlockManager struct {
+	module.BlockManager
+}
+
+func (bm *testBlockManager) GetLastBlock() (module.Block, error) {
+	return nil, errors.NotFoundError.New("NoLastBlock")
+}
+
+type testChain struct {
+	module.Chain
+	bm module.BlockManager
+	sm module.ServiceManager
+}
+
+func (c *testChain) BlockManager() module.BlockManager {
+	return c.bm
+}
+
+func (c *testChain) ServiceManager() module.ServiceManager {
+	return c.sm
+}
+
+func (c *testChain) MetricContext() context.Context {
+	return metric.DefaultMetricContext()
+}
+
+func handleRequest(t *testing.T, mr *jsonrpc.MethodRepository, method string, params string) *jsonrpc.Response {
+	e := echo.New()
+	e.Validator = mr.Validator()
+	ec := e.NewContext(httptest.NewRequest("POST", "/", nil), httptest.NewRecorder())
+	ec.Set("chain", &testChain{
+		bm: &testBlockManager{},
+		sm: struct{ module.ServiceManager }{},
+	})
+	ec.Set("includeDebug", false)
+	req := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":` + params + `}`
+	resp := mr.HandleMessage(jsonrpc.NewContext(ec), json.RawMessage(req))
+	assert.NotNil(t, resp)
+	return resp
+}
+
+func TestCall_Override(t *testing.T) {
+	mtr := metric.NewJsonrpcMetric(time.Minute, 10, true)
+	public := MethodRepository(mtr)
+	debug := DebugMethodRepository(mtr)
+
+	cases := []struct {
+		name     string
+		override string
+	}{
+		{"State", `"stateOverride": { "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31": { "balance": "0x10" } }`},
+		{"Block", `"blockOverride": { "height": "0x10", "timestamp": "0x20" }`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			params := `{
+				"from": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31",
+				"to": "cx0000000000000000000000000000000000000001",
+				"dataType": "call",
+				"data": { "method": "getBalance" },
+				` + c.override + `
+			}`
+
+			resp := handleRequest(t, public, "icx_call", params)
+			if assert.NotNil(t, resp.Error) {
+				assert.Equal(t, jsonrpc.ErrorCodeInvalidParams, resp.Error.Code)
+				assert.Contains(t, resp.Error.Message, c.name+"OverrideNotAllowed")
+			}
+
+			// it's accepted by the debug endpoint, so it fails on
+			// getting the block later.
+			resp = handleRequest(t, debug, "debug_call", params)
+			if assert.NotNil(t, resp.Error) {
+				assert.Equal(t, jsonrpc.ErrorCodeNotFound, resp.Error.Code)
+			}
+		})
+	}
+}
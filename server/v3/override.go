@@ -0,0 +1,113 @@
+/*
+ * Copyright 2022 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+)
+
+const (
+	keyStateOverride = "stateOverride"
+	keyBlockOverride = "blockOverride"
+)
+
+// AccountOverrides returns overrides for the service sorted by address.
+func (p StateOverrideParam) AccountOverrides() ([]*service.AccountOverride, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	aos := make([]*service.AccountOverride, 0, len(p))
+	for addr, param := range p {
+		if param == nil {
+			continue
+		}
+		ao := &service.AccountOverride{
+			Address:     addr.Address(),
+			Code:        param.Code,
+			ContentType: param.ContentType,
+		}
+		if len(param.Balance) > 0 {
+			balance, err := param.Balance.BigInt()
+			if err != nil {
+				return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidBalance(%s)", param.Balance)
+			}
+			ao.Balance = balance
+		}
+		if len(param.Storage) > 0 {
+			ao.Storage = make(map[string][]byte, len(param.Storage))
+			for k, v := range param.Storage {
+				var key common.HexBytes
+				if err := key.UnmarshalJSON([]byte(`"` + k + `"`)); err != nil || len(key) == 0 {
+					return nil, errors.IllegalArgumentError.Errorf("InvalidStorageKey(%s)", k)
+				}
+				ao.Storage[string(key)] = v
+			}
+		}
+		aos = append(aos, ao)
+	}
+	sort.Slice(aos, func(i, j int) bool {
+		return aos[i].Address.String() < aos[j].Address.String()
+	})
+	return aos, nil
+}
+
+// BlockInfo returns the block information replacing the height and the
+// timestamp of bi with specified ones.
+func (p *BlockOverrideParam) BlockInfo(bi module.BlockInfo) (module.BlockInfo, error) {
+	if p == nil {
+		return bi, nil
+	}
+	height, timestamp := bi.Height(), bi.Timestamp()
+	if len(p.Height) > 0 {
+		v, err := p.Height.Int64()
+		if err != nil || v < 0 {
+			return nil, errors.IllegalArgumentError.Errorf("InvalidHeight(%s)", p.Height)
+		}
+		height = v
+	}
+	if len(p.Timestamp) > 0 {
+		v, err := p.Timestamp.Int64()
+		if err != nil || v < 0 {
+			return nil, errors.IllegalArgumentError.Errorf("InvalidTimestamp(%s)", p.Timestamp)
+		}
+		timestamp = v
+	}
+	return common.NewBlockInfo(height, timestamp), nil
+}
+
+// withoutOverrides removes overrides from the parameters, so that the rest
+// could be handled as a transaction.
+func withoutOverrides(js []byte) ([]byte, error) {
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(js, &params); err != nil {
+		return nil, err
+	}
+	_, hasState := params[keyStateOverride]
+	_, hasBlock := params[keyBlockOverride]
+	if !hasState && !hasBlock {
+		return js, nil
+	}
+	delete(params, keyStateOverride)
+	delete(params, keyBlockOverride)
+	return json.Marshal(params)
+}
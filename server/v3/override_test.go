@@ -0,0 +1,99 @@
+/*
+ * Copyright 2022 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+)
+
+func TestStateOverrideParam_AccountOverrides(t *testing.T) {
+	p := StateOverrideParam{
+		"hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31": {
+			Balance: "0x10",
+		},
+		"cx059e19601bcb1424884f4ef19addc0a03de9e9cd": {
+			Code:        common.HexBytes{0x01},
+			ContentType: "application/java",
+			Storage: map[string]common.HexBytes{
+				"0x0102": {0x03},
+			},
+		},
+	}
+	aos, err := p.AccountOverrides()
+	assert.NoError(t, err)
+	assert.Len(t, aos, 2)
+
+	assert.Equal(t, "cx059e19601bcb1424884f4ef19addc0a03de9e9cd", aos[0].Address.String())
+	assert.Nil(t, aos[0].Balance)
+	assert.Equal(t, []byte{0x01}, aos[0].Code)
+	assert.Equal(t, "application/java", aos[0].ContentType)
+	assert.Equal(t, map[string][]byte{"\x01\x02": {0x03}}, aos[0].Storage)
+
+	assert.Equal(t, "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31", aos[1].Address.String())
+	assert.Equal(t, big.NewInt(16), aos[1].Balance)
+
+	p = StateOverrideParam{
+		"hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31": {
+			Storage: map[string]common.HexBytes{
+				"0xzz": {0x03},
+			},
+		},
+	}
+	_, err = p.AccountOverrides()
+	assert.Error(t, err)
+}
+
+func TestBlockOverrideParam_BlockInfo(t *testing.T) {
+	bi := common.NewBlockInfo(10, 1000)
+
+	var p *BlockOverrideParam
+	bi2, err := p.BlockInfo(bi)
+	assert.NoError(t, err)
+	assert.Equal(t, bi, bi2)
+
+	p = &BlockOverrideParam{Height: "0x20"}
+	bi2, err = p.BlockInfo(bi)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0x20, bi2.Height())
+	assert.EqualValues(t, 1000, bi2.Timestamp())
+
+	p = &BlockOverrideParam{Timestamp: "0x20"}
+	bi2, err = p.BlockInfo(bi)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 10, bi2.Height())
+	assert.EqualValues(t, 0x20, bi2.Timestamp())
+
+	p = &BlockOverrideParam{Height: "-0x1"}
+	_, err = p.BlockInfo(bi)
+	assert.Error(t, err)
+}
+
+func TestWithoutOverrides(t *testing.T) {
+	js := []byte(`{"to":"hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31","value":"0x1"}`)
+	js2, err := withoutOverrides(js)
+	assert.NoError(t, err)
+	assert.Equal(t, js, js2)
+
+	js2, err = withoutOverrides([]byte(`{"value":"0x1","stateOverride":{},"blockOverride":{"height":"0x1"}}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"value":"0x1"}`, string(js2))
+}
@@ -1,6 +1,7 @@
 package v3
 
 import (
+	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/server/jsonrpc"
 )
@@ -26,11 +27,28 @@ type BlockHashParam struct {
 }
 
 type CallParam struct {
-	FromAddress jsonrpc.Address `json:"from,omitempty" validate:"optional,t_addr_eoa"`
-	ToAddress   jsonrpc.Address `json:"to" validate:"required,t_addr_score"`
-	DataType    string          `json:"dataType" validate:"required,call"`
-	Data        interface{}     `json:"data"`
-	Height      jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
+	FromAddress   jsonrpc.Address     `json:"from,omitempty" validate:"optional,t_addr_eoa"`
+	ToAddress     jsonrpc.Address     `json:"to" validate:"required,t_addr_score"`
+	DataType      string              `json:"dataType" validate:"required,call"`
+	Data          interface{}         `json:"data"`
+	Height        jsonrpc.HexInt      `json:"height,omitempty" validate:"optional,t_int"`
+	StateOverride StateOverrideParam  `json:"stateOverride,omitempty" validate:"optional,dive,keys,t_addr,endkeys,required"`
+	BlockOverride *BlockOverrideParam `json:"blockOverride,omitempty"`
+}
+
+type AccountOverrideParam struct {
+	Balance     jsonrpc.HexInt             `json:"balance,omitempty" validate:"optional,t_int"`
+	Code        common.HexBytes            `json:"code,omitempty"`
+	ContentType string                     `json:"contentType,omitempty"`
+	Storage     map[string]common.HexBytes `json:"storage,omitempty"`
+}
+
+// StateOverrideParam maps addresses to overrides of their accounts.
+type StateOverrideParam map[jsonrpc.Address]*AccountOverrideParam
+
+type BlockOverrideParam struct {
+	Height    jsonrpc.HexInt `json:"height,omitempty" validate:"optional,t_int"`
+	Timestamp jsonrpc.HexInt `json:"timestamp,omitempty" validate:"optional,t_int"`
 }
 
 type AddressParam struct {
@@ -47,6 +65,22 @@ type TransactionHashParam struct {
 	Hash jsonrpc.HexBytes `json:"txHash" validate:"required,t_hash"`
 }
 
+const (
+	TraceModeInvoke   = "invoke"
+	TraceModeCallTree = "callTree"
+)
+
+type TraceParam struct {
+	Hash jsonrpc.HexBytes `json:"txHash" validate:"required,t_hash"`
+	Mode string           `json:"mode,omitempty" validate:"omitempty,oneof=invoke callTree"`
+}
+
+type TraceBlockParam struct {
+	Height jsonrpc.HexInt   `json:"height,omitempty" validate:"optional,t_int"`
+	Hash   jsonrpc.HexBytes `json:"hash,omitempty" validate:"optional,t_hash"`
+	Mode   string           `json:"mode,omitempty" validate:"omitempty,oneof=invoke callTree"`
+}
+
 type TransactionParamForEstimate struct {
 	Version     jsonrpc.HexInt  `json:"version" validate:"required,t_int"`
 	FromAddress jsonrpc.Address `json:"from" validate:"required,t_addr_eoa"`
@@ -57,6 +91,17 @@ type TransactionParamForEstimate struct {
 	Nonce       jsonrpc.HexInt  `json:"nonce,omitempty" validate:"optional,t_int"`
 	DataType    string          `json:"dataType,omitempty" validate:"optional,call|deploy|message|deposit"`
 	Data        interface{}     `json:"data,omitempty"`
+
+	StateOverride StateOverrideParam  `json:"stateOverride,omitempty" validate:"optional,dive,keys,t_addr,endkeys,required"`
+	BlockOverride *BlockOverrideParam `json:"blockOverride,omitempty"`
+}
+
+type SimulateTransactionsParam struct {
+	Height         jsonrpc.HexInt                 `json:"height,omitempty" validate:"optional,t_int"`
+	Transactions   []*TransactionParamForEstimate `json:"transactions" validate:"required,gt=0,dive,required"`
+	BalanceChanges jsonrpc.HexBool                `json:"balanceChanges,omitempty" validate:"optional,t_bool"`
+	StateOverride  StateOverrideParam             `json:"stateOverride,omitempty" validate:"optional,dive,keys,t_addr,endkeys,required"`
+	BlockOverride  *BlockOverrideParam            `json:"blockOverride,omitempty"`
 }
 
 type TransactionParam struct {
@@ -88,6 +133,12 @@ type ProofEventsParam struct {
 	Events    []jsonrpc.HexInt `json:"events" validate:"gt=0,dive,t_int"`
 }
 
+type ProofStateParam struct {
+	BlockHash jsonrpc.HexBytes  `json:"hash" validate:"required,t_hash"`
+	Address   jsonrpc.Address   `json:"address" validate:"required,t_addr"`
+	Keys      []common.HexBytes `json:"keys,omitempty" validate:"optional,dive,min=1"`
+}
+
 type RosettaTraceParam struct {
 	Tx     jsonrpc.HexBytes `json:"tx,omitempty" validate:"optional,t_rhash"`
 	Block  jsonrpc.HexBytes `json:"block,omitempty" validate:"optional,t_hash"`
@@ -103,3 +154,14 @@ type BTPMessagesParam struct {
 	Height    jsonrpc.HexInt `json:"height" validate:"required,t_int"`
 	NetworkId jsonrpc.HexInt `json:"networkID" validate:"required,t_int"`
 }
+
+type LogsParam struct {
+	EventFilter
+	FromHeight jsonrpc.HexInt `json:"fromHeight" validate:"required,t_int"`
+	ToHeight   jsonrpc.HexInt `json:"toHeight,omitempty" validate:"optional,t_int"`
+	Filters    EventFilters   `json:"eventFilters,omitempty"`
+}
+
+func (p *LogsParam) Compile() (EventFilters, error) {
+	return CompileEventFilters(&p.EventFilter, p.Filters)
+}
@@ -0,0 +1,69 @@
+package v3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogsParam_Compile(t *testing.T) {
+	tests := []struct {
+		name    string
+		param   LogsParam
+		want    int
+		wantErr bool
+	}{
+		{
+			"Legacy",
+			LogsParam{
+				EventFilter: EventFilter{Signature: "TestEvent(int)"},
+				FromHeight:  "0x1",
+			},
+			1, false,
+		},
+		{
+			"Multi",
+			LogsParam{
+				FromHeight: "0x1",
+				Filters: EventFilters{
+					&EventFilter{Signature: "TestEvent(int)"},
+					&EventFilter{Signature: "TestEvent2()"},
+				},
+			},
+			2, false,
+		},
+		{
+			"Both",
+			LogsParam{
+				EventFilter: EventFilter{Signature: "TestEvent(int)"},
+				FromHeight:  "0x1",
+				Filters: EventFilters{
+					&EventFilter{Signature: "TestEvent2()"},
+				},
+			},
+			0, true,
+		},
+		{
+			"BadSignature",
+			LogsParam{
+				EventFilter: EventFilter{Signature: "TestEvent("},
+				FromHeight:  "0x1",
+			},
+			0, true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, err := tt.param.Compile()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, fs, tt.want)
+			for _, f := range fs {
+				assert.NotNil(t, f.LogsBloom())
+			}
+		})
+	}
+}
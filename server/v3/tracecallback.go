@@ -19,6 +19,34 @@ type traceCallback struct {
 	ts      time.Time
 	channel chan interface{}
 	bt      *trace.BalanceTracer
+	ct      *trace.CallTracer
+
+	// txs keeps logs of each transaction if it's not nil.
+	txs   []*txTraceLogs
+	curTx *txTraceLogs
+}
+
+type txTraceLogs struct {
+	index     int
+	hash      []byte
+	isBlockTx bool
+	logs      []interface{}
+}
+
+func (t *txTraceLogs) toJSON() map[string]interface{} {
+	prefix := "0x"
+	if t.isBlockTx {
+		prefix = "bx"
+	}
+	logs := t.logs
+	if logs == nil {
+		logs = []interface{}{}
+	}
+	return map[string]interface{}{
+		"txIndex": fmt.Sprintf("%#x", t.index),
+		"txHash":  prefix + hex.EncodeToString(t.hash),
+		"logs":    logs,
+	}
 }
 
 type traceLog struct {
@@ -32,11 +60,15 @@ func (t *traceCallback) OnLog(level module.TraceLevel, msg string) {
 	defer t.lock.Unlock()
 
 	ts := time.Now()
-	if len(t.logs) == 0 {
+	if t.ts.IsZero() {
 		t.ts = ts
 	}
 	dur := ts.Sub(t.ts) / time.Microsecond
-	t.logs = append(t.logs, traceLog{level, msg, int64(dur)})
+	if t.curTx != nil {
+		t.curTx.logs = append(t.curTx.logs, traceLog{level, msg, int64(dur)})
+	} else {
+		t.logs = append(t.logs, traceLog{level, msg, int64(dur)})
+	}
 }
 
 func (t *traceCallback) OnEnd(e error) {
@@ -56,6 +88,11 @@ func (t *traceCallback) invokeTraceToJSON() interface{} {
 	result := map[string]interface{}{
 		"logs": t.logs,
 	}
+	t.setStatusInLock(result)
+	return result
+}
+
+func (t *traceCallback) setStatusInLock(result map[string]interface{}) {
 	if t.last == nil {
 		result["status"] = "0x1"
 	} else {
@@ -66,6 +103,44 @@ func (t *traceCallback) invokeTraceToJSON() interface{} {
 			"message": t.last.Error(),
 		}
 	}
+}
+
+func (t *traceCallback) callTreeToJSON() interface{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	result := map[string]interface{}{}
+	if txs := t.ct.ToJSON(); len(txs) > 0 {
+		result["calls"] = txs[len(txs)-1]["calls"]
+	} else {
+		result["calls"] = []interface{}{}
+	}
+	t.setStatusInLock(result)
+	return result
+}
+
+func (t *traceCallback) blockTraceToJSON(blk module.Block) interface{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	result := map[string]interface{}{
+		"blockHash":   "0x" + hex.EncodeToString(blk.ID()),
+		"blockHeight": fmt.Sprintf("%#x", blk.Height()),
+	}
+	var txs []map[string]interface{}
+	if t.ct != nil {
+		txs = t.ct.ToJSON()
+	} else {
+		txs = make([]map[string]interface{}, len(t.txs))
+		for i, tx := range t.txs {
+			txs[i] = tx.toJSON()
+		}
+		if len(t.logs) > 0 {
+			result["logs"] = t.logs
+		}
+	}
+	result["transactions"] = txs
+	t.setStatusInLock(result)
 	return result
 }
 
@@ -89,11 +164,26 @@ func (t *traceCallback) balanceChangeToJSON(blk module.Block) interface{} {
 }
 
 func (t *traceCallback) OnTransactionStart(txIndex int, txHash []byte, isBlockTx bool) error {
+	if t.txs != nil {
+		t.lock.Lock()
+		t.curTx = &txTraceLogs{
+			index:     txIndex,
+			hash:      txHash,
+			isBlockTx: isBlockTx,
+		}
+		t.txs = append(t.txs, t.curTx)
+		t.lock.Unlock()
+	}
 	if t.bt != nil {
 		t.lock.Lock()
 		defer t.lock.Unlock()
 		return t.bt.OnTransactionStart(txIndex, txHash, isBlockTx)
 	}
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnTransactionStart(txIndex, txHash, isBlockTx)
+	}
 	return nil
 }
 
@@ -101,19 +191,37 @@ func (t *traceCallback) OnTransactionReset() error {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
-	t.logs = nil
+	if t.curTx != nil {
+		t.curTx.logs = nil
+	} else {
+		t.logs = nil
+		t.ts = time.Time{}
+	}
 	if t.bt != nil {
 		return t.bt.OnTransactionReset()
 	}
+	if t.ct != nil {
+		return t.ct.OnTransactionReset()
+	}
 	return nil
 }
 
 func (t *traceCallback) OnTransactionEnd(txIndex int, txHash []byte) error {
+	if t.txs != nil {
+		t.lock.Lock()
+		t.curTx = nil
+		t.lock.Unlock()
+	}
 	if t.bt != nil {
 		t.lock.Lock()
 		defer t.lock.Unlock()
 		return t.bt.OnTransactionEnd(txIndex, txHash)
 	}
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnTransactionEnd(txIndex, txHash)
+	}
 	return nil
 }
 
@@ -123,6 +231,11 @@ func (t *traceCallback) OnFrameEnter() error {
 		defer t.lock.Unlock()
 		return t.bt.OnFrameEnter()
 	}
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnFrameEnter()
+	}
 	return nil
 }
 
@@ -132,6 +245,11 @@ func (t *traceCallback) OnFrameExit(success bool) error {
 		defer t.lock.Unlock()
 		return t.bt.OnFrameExit(success)
 	}
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnFrameExit(success)
+	}
 	return nil
 }
 
@@ -143,3 +261,30 @@ func (t *traceCallback) OnBalanceChange(opType module.OpType, from, to module.Ad
 	}
 	return nil
 }
+
+func (t *traceCallback) OnCallStart(info *module.TraceCallInfo) error {
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnCallStart(info)
+	}
+	return nil
+}
+
+func (t *traceCallback) OnCallEnd(status error, stepUsed *big.Int, result interface{}) error {
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnCallEnd(status, stepUsed, result)
+	}
+	return nil
+}
+
+func (t *traceCallback) OnEvent(log module.EventLog) error {
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnEvent(log)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2022 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+)
+
+type testTraceBlock struct {
+	module.Block
+	id     []byte
+	height int64
+}
+
+func (b *testTraceBlock) ID() []byte {
+	return b.id
+}
+
+func (b *testTraceBlock) Height() int64 {
+	return b.height
+}
+
+func TestTraceCallback_BlockTraceToJSON(t *testing.T) {
+	cb := &traceCallback{
+		channel: make(chan interface{}, 1),
+		txs:     make([]*txTraceLogs, 0, 2),
+	}
+
+	assert.NoError(t, cb.OnTransactionStart(0, []byte{0x01}, false))
+	cb.OnLog(module.TSystemLevel, "discarded")
+	assert.NoError(t, cb.OnTransactionReset())
+	cb.OnLog(module.TSystemLevel, "tx0")
+	assert.NoError(t, cb.OnTransactionEnd(0, []byte{0x01}))
+
+	cb.OnLog(module.TSystemLevel, "block")
+
+	assert.NoError(t, cb.OnTransactionStart(1, []byte{0x02}, true))
+	assert.NoError(t, cb.OnTransactionEnd(1, []byte{0x02}))
+	cb.OnEnd(nil)
+
+	blk := &testTraceBlock{id: []byte{0xab}, height: 10}
+	result := cb.blockTraceToJSON(blk).(map[string]interface{})
+	assert.Equal(t, "0xab", result["blockHash"])
+	assert.Equal(t, "0xa", result["blockHeight"])
+	assert.Equal(t, "0x1", result["status"])
+	assert.Len(t, result["logs"], 1)
+
+	txs := result["transactions"].([]map[string]interface{})
+	assert.Len(t, txs, 2)
+	assert.Equal(t, "0x0", txs[0]["txIndex"])
+	assert.Equal(t, "0x01", txs[0]["txHash"])
+	logs := txs[0]["logs"].([]interface{})
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "tx0", logs[0].(traceLog).Msg)
+	assert.Equal(t, "0x1", txs[1]["txIndex"])
+	assert.Equal(t, "bx02", txs[1]["txHash"])
+	assert.Len(t, txs[1]["logs"], 0)
+}
@@ -107,3 +107,200 @@ func TestTransactionParamValidator(t *testing.T) {
 		assert.Fail(t, "validate fail", err.Error())
 	}
 }
+
+func TestCallParamValidator_Override(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	tests := []struct {
+		name    string
+		param   string
+		wantErr bool
+	}{
+		{
+			"Valid",
+			`{
+				"to": "cx059e19601bcb1424884f4ef19addc0a03de9e9cd",
+				"dataType": "call",
+				"data": { "method": "balanceOf" },
+				"stateOverride": {
+					"hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31": { "balance": "0x10" },
+					"cx059e19601bcb1424884f4ef19addc0a03de9e9cd": {
+						"storage": { "0x01": "0x02" }
+					}
+				},
+				"blockOverride": { "height": "0x10", "timestamp": "0x20" }
+			}`,
+			false,
+		},
+		{
+			"InvalidAddress",
+			`{
+				"to": "cx059e19601bcb1424884f4ef19addc0a03de9e9cd",
+				"dataType": "call",
+				"data": { "method": "balanceOf" },
+				"stateOverride": {
+					"hx01": { "balance": "0x10" }
+				}
+			}`,
+			true,
+		},
+		{
+			"InvalidBalance",
+			`{
+				"to": "cx059e19601bcb1424884f4ef19addc0a03de9e9cd",
+				"dataType": "call",
+				"data": { "method": "balanceOf" },
+				"stateOverride": {
+					"hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31": { "balance": "10" }
+				}
+			}`,
+			true,
+		},
+		{
+			"InvalidHeight",
+			`{
+				"to": "cx059e19601bcb1424884f4ef19addc0a03de9e9cd",
+				"dataType": "call",
+				"data": { "method": "balanceOf" },
+				"blockOverride": { "height": "16" }
+			}`,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param CallParam
+			assert.NoError(t, json.Unmarshal([]byte(tt.param), &param))
+			if tt.wantErr {
+				assert.Error(t, validator.Validate(&param))
+			} else {
+				assert.NoError(t, validator.Validate(&param))
+			}
+		})
+	}
+}
+
+func TestSimulateTransactionsParamValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	tests := []struct {
+		name    string
+		param   string
+		wantErr bool
+	}{
+		{
+			"Valid",
+			`{
+				"transactions": [
+					{
+						"version": "0x3",
+						"from": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31",
+						"to": "cx059e19601bcb1424884f4ef19addc0a03de9e9cd",
+						"timestamp": "0x563a6cf330136",
+						"nid": "0x3",
+						"dataType": "call",
+						"data": { "method": "approve" }
+					},
+					{
+						"version": "0x3",
+						"from": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31",
+						"to": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31",
+						"value": "0x10",
+						"timestamp": "0x563a6cf330136",
+						"nid": "0x3"
+					}
+				],
+				"balanceChanges": "0x1"
+			}`,
+			false,
+		},
+		{
+			"NoTransactions",
+			`{ "transactions": [] }`,
+			true,
+		},
+		{
+			"InvalidTransaction",
+			`{
+				"transactions": [
+					{
+						"version": "0x3",
+						"from": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31",
+						"to": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31",
+						"nid": "0x3"
+					}
+				]
+			}`,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param SimulateTransactionsParam
+			assert.NoError(t, json.Unmarshal([]byte(tt.param), &param))
+			if tt.wantErr {
+				assert.Error(t, validator.Validate(&param))
+			} else {
+				assert.NoError(t, validator.Validate(&param))
+			}
+		})
+	}
+}
+
+func TestProofStateParamValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	tests := []struct {
+		name    string
+		param   string
+		wantErr bool
+	}{
+		{
+			"Valid",
+			`{
+				"hash": "0xc7fae616bd1d377a92c48a35e33e7a072e5e2be155c000088dbdd42a3e31bb74",
+				"address": "cx059e19601bcb1424884f4ef19addc0a03de9e9cd",
+				"keys": [ "0x0102" ]
+			}`,
+			false,
+		},
+		{
+			"NoKeys",
+			`{
+				"hash": "0xc7fae616bd1d377a92c48a35e33e7a072e5e2be155c000088dbdd42a3e31bb74",
+				"address": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31"
+			}`,
+			false,
+		},
+		{
+			"EmptyKey",
+			`{
+				"hash": "0xc7fae616bd1d377a92c48a35e33e7a072e5e2be155c000088dbdd42a3e31bb74",
+				"address": "cx059e19601bcb1424884f4ef19addc0a03de9e9cd",
+				"keys": [ "0x" ]
+			}`,
+			true,
+		},
+		{
+			"NoAddress",
+			`{
+				"hash": "0xc7fae616bd1d377a92c48a35e33e7a072e5e2be155c000088dbdd42a3e31bb74"
+			}`,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param ProofStateParam
+			assert.NoError(t, json.Unmarshal([]byte(tt.param), &param))
+			if tt.wantErr {
+				assert.Error(t, validator.Validate(&param))
+			} else {
+				assert.NoError(t, validator.Validate(&param))
+			}
+		})
+	}
+}
@@ -172,6 +172,63 @@ func (wm *wsSessionManager) initSession(ctx echo.Context, reqPtr interface{}) (*
 	return wss, nil
 }
 
+// wsRequestError is an error on the request of the session.
+type wsRequestError struct {
+	code jsonrpc.ErrorCode
+	msg  string
+}
+
+func newWSRequestError(code jsonrpc.ErrorCode, msg string) *wsRequestError {
+	return &wsRequestError{code: code, msg: msg}
+}
+
+func (e *wsRequestError) Error() string {
+	return e.msg
+}
+
+func (e *wsRequestError) ToRPCError() *jsonrpc.Error {
+	return &jsonrpc.Error{Code: e.code, Message: e.msg}
+}
+
+type wsWriter interface {
+	WriteJSON(v interface{}) error
+}
+
+// wsNotifier sends notifications with wsWriter until it fails to send
+// or it receives an error from ech.
+type wsNotifier interface {
+	AckWindow() *ackWindow
+	Run(w wsWriter, ech <-chan error) error
+}
+
+// runNotifierSession runs a session sending notifications with the notifier
+// made by newNotifier for the request.
+func (wm *wsSessionManager) runNotifierSession(
+	ctx echo.Context, reqPtr interface{},
+	newNotifier func(chain module.Chain) (wsNotifier, *wsRequestError),
+) error {
+	wss, err := wm.initSession(ctx, reqPtr)
+	if err != nil {
+		return err
+	}
+	defer wm.StopSession(wss)
+
+	n, rerr := newNotifier(wss.chain)
+	if rerr != nil {
+		_ = wss.response(int(rerr.code), rerr.msg)
+		return nil
+	}
+
+	_ = wss.response(0, "")
+
+	ech := make(chan error, 1)
+	wss.RunAckLoop(ech, n.AckWindow())
+
+	err = n.Run(wss, ech)
+	wm.logger.Warnf("%+v\n", err)
+	return nil
+}
+
 func (wm *wsSessionManager) chain(ctx echo.Context) (module.Chain, error) {
 	c, ok := ctx.Get("chain").(module.Chain)
 	if !ok {
@@ -223,6 +280,23 @@ func (wss *wsSession) RunLoop(ech chan<- error) {
 	}
 }
 
+// RunAckLoop runs read loop handling acknowledgements from the client.
+// If aw is nil, then it works same as RunLoop.
+func (wss *wsSession) RunAckLoop(ech chan<- error, aw *ackWindow) {
+	if aw == nil {
+		wss.RunLoop(ech)
+		return
+	}
+	wss.lock.Lock()
+	defer wss.lock.Unlock()
+
+	if wss.c != nil {
+		go readAckLoop(wss.c, ech, aw)
+	} else {
+		ech <- errors.New("AlreadyClosed")
+	}
+}
+
 const (
 	DefaultWSMaxSession   = 10
 	DefaultWSMaxReadLimit = 16 * 1024 // 16kB
@@ -6,14 +6,17 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
 )
 
 type BlockRequest struct {
-	Height       common.HexInt64 `json:"height"`
-	EventFilters []*EventFilter  `json:"eventFilters,omitempty"`
-	Logs         common.HexBool  `json:"logs,omitempty"`
+	Height       common.HexInt64     `json:"height"`
+	EventFilters []*EventFilter      `json:"eventFilters,omitempty"`
+	Logs         common.HexBool      `json:"logs,omitempty"`
+	Cursor       *NotificationCursor `json:"cursor,omitempty"`
+	AckWindow    common.HexInt32     `json:"ackWindow,omitempty"`
 	bn           BlockNotification
 }
 
@@ -23,40 +26,66 @@ type BlockNotification struct {
 	Indexes [][]common.HexInt32   `json:"indexes,omitempty"`
 	Events  [][][]common.HexInt32 `json:"events,omitempty"`
 	Logs    [][][]module.EventLog `json:"logs,omitempty"`
+	Cursor  *NotificationCursor   `json:"cursor"`
 }
 
 func (wm *wsSessionManager) RunBlockSession(ctx echo.Context) error {
 	var br BlockRequest
-	wss, err := wm.initSession(ctx, &br)
-	if err != nil {
-		return err
-	}
-	defer wm.StopSession(wss)
+	return wm.runNotifierSession(ctx, &br, func(chain module.Chain) (wsNotifier, *wsRequestError) {
+		return newBlockNotifier(chain, &br, wm.logger)
+	})
+}
+
+type blockNotifier struct {
+	logger log.Logger
+	bm     module.BlockManager
+	sm     module.ServiceManager
+	req    *BlockRequest
+	aw     *ackWindow
+	height int64
+}
 
+func newBlockNotifier(chain module.Chain, br *BlockRequest, logger log.Logger) (*blockNotifier, *wsRequestError) {
 	if err := br.Compile(); err != nil {
-		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams), err.Error())
-		return nil
+		return nil, newWSRequestError(jsonrpc.ErrorCodeInvalidParams, err.Error())
+	}
+
+	aw, err := newAckWindowFor(br.AckWindow)
+	if err != nil {
+		return nil, newWSRequestError(jsonrpc.ErrorCodeInvalidParams, err.Error())
 	}
 
-	bm := wss.chain.BlockManager()
-	sm := wss.chain.ServiceManager()
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
 	if bm == nil || sm == nil {
-		_ = wss.response(int(jsonrpc.ErrorCodeServer), "Stopped")
-		return nil
+		return nil, newWSRequestError(jsonrpc.ErrorCodeServer, "Stopped")
 	}
 
 	h := br.Height.Value
-	if gh := wss.chain.GenesisStorage().Height(); gh > h {
-		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams),
+	if br.Cursor != nil {
+		h = br.Cursor.Height.Value + 1
+	}
+	if gh := chain.GenesisStorage().Height(); gh > h {
+		return nil, newWSRequestError(jsonrpc.ErrorCodeInvalidParams,
 			fmt.Sprintf("given height(%d) is lower than genesis height(%d)", h, gh))
-		return nil
 	}
+	return &blockNotifier{
+		logger: logger,
+		bm:     bm,
+		sm:     sm,
+		req:    br,
+		aw:     aw,
+		height: h,
+	}, nil
+}
 
-	_ = wss.response(0, "")
-
-	ech := make(chan error, 1)
-	wss.RunLoop(ech)
+func (n *blockNotifier) AckWindow() *ackWindow {
+	return n.aw
+}
 
+func (n *blockNotifier) Run(w wsWriter, ech <-chan error) error {
+	br, aw, h := n.req, n.aw, n.height
+	var err error
 	var bch <-chan module.Block
 	indexes := make([][]common.HexInt32, len(br.EventFilters))
 	events := make([][][]common.HexInt32, len(br.EventFilters))
@@ -69,7 +98,7 @@ func (wm *wsSessionManager) RunBlockSession(ctx echo.Context) error {
 	var rl module.ReceiptList
 loop:
 	for {
-		bch, err = bm.WaitForBlock(h)
+		bch, err = n.bm.WaitForBlock(h)
 		if err != nil {
 			break loop
 		}
@@ -103,9 +132,9 @@ loop:
 			}
 			lb := blk.LogsBloom()
 			for i, f := range br.EventFilters {
-				if lb.Contain(f.lb) {
+				if lb.Contain(f.LogsBloom()) {
 					if rl == nil {
-						rl, err = sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
+						rl, err = n.sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
 						if err != nil {
 							break loop
 						}
@@ -134,15 +163,20 @@ loop:
 					}
 				}
 			}
-			if err = wss.WriteJSON(&br.bn); err != nil {
-				wm.logger.Infof("fail to write json BlockNotification err:%+v\n", err)
+			br.bn.Cursor = NewBlockCursor(h)
+			if aw != nil {
+				if err = aw.Reserve(br.bn.Cursor, ech); err != nil {
+					break loop
+				}
+			}
+			if err = w.WriteJSON(&br.bn); err != nil {
+				n.logger.Infof("fail to write json BlockNotification err:%+v\n", err)
 				break loop
 			}
 		}
 		h++
 	}
-	wm.logger.Warnf("%+v\n", err)
-	return nil
+	return err
 }
 
 func (r *BlockRequest) Compile() error {
@@ -236,6 +236,7 @@ func TestWsSessionManager_RunBlockSession(t *testing.T) {
 	assert.Equal(t, &BlockNotification{
 		Hash:   testHeightToBlockID(1),
 		Height: common.HexInt64{Value: 1},
+		Cursor: NewBlockCursor(1),
 	}, n1)
 
 	// wait a message (1)
@@ -245,6 +246,7 @@ func TestWsSessionManager_RunBlockSession(t *testing.T) {
 	assert.Equal(t, &BlockNotification{
 		Hash:   testHeightToBlockID(2),
 		Height: common.HexInt64{Value: 2},
+		Cursor: NewBlockCursor(2),
 		Indexes: [][]common.HexInt32{
 			{
 				{0},
@@ -268,6 +270,7 @@ func TestWsSessionManager_RunBlockSession(t *testing.T) {
 	assert.Equal(t, &BlockNotification{
 		Hash:   testHeightToBlockID(3),
 		Height: common.HexInt64{Value: 3},
+		Cursor: NewBlockCursor(3),
 		Indexes: [][]common.HexInt32{
 			{
 				{0}, {1},
@@ -7,6 +7,7 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
 )
@@ -26,45 +27,60 @@ type BTPNotification struct {
 
 func (wm *wsSessionManager) RunBtpSession(ctx echo.Context) error {
 	var br BTPRequest
-	wss, err := wm.initSession(ctx, &br)
-	if err != nil {
-		return err
-	}
-	defer wm.StopSession(wss)
+	return wm.runNotifierSession(ctx, &br, func(chain module.Chain) (wsNotifier, *wsRequestError) {
+		return newBTPNotifier(chain, &br, wm.logger)
+	})
+}
+
+type btpNotifier struct {
+	logger log.Logger
+	bm     module.BlockManager
+	sm     module.ServiceManager
+	cs     module.Consensus
+	req    *BTPRequest
+}
 
-	bm := wss.chain.BlockManager()
-	sm := wss.chain.ServiceManager()
-	cs := wss.chain.Consensus()
+func newBTPNotifier(chain module.Chain, br *BTPRequest, logger log.Logger) (*btpNotifier, *wsRequestError) {
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	cs := chain.Consensus()
 	if bm == nil || sm == nil || cs == nil {
-		_ = wss.response(int(jsonrpc.ErrorCodeServer), "Stopped")
-		return nil
+		return nil, newWSRequestError(jsonrpc.ErrorCodeServer, "Stopped")
 	}
 
 	h := br.Height.Value
-	if gh := wss.chain.GenesisStorage().Height(); gh > h {
-		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams),
+	if gh := chain.GenesisStorage().Height(); gh > h {
+		return nil, newWSRequestError(jsonrpc.ErrorCodeInvalidParams,
 			fmt.Sprintf("given height(%d) is lower than genesis height(%d)", h, gh))
-		return nil
 	}
+	return &btpNotifier{
+		logger: logger,
+		bm:     bm,
+		sm:     sm,
+		cs:     cs,
+		req:    br,
+	}, nil
+}
 
-	_ = wss.response(0, "")
-
-	ech := make(chan error, 1)
-	wss.RunLoop(ech)
+func (n *btpNotifier) AckWindow() *ackWindow {
+	return nil
+}
 
+func (n *btpNotifier) Run(w wsWriter, ech <-chan error) error {
+	br, h := n.req, n.req.Height.Value
 	var bch <-chan module.Block
 
-	block, err := bm.GetLastBlock()
-	nw, err := sm.BTPNetworkFromResult(block.Result(), br.NetworkId.Value)
+	block, err := n.bm.GetLastBlock()
+	nw, err := n.sm.BTPNetworkFromResult(block.Result(), br.NetworkId.Value)
 	if err != nil {
-		wm.logger.Infof("not found nid=%d height=%d, err:%+v\n", br.NetworkId.Value, h, err)
-		return nil
+		n.logger.Infof("not found nid=%d height=%d, err:%+v\n", br.NetworkId.Value, h, err)
+		return err
 	}
 
 	var pn ProgressNotification;
 loop:
 	for {
-		bch, err = bm.WaitForBlock(h)
+		bch, err = n.bm.WaitForBlock(h)
 		if err != nil {
 			break loop
 		}
@@ -77,11 +93,13 @@ loop:
 			}
 			msgSent := 0
 			if nw.StartHeight()+1 <= h {
-				nw, err := sm.BTPNetworkFromResult(blk.Result(), br.NetworkId.Value)
+				nw, err := n.sm.BTPNetworkFromResult(blk.Result(), br.NetworkId.Value)
 				if !nw.Open() {
-					wm.logger.Infof("network is closed (height=%d, err:%+v)\n", h, err)
-					_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams),
-						fmt.Sprintf("network is closed ( height(%d) , networkId(%d)", h, br.NetworkId))
+					n.logger.Infof("network is closed (height=%d, err:%+v)\n", h, err)
+					_ = w.WriteJSON(&WSResponse{
+						Code:    int(jsonrpc.ErrorCodeInvalidParams),
+						Message: fmt.Sprintf("network is closed ( height(%d) , networkId(%d)", h, br.NetworkId),
+					})
 					break loop
 				}
 
@@ -92,15 +110,15 @@ loop:
 					flag = module.FlagBTPBlockHeader
 				}
 
-				btpBlock, proof, err := cs.GetBTPBlockHeaderAndProof(blk, br.NetworkId.Value, flag)
+				btpBlock, proof, err := n.cs.GetBTPBlockHeaderAndProof(blk, br.NetworkId.Value, flag)
 				if err == nil {
 					br.bn.Header = base64.StdEncoding.EncodeToString(btpBlock.HeaderBytes())
 					if flag == module.FlagBTPBlockHeader|module.FlagBTPBlockProof {
 						br.bn.Proof = base64.StdEncoding.EncodeToString(proof)
 					}
 
-					if err = wss.WriteJSON(&br.bn); err != nil {
-						wm.logger.Infof("fail to write json BtpNotification err:%+v\n", err)
+					if err = w.WriteJSON(&br.bn); err != nil {
+						n.logger.Infof("fail to write json BtpNotification err:%+v\n", err)
 						break loop
 					}
 					msgSent += 1
@@ -111,8 +129,8 @@ loop:
 				last := pn.Progress.Value
 				if last == 0 || (h-last) >= pi || msgSent > 0 {
 					pn.Progress.Value = h
-					if err := wss.WriteJSON(&pn); err != nil {
-						wm.logger.Infof("fail to write json ProgressNotification(height=%d)", h)
+					if err := w.WriteJSON(&pn); err != nil {
+						n.logger.Infof("fail to write json ProgressNotification(height=%d)", h)
 						break loop
 					}
 				}
@@ -120,6 +138,5 @@ loop:
 		}
 		h++
 	}
-	wm.logger.Warnf("%+v\n", err)
-	return nil
+	return err
 }
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"sync/atomic"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+)
+
+const (
+	DefaultWSRoundEventBuffer = 256
+)
+
+type RoundEventRequest struct {
+}
+
+type RoundEventNotification struct {
+	*v3.RoundEventInfo
+	Missed *common.HexInt32 `json:"missed,omitempty"`
+}
+
+func (wm *wsSessionManager) RunRoundEventSession(ctx echo.Context) error {
+	var rr RoundEventRequest
+	return wm.runNotifierSession(ctx, &rr, func(chain module.Chain) (wsNotifier, *wsRequestError) {
+		return newRoundEventNotifier(chain, wm.logger)
+	})
+}
+
+type roundEventNotifier struct {
+	logger log.Logger
+	rsi    consensus.RoundStateInspector
+}
+
+func newRoundEventNotifier(chain module.Chain, logger log.Logger) (*roundEventNotifier, *wsRequestError) {
+	if chain.Consensus() == nil {
+		return nil, newWSRequestError(jsonrpc.ErrorCodeServer, "Stopped")
+	}
+	rsi, err := consensus.RoundStateInspectorOf(chain)
+	if err != nil {
+		return nil, newWSRequestError(jsonrpc.ErrorCodeServer, err.Error())
+	}
+	return &roundEventNotifier{
+		logger: logger,
+		rsi:    rsi,
+	}, nil
+}
+
+func (n *roundEventNotifier) AckWindow() *ackWindow {
+	return nil
+}
+
+func (n *roundEventNotifier) Run(w wsWriter, ech <-chan error) error {
+	// the listener is called in the lock of consensus, so it only queues
+	// the event and counts missed ones if the queue is full.
+	evch := make(chan *consensus.RoundEvent, DefaultWSRoundEventBuffer)
+	var missed int32
+	remove := n.rsi.AddRoundEventListener(func(ev *consensus.RoundEvent) {
+		select {
+		case evch <- ev:
+		default:
+			atomic.AddInt32(&missed, 1)
+		}
+	})
+	defer remove()
+
+	for {
+		select {
+		case err := <-ech:
+			return err
+		case ev := <-evch:
+			rn := &RoundEventNotification{
+				RoundEventInfo: v3.NewRoundEventInfo(ev),
+			}
+			if cnt := atomic.SwapInt32(&missed, 0); cnt > 0 {
+				rn.Missed = &common.HexInt32{Value: cnt}
+			}
+			if err := w.WriteJSON(rn); err != nil {
+				n.logger.Infof("fail to write json RoundEventNotification err:%+v\n", err)
+				return err
+			}
+		}
+	}
+}
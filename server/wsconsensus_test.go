@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/consensus"
+)
+
+type testRoundStateInspector struct {
+	consensus.RoundStateInspector
+	listeners consensus.RoundEventListeners
+}
+
+func (rsi *testRoundStateInspector) AddRoundEventListener(l func(ev *consensus.RoundEvent)) func() {
+	return rsi.listeners.Add(l)
+}
+
+func TestRoundEventNotifier_Run(t *testing.T) {
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	rsi := new(testRoundStateInspector)
+	n := &roundEventNotifier{
+		logger: logger,
+		rsi:    rsi,
+	}
+	w := make(testWSWriter, 1)
+	ech := make(chan error, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- n.Run(w, ech)
+	}()
+
+	addr := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	ev := &consensus.RoundEvent{
+		Height:     10,
+		Round:      1,
+		Step:       "prevoteWait",
+		Reason:     "prevote",
+		Timestamp:  time.UnixMicro(1000),
+		Proposer:   addr,
+		Prevotes:   "1101",
+		Precommits: "0000",
+	}
+	var bs []byte
+	for bs == nil {
+		rsi.listeners.Notify(ev)
+		select {
+		case bs = <-w:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	var rn map[string]interface{}
+	assert.NoError(t, json.Unmarshal(bs, &rn))
+	assert.Equal(t, map[string]interface{}{
+		"height":     "0xa",
+		"round":      "0x1",
+		"step":       "prevoteWait",
+		"reason":     "prevote",
+		"timestamp":  "0x3e8",
+		"proposer":   addr.String(),
+		"prevotes":   "1101",
+		"precommits": "0000",
+	}, rn)
+
+	ech <- errors.New("closed")
+	assert.Error(t, <-done)
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	DefaultWSMaxAckWindow = 1024
+)
+
+// NotificationCursor is the position of the notification.
+// Notification of block session has only height, and notification of
+// event session has height, index of the result and index of the last
+// event in the notification.
+// A client may resume the session right after the notification by
+// sending the cursor on the request.
+type NotificationCursor struct {
+	Height common.HexInt64  `json:"height"`
+	Index  *common.HexInt32 `json:"index,omitempty"`
+	Event  *common.HexInt32 `json:"event,omitempty"`
+}
+
+func NewBlockCursor(height int64) *NotificationCursor {
+	return &NotificationCursor{
+		Height: common.HexInt64{Value: height},
+	}
+}
+
+func NewEventCursor(height int64, index, event int32) *NotificationCursor {
+	return &NotificationCursor{
+		Height: common.HexInt64{Value: height},
+		Index:  &common.HexInt32{Value: index},
+		Event:  &common.HexInt32{Value: event},
+	}
+}
+
+func hexInt32OrNegative(v *common.HexInt32) int32 {
+	if v == nil {
+		return -1
+	}
+	return v.Value
+}
+
+func (c *NotificationCursor) IndexValue() int32 {
+	return hexInt32OrNegative(c.Index)
+}
+
+func (c *NotificationCursor) EventValue() int32 {
+	return hexInt32OrNegative(c.Event)
+}
+
+// Compare returns negative value if c is before c2, zero if they are
+// same, and positive value if c is after c2.
+func (c *NotificationCursor) Compare(c2 *NotificationCursor) int {
+	if d := c.Height.Value - c2.Height.Value; d != 0 {
+		return sign(d)
+	}
+	if d := int64(c.IndexValue()) - int64(c2.IndexValue()); d != 0 {
+		return sign(d)
+	}
+	return sign(int64(c.EventValue()) - int64(c2.EventValue()))
+}
+
+func sign(v int64) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// WSAck is sent by the client to acknowledge notifications.
+// It acknowledges all notifications up to the cursor.
+type WSAck struct {
+	Ack *NotificationCursor `json:"ack"`
+}
+
+// ackWindow limits number of notifications sent but not acknowledged.
+type ackWindow struct {
+	lock    sync.Mutex
+	size    int
+	pending []*NotificationCursor
+	signal  chan struct{}
+}
+
+func newAckWindow(size int) *ackWindow {
+	return &ackWindow{
+		size:   size,
+		signal: make(chan struct{}, 1),
+	}
+}
+
+func (w *ackWindow) tryReserve(c *NotificationCursor) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if len(w.pending) >= w.size {
+		return false
+	}
+	w.pending = append(w.pending, c)
+	return true
+}
+
+// Reserve reserves a slot for the notification at the cursor. It blocks
+// until the client acknowledges enough notifications or the session ends.
+func (w *ackWindow) Reserve(c *NotificationCursor, ech <-chan error) error {
+	for !w.tryReserve(c) {
+		select {
+		case <-w.signal:
+		case err := <-ech:
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *ackWindow) Ack(c *NotificationCursor) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	idx := 0
+	for idx < len(w.pending) && w.pending[idx].Compare(c) <= 0 {
+		w.pending[idx] = nil
+		idx++
+	}
+	if idx == 0 {
+		return
+	}
+	w.pending = w.pending[idx:]
+	select {
+	case w.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (w *ackWindow) Len() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return len(w.pending)
+}
+
+func readAckLoop(c WebSocketConn, ech chan<- error, aw *ackWindow) {
+	for {
+		_, bs, err := c.ReadMessage()
+		if err != nil {
+			ech <- err
+			break
+		}
+		var msg WSAck
+		if err := json.Unmarshal(bs, &msg); err != nil || msg.Ack == nil {
+			ech <- errors.IllegalArgumentError.Errorf("InvalidAck(msg=%q)", bs)
+			break
+		}
+		aw.Ack(msg.Ack)
+	}
+}
+
+// newAckWindowFor returns ackWindow for the requested size.
+// It returns nil if the size is zero, which means acknowledgement is
+// not used.
+func newAckWindowFor(size common.HexInt32) (*ackWindow, error) {
+	if size.Value == 0 {
+		return nil, nil
+	}
+	if size.Value < 0 || size.Value > DefaultWSMaxAckWindow {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidAckWindow(size=%d,max=%d)", size.Value, DefaultWSMaxAckWindow)
+	}
+	return newAckWindow(int(size.Value)), nil
+}
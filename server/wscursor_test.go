@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+)
+
+func TestNotificationCursor_Compare(t *testing.T) {
+	tests := []struct {
+		name string
+		c1   *NotificationCursor
+		c2   *NotificationCursor
+		want int
+	}{
+		{"SameBlock", NewBlockCursor(3), NewBlockCursor(3), 0},
+		{"LowerBlock", NewBlockCursor(2), NewBlockCursor(3), -1},
+		{"HigherBlock", NewBlockCursor(4), NewBlockCursor(3), 1},
+		{"BlockAndEvent", NewBlockCursor(3), NewEventCursor(3, 0, 0), -1},
+		{"LowerIndex", NewEventCursor(3, 0, 5), NewEventCursor(3, 1, 0), -1},
+		{"HigherEvent", NewEventCursor(3, 1, 2), NewEventCursor(3, 1, 1), 1},
+		{"SameEvent", NewEventCursor(3, 1, 2), NewEventCursor(3, 1, 2), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.c1.Compare(tt.c2))
+		})
+	}
+}
+
+func TestNotificationCursor_JSON(t *testing.T) {
+	bs, err := json.Marshal(NewBlockCursor(0x10))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"height":"0x10"}`, string(bs))
+
+	bs, err = json.Marshal(NewEventCursor(0x10, 1, 2))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"height":"0x10","index":"0x1","event":"0x2"}`, string(bs))
+
+	var c NotificationCursor
+	assert.NoError(t, json.Unmarshal(bs, &c))
+	assert.Equal(t, 0, c.Compare(NewEventCursor(0x10, 1, 2)))
+}
+
+func TestAckWindow(t *testing.T) {
+	aw := newAckWindow(2)
+	ech := make(chan error, 1)
+
+	assert.NoError(t, aw.Reserve(NewBlockCursor(1), ech))
+	assert.NoError(t, aw.Reserve(NewBlockCursor(2), ech))
+	assert.Equal(t, 2, aw.Len())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- aw.Reserve(NewBlockCursor(3), ech)
+	}()
+	select {
+	case <-done:
+		assert.Fail(t, "reserved over the window")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	aw.Ack(NewBlockCursor(1))
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "not released by ack")
+	}
+	assert.Equal(t, 2, aw.Len())
+
+	aw.Ack(NewBlockCursor(3))
+	assert.Equal(t, 0, aw.Len())
+
+	assert.NoError(t, aw.Reserve(NewBlockCursor(4), ech))
+	assert.NoError(t, aw.Reserve(NewBlockCursor(5), ech))
+	ech <- errors.New("closed")
+	assert.Error(t, aw.Reserve(NewBlockCursor(6), ech))
+}
+
+func TestNewAckWindowFor(t *testing.T) {
+	aw, err := newAckWindowFor(common.HexInt32{Value: 0})
+	assert.NoError(t, err)
+	assert.Nil(t, aw)
+
+	aw, err = newAckWindowFor(common.HexInt32{Value: 10})
+	assert.NoError(t, err)
+	assert.NotNil(t, aw)
+
+	_, err = newAckWindowFor(common.HexInt32{Value: -1})
+	assert.Error(t, err)
+	_, err = newAckWindowFor(common.HexInt32{Value: DefaultWSMaxAckWindow + 1})
+	assert.Error(t, err)
+}
+
+func TestSkipDeliveredEvents(t *testing.T) {
+	es := []common.HexInt32{{Value: 0}, {Value: 2}, {Value: 3}}
+	c := NewEventCursor(5, 1, 2)
+
+	res, _ := skipDeliveredEvents(c, 6, 0, es, nil)
+	assert.Equal(t, es, res)
+	res, _ = skipDeliveredEvents(c, 5, 2, es, nil)
+	assert.Equal(t, es, res)
+	res, _ = skipDeliveredEvents(c, 5, 0, es, nil)
+	assert.Empty(t, res)
+	res, _ = skipDeliveredEvents(c, 5, 1, es, nil)
+	assert.Equal(t, es[2:], res)
+	res, _ = skipDeliveredEvents(NewEventCursor(5, 1, 3), 5, 1, es, nil)
+	assert.Empty(t, res)
+}
@@ -0,0 +1,389 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+const (
+	DefaultWSMaxSubscription = 32
+)
+
+const (
+	MethodSubscribe    = "subscribe"
+	MethodUnsubscribe  = "unsubscribe"
+	MethodAck          = "ack"
+	MethodNotification = "notification"
+	MethodEnd          = "subscriptionEnd"
+)
+
+type SubscribeParam struct {
+	Type   string          `json:"type"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type SubscriptionParam struct {
+	Subscription common.HexInt64 `json:"subscription"`
+}
+
+type AckParam struct {
+	Subscription common.HexInt64     `json:"subscription"`
+	Cursor       *NotificationCursor `json:"cursor"`
+}
+
+// SubscriptionNotification is a JSON-RPC notification delivering a
+// notification of the subscription.
+type SubscriptionNotification struct {
+	Version string             `json:"jsonrpc"`
+	Method  string             `json:"method"`
+	Params  SubscriptionResult `json:"params"`
+}
+
+type SubscriptionResult struct {
+	Subscription common.HexInt64 `json:"subscription"`
+	Result       interface{}     `json:"result"`
+}
+
+// SubscriptionEndNotification is a JSON-RPC notification sent when the
+// subscription is terminated by the server.
+type SubscriptionEndNotification struct {
+	Version string                `json:"jsonrpc"`
+	Method  string                `json:"method"`
+	Params  SubscriptionEndResult `json:"params"`
+}
+
+type SubscriptionEndResult struct {
+	Subscription common.HexInt64 `json:"subscription"`
+	Error        *jsonrpc.Error  `json:"error,omitempty"`
+}
+
+// wsNotifierFactory makes a notifier for the request of the subscription.
+type wsNotifierFactory func(chain module.Chain, raw json.RawMessage, logger log.Logger) (wsNotifier, *wsRequestError)
+
+var wsNotifierFactories = map[string]wsNotifierFactory{
+	"block": func(chain module.Chain, raw json.RawMessage, logger log.Logger) (wsNotifier, *wsRequestError) {
+		var br BlockRequest
+		if err := decodeSubscribeRequest(raw, &br); err != nil {
+			return nil, err
+		}
+		return newBlockNotifier(chain, &br, logger)
+	},
+	"event": func(chain module.Chain, raw json.RawMessage, logger log.Logger) (wsNotifier, *wsRequestError) {
+		var er EventRequest
+		if err := decodeSubscribeRequest(raw, &er); err != nil {
+			return nil, err
+		}
+		return newEventNotifier(chain, &er, logger)
+	},
+	"btp": func(chain module.Chain, raw json.RawMessage, logger log.Logger) (wsNotifier, *wsRequestError) {
+		var br BTPRequest
+		if err := decodeSubscribeRequest(raw, &br); err != nil {
+			return nil, err
+		}
+		return newBTPNotifier(chain, &br, logger)
+	},
+	"transaction": func(chain module.Chain, raw json.RawMessage, logger log.Logger) (wsNotifier, *wsRequestError) {
+		var pr PendingTxRequest
+		if err := decodeSubscribeRequest(raw, &pr); err != nil {
+			return nil, err
+		}
+		return newPendingTxNotifier(chain, &pr, logger)
+	},
+}
+
+func decodeSubscribeRequest(raw json.RawMessage, reqPtr interface{}) *wsRequestError {
+	if len(raw) == 0 {
+		raw = []byte("{}")
+	}
+	jd := json.NewDecoder(bytes.NewBuffer(raw))
+	jd.DisallowUnknownFields()
+	if err := jd.Decode(reqPtr); err != nil {
+		return newWSRequestError(jsonrpc.ErrorCodeInvalidParams, "bad subscription request")
+	}
+	return nil
+}
+
+type wsSubscription struct {
+	id  int64
+	wss *wsSession
+	ech chan error
+	aw  *ackWindow
+}
+
+func (s *wsSubscription) WriteJSON(v interface{}) error {
+	return s.wss.WriteJSON(&SubscriptionNotification{
+		Version: jsonrpc.Version,
+		Method:  MethodNotification,
+		Params: SubscriptionResult{
+			Subscription: common.HexInt64{Value: s.id},
+			Result:       v,
+		},
+	})
+}
+
+// WriteEnd notifies the end of the subscription with the error
+// terminating it.
+func (s *wsSubscription) WriteEnd(err error) error {
+	var rerr *jsonrpc.Error
+	if err != nil {
+		if je, ok := err.(*jsonrpc.Error); ok {
+			rerr = je
+		} else {
+			rerr = jsonrpc.ErrorCodeServer.New(err.Error())
+		}
+	}
+	return s.wss.WriteJSON(&SubscriptionEndNotification{
+		Version: jsonrpc.Version,
+		Method:  MethodEnd,
+		Params: SubscriptionEndResult{
+			Subscription: common.HexInt64{Value: s.id},
+			Error:        rerr,
+		},
+	})
+}
+
+func (s *wsSubscription) Stop(err error) {
+	select {
+	case s.ech <- err:
+	default:
+	}
+}
+
+// wsMuxSession handles JSON-RPC requests including subscriptions over
+// a websocket.
+type wsMuxSession struct {
+	lock   sync.Mutex
+	wss    *wsSession
+	ctx    *jsonrpc.Context
+	mr     *jsonrpc.MethodRepository
+	logger log.Logger
+	calls  chan struct{}
+	subs   map[int64]*wsSubscription
+	lastID int64
+}
+
+// RunMuxSession returns the handler for websocket serving JSON-RPC
+// requests of the repository along with subscribe and unsubscribe.
+func (wm *wsSessionManager) RunMuxSession(mr *jsonrpc.MethodRepository) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		chain, err := wm.chain(ctx)
+		if err != nil {
+			return err
+		}
+
+		c, err := wm.upgrader.Upgrade(ctx)
+		if err != nil {
+			return err
+		}
+
+		wss := wm.NewSession(c, chain)
+		if wss == nil {
+			_ = c.WriteJSON(&jsonrpc.Response{
+				Version: jsonrpc.Version,
+				Error:   jsonrpc.ErrorLackOfResource.New("too many monitor"),
+			})
+			_ = c.Close()
+			return errors.New("too many monitor")
+		}
+		defer wm.StopSession(wss)
+
+		jctx := jsonrpc.NewContext(ctx)
+		limit := jctx.BatchLimit()
+		if limit < 1 {
+			limit = 1
+		}
+		ms := &wsMuxSession{
+			wss:    wss,
+			ctx:    jctx,
+			mr:     mr,
+			logger: wm.logger,
+			calls:  make(chan struct{}, limit),
+			subs:   make(map[int64]*wsSubscription),
+		}
+		defer ms.stopAll(errors.New("SessionClosed"))
+
+		for {
+			_, msg, err := c.ReadMessage()
+			if err != nil {
+				wm.logger.Debugf("fail to read message err=%+v", err)
+				return nil
+			}
+			ms.handle(msg)
+		}
+	}
+}
+
+func (ms *wsMuxSession) respond(id interface{}, result interface{}, err *jsonrpc.Error) {
+	if id == nil {
+		return
+	}
+	resp := &jsonrpc.Response{
+		Version: jsonrpc.Version,
+		ID:      id,
+		Result:  result,
+		Error:   err,
+	}
+	if werr := ms.wss.WriteJSON(resp); werr != nil {
+		ms.logger.Infof("fail to write json Response err:%+v", werr)
+	}
+}
+
+func (ms *wsMuxSession) handle(msg []byte) {
+	var req jsonrpc.Request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		ms.respond(json.RawMessage("null"), nil, jsonrpc.ErrParse())
+		return
+	}
+	if req.Method == nil {
+		ms.respond(req.ID, nil, jsonrpc.ErrInvalidRequest())
+		return
+	}
+	switch *req.Method {
+	case MethodSubscribe:
+		var param SubscribeParam
+		if err := json.Unmarshal(req.Params, &param); err != nil {
+			ms.respond(req.ID, nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, false))
+			return
+		}
+		sub, n, rerr := ms.subscribe(&param)
+		if rerr != nil {
+			ms.respond(req.ID, nil, rerr.ToRPCError())
+			return
+		}
+		ms.respond(req.ID, &common.HexInt64{Value: sub.id}, nil)
+		go ms.run(sub, n)
+	case MethodUnsubscribe:
+		var param SubscriptionParam
+		if err := json.Unmarshal(req.Params, &param); err != nil {
+			ms.respond(req.ID, nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, false))
+			return
+		}
+		if !ms.unsubscribe(param.Subscription.Value) {
+			ms.respond(req.ID, nil, jsonrpc.ErrorCodeNotFound.Errorf(
+				"NoSubscription(id=%#x)", param.Subscription.Value))
+			return
+		}
+		ms.respond(req.ID, true, nil)
+	case MethodAck:
+		var param AckParam
+		if err := json.Unmarshal(req.Params, &param); err != nil || param.Cursor == nil {
+			ms.respond(req.ID, nil, jsonrpc.ErrInvalidParams())
+			return
+		}
+		if !ms.ack(param.Subscription.Value, param.Cursor) {
+			ms.respond(req.ID, nil, jsonrpc.ErrorCodeNotFound.Errorf(
+				"NoSubscription(id=%#x)", param.Subscription.Value))
+			return
+		}
+		ms.respond(req.ID, true, nil)
+	default:
+		ms.calls <- struct{}{}
+		go func() {
+			defer func() {
+				<-ms.calls
+			}()
+			if resp := ms.mr.HandleMessage(ms.ctx, msg); resp != nil {
+				if err := ms.wss.WriteJSON(resp); err != nil {
+					ms.logger.Infof("fail to write json Response err:%+v", err)
+				}
+			}
+		}()
+	}
+}
+
+func (ms *wsMuxSession) subscribe(param *SubscribeParam) (*wsSubscription, wsNotifier, *wsRequestError) {
+	factory, ok := wsNotifierFactories[param.Type]
+	if !ok {
+		return nil, nil, newWSRequestError(jsonrpc.ErrorCodeInvalidParams, "unknown subscription type")
+	}
+	n, rerr := factory(ms.wss.chain, param.Params, ms.logger)
+	if rerr != nil {
+		return nil, nil, rerr
+	}
+
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	if len(ms.subs) >= DefaultWSMaxSubscription {
+		return nil, nil, newWSRequestError(jsonrpc.ErrorLackOfResource, "too many subscriptions")
+	}
+	ms.lastID++
+	sub := &wsSubscription{
+		id:  ms.lastID,
+		wss: ms.wss,
+		ech: make(chan error, 1),
+		aw:  n.AckWindow(),
+	}
+	ms.subs[sub.id] = sub
+	return sub, n, nil
+}
+
+func (ms *wsMuxSession) run(sub *wsSubscription, n wsNotifier) {
+	err := n.Run(sub, sub.ech)
+	ms.logger.Debugf("subscription(id=%d) finished err=%+v", sub.id, err)
+	// the client is notified only if it's terminated by the server
+	if ms.remove(sub) {
+		if werr := sub.WriteEnd(err); werr != nil {
+			ms.logger.Infof("fail to write json SubscriptionEnd err:%+v", werr)
+		}
+	}
+}
+
+// remove removes the subscription, and returns whether it was still
+// registered (not unsubscribed or stopped by the session).
+func (ms *wsMuxSession) remove(sub *wsSubscription) bool {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	if s, ok := ms.subs[sub.id]; ok && s == sub {
+		delete(ms.subs, sub.id)
+		return true
+	}
+	return false
+}
+
+func (ms *wsMuxSession) unsubscribe(id int64) bool {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	sub, ok := ms.subs[id]
+	if !ok {
+		return false
+	}
+	delete(ms.subs, id)
+	sub.Stop(errors.New("Unsubscribed"))
+	return true
+}
+
+func (ms *wsMuxSession) ack(id int64, c *NotificationCursor) bool {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	sub, ok := ms.subs[id]
+	if !ok {
+		return false
+	}
+	if sub.aw != nil {
+		sub.aw.Ack(c)
+	}
+	return true
+}
+
+func (ms *wsMuxSession) stopAll(err error) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	for id, sub := range ms.subs {
+		sub.Stop(err)
+		delete(ms.subs, id)
+	}
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright 2022 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+type testMuxContext struct {
+	*testContext
+	req *http.Request
+}
+
+func (ctx *testMuxContext) Request() *http.Request {
+	return ctx.req
+}
+
+type testMuxResponse struct {
+	ID     interface{}     `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Params *testMuxResult  `json:"params"`
+	Error  *jsonrpc.Error  `json:"error"`
+}
+
+type testMuxResult struct {
+	Subscription common.HexInt64 `json:"subscription"`
+	Result       json.RawMessage `json:"result"`
+	Error        *jsonrpc.Error  `json:"error"`
+}
+
+func TestWsSessionManager_RunMuxSession(t *testing.T) {
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	cch := make(chan *testWebSocketConn, 1)
+	upgrader := newTestWebsocketUpgrader(func(ctx echo.Context, conn *testWebSocketConn) {
+		cch <- conn
+	})
+	wm := newWSSessionManagerWithUpgrader(logger, 10, upgrader)
+
+	// only the first block is available until the end of the test,
+	// and blocks from 10 can't be waited
+	s1 := make(chan struct{})
+	chain := newTestChain(0,
+		func(h int64) (getBlockFunc, error) {
+			if h >= 10 {
+				return nil, errors.New("NotAvailable")
+			}
+			return func() module.Block {
+				if h > 1 {
+					<-s1
+				}
+				return &testBlock{height: h, result: "empty"}
+			}, nil
+		},
+		blockReceipts{"empty": testReceiptList{}},
+	)
+	ctx := &testMuxContext{
+		testContext: newTestContext(chain),
+		req:         httptest.NewRequest(http.MethodGet, "/api/v3/icon_dex/ws", nil),
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- wm.RunMuxSession(jsonrpc.NewMethodRepository(nil))(ctx)
+	}()
+	conn := <-cch
+
+	call := func(req map[string]interface{}) *testMuxResponse {
+		req["jsonrpc"] = jsonrpc.Version
+		assert.NoError(t, conn.clientWriteJSON(req))
+		bs, err := conn.clientRead()
+		assert.NoError(t, err)
+		var res testMuxResponse
+		assert.NoError(t, json.Unmarshal(bs, &res))
+		return &res
+	}
+
+	res := call(map[string]interface{}{
+		"id":     1,
+		"method": MethodSubscribe,
+		"params": map[string]interface{}{
+			"type":   "unknown",
+			"params": map[string]interface{}{},
+		},
+	})
+	assert.NotNil(t, res.Error)
+	assert.Equal(t, jsonrpc.ErrorCodeInvalidParams, res.Error.Code)
+
+	res = call(map[string]interface{}{
+		"id":     2,
+		"method": MethodSubscribe,
+		"params": map[string]interface{}{
+			"type":   "block",
+			"params": map[string]interface{}{"height": "0x1"},
+		},
+	})
+	assert.Nil(t, res.Error)
+	assert.JSONEq(t, `"0x1"`, string(res.Result))
+
+	bs, err := conn.clientRead()
+	assert.NoError(t, err)
+	var noti testMuxResponse
+	assert.NoError(t, json.Unmarshal(bs, &noti))
+	assert.Equal(t, MethodNotification, noti.Method)
+	assert.Nil(t, noti.ID)
+	assert.EqualValues(t, 1, noti.Params.Subscription.Value)
+	var bn BlockNotification
+	assert.NoError(t, json.Unmarshal(noti.Params.Result, &bn))
+	assert.EqualValues(t, 1, bn.Height.Value)
+	assert.Equal(t, NewBlockCursor(1), bn.Cursor)
+
+	res = call(map[string]interface{}{
+		"id":     3,
+		"method": MethodUnsubscribe,
+		"params": map[string]interface{}{"subscription": "0x1"},
+	})
+	assert.EqualValues(t, 3, res.ID)
+	assert.Nil(t, res.Error)
+	assert.JSONEq(t, `true`, string(res.Result))
+
+	res = call(map[string]interface{}{
+		"id":     4,
+		"method": MethodUnsubscribe,
+		"params": map[string]interface{}{"subscription": "0x1"},
+	})
+	assert.NotNil(t, res.Error)
+	assert.Equal(t, jsonrpc.ErrorCodeNotFound, res.Error.Code)
+
+	res = call(map[string]interface{}{
+		"id":     5,
+		"method": MethodAck,
+		"params": map[string]interface{}{"subscription": "0x1"},
+	})
+	assert.NotNil(t, res.Error)
+	assert.Equal(t, jsonrpc.ErrorCodeInvalidParams, res.Error.Code)
+
+	// the subscription terminated by the server is notified
+	res = call(map[string]interface{}{
+		"id":     6,
+		"method": MethodSubscribe,
+		"params": map[string]interface{}{
+			"type":   "block",
+			"params": map[string]interface{}{"height": "0xa"},
+		},
+	})
+	assert.Nil(t, res.Error)
+	assert.JSONEq(t, `"0x2"`, string(res.Result))
+
+	bs, err = conn.clientRead()
+	assert.NoError(t, err)
+	var end testMuxResponse
+	assert.NoError(t, json.Unmarshal(bs, &end))
+	assert.Equal(t, MethodEnd, end.Method)
+	assert.EqualValues(t, 2, end.Params.Subscription.Value)
+	assert.NotNil(t, end.Params.Error)
+
+	res = call(map[string]interface{}{
+		"id":     7,
+		"method": MethodUnsubscribe,
+		"params": map[string]interface{}{"subscription": "0x2"},
+	})
+	assert.NotNil(t, res.Error)
+	assert.Equal(t, jsonrpc.ErrorCodeNotFound, res.Error.Code)
+
+	conn.Close()
+	assert.NoError(t, <-done)
+	close(s1)
+	wm.StopAllSessions()
+}
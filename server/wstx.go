@@ -0,0 +1,100 @@
+package server
+
+import (
+	"sync/atomic"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/service"
+)
+
+const (
+	DefaultWSPendingTxBuffer = 256
+)
+
+type PendingTxRequest struct {
+	Full common.HexBool `json:"full,omitempty"`
+}
+
+type PendingTxNotification struct {
+	Hash        common.HexBytes  `json:"hash"`
+	Transaction interface{}      `json:"transaction,omitempty"`
+	Missed      *common.HexInt32 `json:"missed,omitempty"`
+}
+
+func (wm *wsSessionManager) RunPendingTxSession(ctx echo.Context) error {
+	var pr PendingTxRequest
+	return wm.runNotifierSession(ctx, &pr, func(chain module.Chain) (wsNotifier, *wsRequestError) {
+		return newPendingTxNotifier(chain, &pr, wm.logger)
+	})
+}
+
+type pendingTxNotifier struct {
+	logger log.Logger
+	tpi    service.TxPoolInspector
+	req    *PendingTxRequest
+}
+
+func newPendingTxNotifier(chain module.Chain, pr *PendingTxRequest, logger log.Logger) (*pendingTxNotifier, *wsRequestError) {
+	if chain.ServiceManager() == nil {
+		return nil, newWSRequestError(jsonrpc.ErrorCodeServer, "Stopped")
+	}
+	tpi, err := service.TxPoolInspectorOf(chain)
+	if err != nil {
+		return nil, newWSRequestError(jsonrpc.ErrorCodeServer, err.Error())
+	}
+	return &pendingTxNotifier{
+		logger: logger,
+		tpi:    tpi,
+		req:    pr,
+	}, nil
+}
+
+func (n *pendingTxNotifier) AckWindow() *ackWindow {
+	return nil
+}
+
+func (n *pendingTxNotifier) Run(w wsWriter, ech <-chan error) error {
+	// the listener is called in the lock of the pool, so it only queues
+	// the transaction and counts missed ones if the queue is full.
+	txch := make(chan module.Transaction, DefaultWSPendingTxBuffer)
+	var missed int32
+	remove := n.tpi.AddTxListener(func(tx module.Transaction) {
+		select {
+		case txch <- tx:
+		default:
+			atomic.AddInt32(&missed, 1)
+		}
+	})
+	defer remove()
+
+	for {
+		select {
+		case err := <-ech:
+			return err
+		case tx := <-txch:
+			pn := &PendingTxNotification{
+				Hash: tx.ID(),
+			}
+			if cnt := atomic.SwapInt32(&missed, 0); cnt > 0 {
+				pn.Missed = &common.HexInt32{Value: cnt}
+			}
+			if n.req.Full.Value {
+				js, err := tx.ToJSON(module.JSONVersionLast)
+				if err != nil {
+					n.logger.Infof("fail to make JSON of tx=%#x err=%+v", tx.ID(), err)
+					return err
+				}
+				pn.Transaction = js
+			}
+			if err := w.WriteJSON(pn); err != nil {
+				n.logger.Infof("fail to write json PendingTxNotification err:%+v\n", err)
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2022 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+)
+
+type testTxPoolInspector struct {
+	service.TxPoolInspector
+	lock sync.Mutex
+	cb   service.TxListener
+}
+
+func (tpi *testTxPoolInspector) AddTxListener(cb service.TxListener) func() {
+	tpi.lock.Lock()
+	defer tpi.lock.Unlock()
+	tpi.cb = cb
+	return func() {
+		tpi.lock.Lock()
+		defer tpi.lock.Unlock()
+		tpi.cb = nil
+	}
+}
+
+func (tpi *testTxPoolInspector) add(tx module.Transaction) bool {
+	tpi.lock.Lock()
+	defer tpi.lock.Unlock()
+	if tpi.cb == nil {
+		return false
+	}
+	tpi.cb(tx)
+	return true
+}
+
+type testTransaction struct {
+	module.Transaction
+	id []byte
+}
+
+func (tx *testTransaction) ID() []byte {
+	return tx.id
+}
+
+func (tx *testTransaction) ToJSON(version module.JSONVersion) (interface{}, error) {
+	return map[string]interface{}{
+		"txHash": common.HexBytes(tx.id),
+	}, nil
+}
+
+type testWSWriter chan []byte
+
+func (w testWSWriter) WriteJSON(v interface{}) error {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w <- bs
+	return nil
+}
+
+func TestPendingTxNotifier_Run(t *testing.T) {
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	tpi := new(testTxPoolInspector)
+	n := &pendingTxNotifier{
+		logger: logger,
+		tpi:    tpi,
+		req:    &PendingTxRequest{Full: common.HexBool{Value: true}},
+	}
+	w := make(testWSWriter, 1)
+	ech := make(chan error, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- n.Run(w, ech)
+	}()
+
+	for !tpi.add(&testTransaction{id: []byte{0x01}}) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	var pn PendingTxNotification
+	assert.NoError(t, json.Unmarshal(<-w, &pn))
+	assert.Equal(t, common.HexBytes{0x01}, pn.Hash)
+	assert.Equal(t, map[string]interface{}{"txHash": "0x01"}, pn.Transaction)
+	assert.Nil(t, pn.Missed)
+
+	ech <- errors.New("closed")
+	assert.Error(t, <-done)
+	assert.False(t, tpi.add(&testTransaction{id: []byte{0x02}}))
+}
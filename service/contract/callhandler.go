@@ -163,6 +163,17 @@ func (h *CallHandler) contract(as state.AccountState) state.ContractState {
 	}
 }
 
+func (h *CallHandler) TraceCallInfo() *module.TraceCallInfo {
+	info := h.CommonHandler.TraceCallInfo()
+	info.Method = h.name
+	if h.paramObj != nil {
+		info.Params, _ = common.DecodeAnyForJSON(h.paramObj)
+	} else if len(h.params) > 0 {
+		info.Params = json.RawMessage(h.params)
+	}
+	return info
+}
+
 func (h *CallHandler) TLogStart() {
 	h.Log.TSystemf("INVOKE start score=%s method=%s", h.To, h.name)
 }
@@ -30,6 +30,12 @@ type (
 		TraceLogger() *trace.Logger
 	}
 
+	// traceCallInfoProvider is implemented by handlers providing
+	// information of the call for TraceModeCallTree.
+	traceCallInfoProvider interface {
+		TraceCallInfo() *module.TraceCallInfo
+	}
+
 	SyncContractHandler interface {
 		ContractHandler
 		ExecuteSync(cc CallContext) (error, *codec.TypedObj, module.Address)
@@ -95,3 +101,18 @@ func (h *CommonHandler) TraceLogger() *trace.Logger {
 func (h *CommonHandler) Logger() log.Logger {
 	return h.Log
 }
+
+func (h *CommonHandler) TraceCallInfo() *module.TraceCallInfo {
+	return &module.TraceCallInfo{
+		From:  h.From,
+		To:    h.To,
+		Value: h.Value,
+	}
+}
+
+func traceCallInfoOf(handler ContractHandler) *module.TraceCallInfo {
+	if p, ok := handler.(traceCallInfoProvider); ok {
+		return p.TraceCallInfo()
+	}
+	return &module.TraceCallInfo{}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/icon-project/goloop/common/codec"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/wasmee"
 
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
@@ -311,7 +312,7 @@ func (cm *contractManager) GetSystemScore(contentID string, cc CallContext, from
 }
 
 func (cm *contractManager) DefaultEnabledEETypes() state.EETypes {
-	return state.AllEETypes
+	return state.DefaultEETypes
 }
 
 func (cm *contractManager) GenesisTo() module.Address {
@@ -352,6 +353,7 @@ func NewContractManager(db db.Database, contractDir string, log log.Logger) (Con
 
 const (
 	javaCode               = "code.jar"
+	wasmCode               = wasmee.CodeFile
 	tmpRoot                = "tmp"
 	tmpPattern             = "tmp-*"
 	contractPythonRootFile = "package.json"
@@ -448,6 +450,20 @@ func storeJava(path string, code []byte, log log.Logger) error {
 	return nil
 }
 
+func storeWasm(path string, code []byte, log log.Logger) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err = os.MkdirAll(path, 0755); err != nil {
+			return errors.WithCode(err, errors.CriticalIOError)
+		}
+	}
+	sPath := filepath.Join(path, wasmCode)
+	if err := os.WriteFile(sPath, code, 0644); err != nil {
+		_ = os.RemoveAll(sPath)
+		return errors.WithCode(err, errors.CriticalIOError)
+	}
+	return nil
+}
+
 func storeByEEType(e state.EEType, path string, code []byte, log log.Logger) error {
 	var err error
 	switch e {
@@ -455,6 +471,8 @@ func storeByEEType(e state.EEType, path string, code []byte, log log.Logger) err
 		err = storePython(path, code, log)
 	case state.JavaEE:
 		err = storeJava(path, code, log)
+	case state.WasmEE:
+		err = storeWasm(path, code, log)
 	default:
 		err = scoreresult.Errorf(module.StatusInvalidParameter,
 			"UnexpectedEEType(%v)\n", e)
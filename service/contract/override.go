@@ -0,0 +1,60 @@
+/*
+ * Copyright 2022 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contract
+
+import (
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// OverrideContract replaces the contract of the account with the code
+// without calling its install or update method. If the account is not a
+// contract, it becomes a contract owned by the system.
+// It must be used only on the world state which is never committed.
+func OverrideContract(ctx Context, addr module.Address, code []byte, contentType string) error {
+	if !addr.IsContract() {
+		return scoreresult.InvalidParameterError.Errorf("NotContractAddress(%s)", addr)
+	}
+	eeType, ok := state.EETypeFromContentType(contentType)
+	if !ok || eeType == state.SystemEE {
+		return scoreresult.InvalidParameterError.Errorf("InvalidContentType(%s)", contentType)
+	}
+	if !ctx.GetEnabledEETypes().Contains(eeType) {
+		return scoreresult.InvalidParameterError.Errorf("NotEnabledContentType(%s)", contentType)
+	}
+
+	as := ctx.GetAccountState(addr.ID())
+	if !as.IsContract() {
+		as.InitContractAccount(state.SystemAddress)
+	}
+	id := crypto.SHA3Sum256(code)
+	if _, err := as.DeployContract(code, eeType, contentType, nil, id); err != nil {
+		return err
+	}
+
+	// API information of the contract is updated by the execution
+	// environment before the contract becomes active.
+	cc := NewCallContext(ctx, ctx.GetStepLimit(state.StepLimitTypeInvoke), false)
+	defer cc.Dispose()
+	handler := newCallGetAPIHandler(NewCommonHandler(state.SystemAddress, addr, nil, false, ctx.Logger()))
+	if status, _, _, _ := cc.Call(handler, cc.StepAvailable()); status != nil {
+		return status
+	}
+	return as.AcceptContract(id, id)
+}
@@ -0,0 +1,464 @@
+package eeproxy
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/ipc"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/wasmee"
+)
+
+const (
+	WasmEE = "wasmee"
+
+	wasmVersion = 1
+)
+
+var errWasmInstanceClosed = errors.InvalidStateError.New("InstanceClosed")
+
+// wasmFrame is the frame of the execution in the instance. It implements
+// wasmee.Host by sending requests to the proxy like other execution
+// environments do, so the proxy doesn't need to know where it runs.
+type wasmFrame struct {
+	is       *wasmInstance
+	readOnly bool
+	trace    bool
+	from     *common.Address
+	to       common.Address
+	value    *big.Int
+	limit    *big.Int
+	used     *big.Int
+	costs    map[string]interface{}
+	info     map[string]interface{}
+	result   *resultMessage
+
+	prev *wasmFrame
+}
+
+func (f *wasmFrame) stepsFor(t string, n int) int64 {
+	if v, ok := f.costs[t].(*common.HexInt); ok {
+		return v.Int64() * int64(n)
+	}
+	return 0
+}
+
+func (f *wasmFrame) StepsLeft() int64 {
+	left := new(big.Int).Sub(f.limit, f.used)
+	if !left.IsInt64() {
+		return math.MaxInt64
+	}
+	return left.Int64()
+}
+
+func (f *wasmFrame) DeductSteps(steps int64) error {
+	f.used.Add(f.used, big.NewInt(steps))
+	if f.used.Cmp(f.limit) > 0 {
+		f.used.Set(f.limit)
+		return scoreresult.ErrOutOfStep
+	}
+	return nil
+}
+
+func (f *wasmFrame) checkWritable(op string) error {
+	if f.readOnly {
+		return scoreresult.AccessDeniedError.Errorf("%sInReadOnly", op)
+	}
+	return nil
+}
+
+func (f *wasmFrame) GetValue(key []byte) ([]byte, error) {
+	var m getValueMessage
+	if err := f.is.conn.SendAndReceive(msgGETVALUE, key, &m); err != nil {
+		return nil, err
+	}
+	if !m.Success {
+		return nil, f.DeductSteps(f.stepsFor(state.StepTypeGetBase, 1))
+	}
+	return m.Value, f.DeductSteps(f.stepsFor(state.StepTypeGetBase, 1) +
+		f.stepsFor(state.StepTypeGet, len(m.Value)))
+}
+
+func (f *wasmFrame) SetValue(key []byte, value []byte) error {
+	if err := f.checkWritable("SetValue"); err != nil {
+		return err
+	}
+	if err := f.DeductSteps(f.stepsFor(state.StepTypeSetBase, 1) +
+		f.stepsFor(state.StepTypeSet, len(value))); err != nil {
+		return err
+	}
+	return f.is.conn.Send(msgSETVALUE, &setValueMessage{
+		Key:   key,
+		Value: value,
+	})
+}
+
+func (f *wasmFrame) DeleteValue(key []byte) error {
+	if err := f.checkWritable("DeleteValue"); err != nil {
+		return err
+	}
+	var m oldValueMessage
+	if err := f.is.conn.SendAndReceive(msgSETVALUE, &setValueMessage{
+		Key:  key,
+		Flag: flagDELETE | flagOLDVALUE,
+	}, &m); err != nil {
+		return err
+	}
+	return f.DeductSteps(f.stepsFor(state.StepTypeDeleteBase, 1) +
+		f.stepsFor(state.StepTypeDelete, m.OldSize))
+}
+
+func (f *wasmFrame) GetBalance(addr module.Address) (*big.Int, error) {
+	if err := f.DeductSteps(f.stepsFor(state.StepTypeApiCall, 1)); err != nil {
+		return nil, err
+	}
+	var balance common.HexInt
+	if err := f.is.conn.SendAndReceive(msgGETBALANCE, common.AddressToPtr(addr), &balance); err != nil {
+		return nil, err
+	}
+	return balance.Value(), nil
+}
+
+// GetInfo returns the information of the execution for the contract.
+// It includes the information of the block and the transaction given by
+// the proxy, and the address, the sender and the value of the call.
+func (f *wasmFrame) GetInfo() interface{} {
+	info := make(map[string]interface{})
+	for k, v := range f.info {
+		if k == state.InfoStepCosts {
+			continue
+		}
+		if jso, err := common.AnyForJSON(v); err == nil {
+			info[k] = jso
+		}
+	}
+	info["C.address"] = &f.to
+	info["M.from"] = f.from
+	info["M.value"] = common.NewHexInt(0).SetValue(f.value)
+	return info
+}
+
+func (f *wasmFrame) OnEvent(indexed, data [][]byte) error {
+	if err := f.checkWritable("Event"); err != nil {
+		return err
+	}
+	size := 0
+	for _, bs := range indexed {
+		size += len(bs)
+	}
+	for _, bs := range data {
+		size += len(bs)
+	}
+	if err := f.DeductSteps(f.stepsFor(state.StepTypeLogBase, 1) +
+		f.stepsFor(state.StepTypeLog, size)); err != nil {
+		return err
+	}
+	return f.is.conn.Send(msgEVENT, &eventMessage{
+		Indexed: indexed,
+		Data:    data,
+	})
+}
+
+func (f *wasmFrame) Call(to module.Address, value *big.Int, method string, params *codec.TypedObj) (*codec.TypedObj, error) {
+	if value == nil {
+		value = new(big.Int)
+	}
+	data, err := common.EncodeAny(map[string]interface{}{
+		"method": method,
+		"params": params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var m callMessage
+	m.To.Set(to)
+	m.Value.Set(value)
+	m.Limit.Sub(f.limit, f.used)
+	m.DataType = "call"
+	m.Data = data
+	if err := f.is.conn.Send(msgCALL, &m); err != nil {
+		return nil, err
+	}
+	for f.result == nil {
+		if err := f.is.conn.HandleMessage(); err != nil {
+			return nil, err
+		}
+	}
+	res := f.result
+	f.result = nil
+
+	f.used.Add(f.used, &res.StepUsed.Int)
+	code, _ := StatusToCodeAndFlag(res.Status)
+	if code != errors.Success {
+		return nil, code.New(common.DecodeAsString(res.Result, ""))
+	}
+	return res.Result, nil
+}
+
+func (f *wasmFrame) Log(msg string) {
+	m := logMessage{
+		Level:   log.DebugLevel,
+		Message: msg,
+	}
+	if f.trace {
+		m.Flag |= LogFlagTrace
+	}
+	_ = f.is.conn.Send(msgLOG, &m)
+}
+
+type wasmInstance struct {
+	uid    string
+	status InstanceStatus
+	conn   ipc.Connection
+	ctx    context.Context
+	cancel context.CancelFunc
+	frame  *wasmFrame
+	engine *wasmExecutionEngine
+}
+
+func (is *wasmInstance) invoke(m *invokeMessage) error {
+	var info map[string]interface{}
+	if v, err := common.DecodeAny(m.Info); err == nil {
+		info, _ = v.(map[string]interface{})
+	}
+	costs, _ := info[state.InfoStepCosts].(map[string]interface{})
+	is.frame = &wasmFrame{
+		is:       is,
+		readOnly: (m.Flag & InvokeFlagReadOnly) != 0,
+		trace:    (m.Flag & InvokeFlagTrace) != 0,
+		from:     m.From,
+		to:       m.To,
+		value:    m.Value.Value(),
+		limit:    m.Limit.Value(),
+		used:     new(big.Int),
+		costs:    costs,
+		info:     info,
+		prev:     is.frame,
+	}
+	frame := is.frame
+	result, err := is.engine.runtime.Invoke(is.ctx, frame, m.Code, m.Method, m.Params)
+	is.frame = frame.prev
+
+	var r resultMessage
+	r.StepUsed.Set(frame.used)
+	r.EID = m.EID
+	if err != nil {
+		is.engine.logger.Debugf("Invoke failed method=%s err=%+v", m.Method, err)
+		status, _ := scoreresult.StatusOf(err)
+		r.Status = errors.Code(status)
+		r.Result = common.MustEncodeAny(err.Error())
+	} else {
+		r.Status = errors.Success
+		r.Result = result
+	}
+	return is.conn.Send(msgRESULT, &r)
+}
+
+func (is *wasmInstance) getAPI(path string) error {
+	var m getAPIMessage
+	if info, err := is.engine.runtime.GetAPI(is.ctx, path); err != nil {
+		is.engine.logger.Debugf("GetAPI failed path=%s err=%+v", path, err)
+		status, _ := scoreresult.StatusOf(err)
+		m.Status = errors.Code(status)
+	} else {
+		m.Status = errors.Success
+		m.Info = info
+	}
+	return is.conn.Send(msgGETAPI, &m)
+}
+
+func (is *wasmInstance) HandleMessage(c ipc.Connection, msg uint, data []byte) error {
+	switch msg {
+	case msgINVOKE:
+		var m invokeMessage
+		if _, err := codec.MP.UnmarshalFromBytes(data, &m); err != nil {
+			return err
+		}
+		return is.invoke(&m)
+
+	case msgGETAPI:
+		var path string
+		if _, err := codec.MP.UnmarshalFromBytes(data, &path); err != nil {
+			return err
+		}
+		return is.getAPI(path)
+
+	case msgRESULT:
+		var m resultMessage
+		if _, err := codec.MP.UnmarshalFromBytes(data, &m); err != nil {
+			return err
+		}
+		if is.frame == nil {
+			return errors.InvalidStateError.New("NoFrameForResult")
+		}
+		is.frame.result = &m
+		return nil
+
+	case msgCLOSE:
+		return errWasmInstanceClosed
+
+	default:
+		return errors.IllegalArgumentError.Errorf("UnknownMessage(msg=%d)", msg)
+	}
+}
+
+func (is *wasmInstance) serve(net, addr string) error {
+	conn, err := ipc.Dial(net, addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	is.engine.lock.Lock()
+	is.conn = conn
+	is.engine.lock.Unlock()
+
+	conn.SetHandler(msgINVOKE, is)
+	conn.SetHandler(msgGETAPI, is)
+	conn.SetHandler(msgRESULT, is)
+	conn.SetHandler(msgCLOSE, is)
+	if err := conn.Send(msgVERSION, &versionMessage{
+		Version: wasmVersion,
+		UID:     is.uid,
+		Type:    wasmEEType,
+	}); err != nil {
+		return err
+	}
+	for {
+		if err := conn.HandleMessage(); err != nil {
+			return err
+		}
+	}
+}
+
+const wasmEEType = "wasm"
+
+// wasmExecutionEngine runs contracts in WebAssembly in the process.
+// Each instance connects to the manager like an external execution
+// environment, so the manager handles them in the same way.
+type wasmExecutionEngine struct {
+	lock      sync.Mutex
+	target    int
+	instances map[string]*wasmInstance
+	net, addr string
+	runtime   *wasmee.Runtime
+	logger    log.Logger
+}
+
+func (e *wasmExecutionEngine) Type() string {
+	return wasmEEType
+}
+
+func (e *wasmExecutionEngine) Init(net, addr string) error {
+	e.net = net
+	e.addr = addr
+	return nil
+}
+
+func (e *wasmExecutionEngine) SetInstances(n int) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if n < 0 {
+		return errors.ErrIllegalArgument
+	}
+
+	e.target = n
+	for e.target > len(e.instances) {
+		e.startNew()
+	}
+	return nil
+}
+
+func (e *wasmExecutionEngine) OnAttach(uid string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if is, ok := e.instances[uid]; ok {
+		is.status = instanceOnline
+		return true
+	}
+	return false
+}
+
+func (e *wasmExecutionEngine) OnEnd(uid string) bool {
+	return true
+}
+
+func (e *wasmExecutionEngine) Kill(uid string) (bool, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if is, ok := e.instances[uid]; ok {
+		is.cancel()
+		if is.conn != nil {
+			return true, is.conn.Close()
+		}
+		return true, nil
+	} else {
+		return false, nil
+	}
+}
+
+func (e *wasmExecutionEngine) OnConnect(conn ipc.Connection, version uint16) error {
+	return common.ErrUnsupported
+}
+
+func (e *wasmExecutionEngine) OnClose(conn ipc.Connection) bool {
+	return false
+}
+
+func (e *wasmExecutionEngine) startNew() {
+	ctx, cancel := context.WithCancel(context.Background())
+	is := &wasmInstance{
+		uid:    newUID(),
+		status: instanceStarted,
+		ctx:    ctx,
+		cancel: cancel,
+		engine: e,
+	}
+	e.instances[is.uid] = is
+	e.logger.Infof("start instance uid=%s", is.uid)
+	go e.run(is)
+}
+
+func (e *wasmExecutionEngine) run(is *wasmInstance) {
+	err := is.serve(e.net, e.addr)
+	e.logger.Tracef("Serve result uid=%s err=%+v", is.uid, err)
+	is.cancel()
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	delete(e.instances, is.uid)
+	if is.status != instanceOnline {
+		e.logger.Warnf("It's not correctly started uid=%s err=%+v", is.uid, err)
+		return
+	}
+	if len(e.instances) < e.target {
+		if err != errWasmInstanceClosed {
+			e.logger.Warnf("Instance uid=%s is killed err=%+v", is.uid, err)
+		}
+		e.startNew()
+	}
+}
+
+func NewWasmEE(logger log.Logger) (Engine, error) {
+	rt, err := wasmee.NewRuntime(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &wasmExecutionEngine{
+		instances: make(map[string]*wasmInstance),
+		runtime:   rt,
+		logger:    logger.WithFields(log.Fields{log.FieldKeyModule: WasmEE}),
+	}, nil
+}
@@ -0,0 +1,183 @@
+package eeproxy
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/wasmee"
+)
+
+type testResult struct {
+	status error
+	steps  *big.Int
+	result *codec.TypedObj
+}
+
+type testCallContext struct {
+	store   map[string][]byte
+	events  int
+	results chan testResult
+	apis    chan *scoreapi.Info
+}
+
+func (c *testCallContext) GetValue(key []byte) ([]byte, error) {
+	return c.store[string(key)], nil
+}
+
+func (c *testCallContext) SetValue(key []byte, value []byte) ([]byte, error) {
+	old := c.store[string(key)]
+	c.store[string(key)] = value
+	return old, nil
+}
+
+func (c *testCallContext) DeleteValue(key []byte) ([]byte, error) {
+	old := c.store[string(key)]
+	delete(c.store, string(key))
+	return old, nil
+}
+
+func (c *testCallContext) ArrayDBContains(prefix, value []byte, limit int64) (bool, int, int, error) {
+	return false, 0, 0, nil
+}
+
+func (c *testCallContext) GetInfo() *codec.TypedObj {
+	return common.MustEncodeAny(map[string]interface{}{
+		state.InfoBlockHeight: 1,
+		state.InfoStepCosts: map[string]interface{}{
+			state.StepTypeGetBase: 10,
+			state.StepTypeGet:     1,
+			state.StepTypeSetBase: 20,
+			state.StepTypeSet:     2,
+		},
+	})
+}
+
+func (c *testCallContext) GetBalance(addr module.Address) *big.Int {
+	return new(big.Int)
+}
+
+func (c *testCallContext) OnEvent(addr module.Address, indexed, data [][]byte) error {
+	c.events += 1
+	return nil
+}
+
+func (c *testCallContext) OnResult(status error, flag int, steps *big.Int, result *codec.TypedObj) {
+	c.results <- testResult{status, steps, result}
+}
+
+func (c *testCallContext) OnCall(from, to module.Address, value, limit *big.Int, dataType string, dataObj *codec.TypedObj) {
+}
+
+func (c *testCallContext) OnAPI(status error, info *scoreapi.Info) {
+	c.apis <- info
+}
+
+func (c *testCallContext) OnSetFeeProportion(portion int) {
+}
+
+func (c *testCallContext) SetCode(code []byte) error {
+	return nil
+}
+
+func (c *testCallContext) GetObjGraph(bool) (int, []byte, []byte, error) {
+	return 0, nil, nil, nil
+}
+
+func (c *testCallContext) SetObjGraph(flags bool, nextHash int, objGraph []byte) error {
+	return nil
+}
+
+func (c *testCallContext) Logger() log.Logger {
+	return log.GlobalLogger()
+}
+
+func (c *testCallContext) waitResult(t *testing.T) testResult {
+	select {
+	case r := <-c.results:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+		return testResult{}
+	}
+}
+
+func TestWasmEE_Invoke(t *testing.T) {
+	dir := t.TempDir()
+	code, err := os.ReadFile(filepath.Join("..", "wasmee", "testdata", "counter.wasm"))
+	assert.NoError(t, err)
+	path := filepath.Join(dir, "contract")
+	assert.NoError(t, os.MkdirAll(path, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(path, wasmee.CodeFile), code, 0644))
+
+	engines, err := AllocEngines(log.GlobalLogger(), "wasm")
+	assert.NoError(t, err)
+	mgr, err := NewManager("unix", filepath.Join(dir, "ee.socket"), log.GlobalLogger(), engines...)
+	assert.NoError(t, err)
+	go mgr.Loop()
+	defer mgr.Close()
+	assert.NoError(t, mgr.SetInstances(1, 1, 1))
+
+	ex := mgr.GetExecutor(ForTransaction)
+	defer ex.Release()
+	p := ex.Get("wasm")
+	assert.NotNil(t, p)
+
+	ctx := &testCallContext{
+		store:   make(map[string][]byte),
+		results: make(chan testResult, 1),
+		apis:    make(chan *scoreapi.Info, 1),
+	}
+	assert.NoError(t, p.GetAPI(ctx, path))
+	select {
+	case info := <-ctx.apis:
+		assert.NotNil(t, info.GetMethod("set"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	from := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	to := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	limit := big.NewInt(1000)
+	params := common.MustEncodeAny([]interface{}{common.NewHexInt(0x12)})
+	assert.NoError(t, p.Invoke(ctx, path, false, from, to, new(big.Int), limit,
+		"set", params, nil, 1, nil))
+	r := ctx.waitResult(t)
+	assert.NoError(t, r.status)
+	// 20 instructions and setting 6 bytes
+	assert.Equal(t, big.NewInt(20+20+2*6), r.steps)
+	assert.Equal(t, []byte("\"0x12\""), ctx.store["count"])
+	assert.Equal(t, 1, ctx.events)
+
+	assert.NoError(t, p.Invoke(ctx, path, true, from, to, new(big.Int), limit,
+		"get", nil, nil, 2, nil))
+	r = ctx.waitResult(t)
+	assert.NoError(t, r.status)
+	// 8 instructions and getting 6 bytes
+	assert.Equal(t, big.NewInt(8+10+1*6), r.steps)
+	assert.Equal(t, common.MustEncodeAny(common.NewHexInt(0x12)), r.result)
+
+	assert.NoError(t, p.Invoke(ctx, path, true, from, to, new(big.Int), limit,
+		"set", params, nil, 3, nil))
+	r = ctx.waitResult(t)
+	status, _ := scoreresult.StatusOf(r.status)
+	assert.Equal(t, module.StatusAccessDenied, status)
+
+	assert.NoError(t, p.Invoke(ctx, path, false, from, to, new(big.Int), big.NewInt(25),
+		"set", params, nil, 4, nil))
+	r = ctx.waitResult(t)
+	status, _ = scoreresult.StatusOf(r.status)
+	assert.Equal(t, module.StatusOutOfStep, status)
+	assert.Equal(t, big.NewInt(25), r.steps)
+}
@@ -4,17 +4,23 @@ import (
 	"github.com/icon-project/goloop/module"
 )
 
-func Inspect(c module.Chain, informal bool) map[string]interface{} {
-	var mgr *manager
+func managerOf(c module.Chain) *manager {
 	if sm := c.ServiceManager(); sm == nil {
 		return nil
 	} else {
 		if impl, ok := sm.(*manager); ok {
-			mgr = impl
+			return impl
 		} else {
 			return nil
 		}
 	}
+}
+
+func Inspect(c module.Chain, informal bool) map[string]interface{} {
+	mgr := managerOf(c)
+	if mgr == nil {
+		return nil
+	}
 	m := make(map[string]interface{})
 	m["normalTxPool"] = inspectTxPool(mgr.tm.normalTxPool)
 	m["patchTxPool"] = inspectTxPool(mgr.tm.patchTxPool)
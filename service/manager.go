@@ -489,6 +489,13 @@ func (m *manager) SendTransaction(result []byte, height int64, txi interface{})
 
 func (m *manager) Call(resultHash []byte,
 	vl module.ValidatorList, js []byte, bi module.BlockInfo,
+) (interface{}, error) {
+	return m.CallWithOverride(resultHash, vl, js, bi, nil)
+}
+
+func (m *manager) CallWithOverride(resultHash []byte,
+	vl module.ValidatorList, js []byte, bi module.BlockInfo,
+	aos []*AccountOverride,
 ) (interface{}, error) {
 	type callJSON struct {
 		To       common.Address  `json:"to"`
@@ -504,13 +511,17 @@ func (m *manager) Call(resultHash []byte,
 		return nil, InvalidQueryError.New("InvalidDataType")
 	}
 
-	var wc state.WorldContext
-	if wss, err := m.trc.GetWorldSnapshot(resultHash, vl.Hash()); err == nil {
-		ws := state.NewReadOnlyWorldState(wss)
-		wc = state.NewWorldContext(ws, bi, nil, m.plt)
-	} else {
+	wss, err := m.trc.GetWorldSnapshot(resultHash, vl.Hash())
+	if err != nil {
 		return nil, err
 	}
+	if len(aos) > 0 {
+		if wss, err = m.overrideWorldSnapshot(wss, bi, aos); err != nil {
+			return nil, err
+		}
+	}
+	ws := state.NewReadOnlyWorldState(wss)
+	wc := state.NewWorldContext(ws, bi, nil, m.plt)
 
 	qh, err := NewQueryHandler(m.cm, &jso.To, jso.Data)
 	if err != nil {
@@ -871,6 +882,10 @@ func (m *manager) ImportResult(result []byte, vh []byte, src db.Database) error
 }
 
 func (m *manager) ExecuteTransaction(result []byte, vh []byte, js []byte, bi module.BlockInfo) (module.Receipt, error) {
+	return m.ExecuteTransactionWithOverride(result, vh, js, bi, nil)
+}
+
+func (m *manager) ExecuteTransactionWithOverride(result []byte, vh []byte, js []byte, bi module.BlockInfo, aos []*AccountOverride) (module.Receipt, error) {
 	tx, err := transaction.NewTransactionFromJSON(js)
 	if err != nil {
 		return nil, err
@@ -885,17 +900,20 @@ func (m *manager) ExecuteTransaction(result []byte, vh []byte, js []byte, bi mod
 	}
 	defer txh.Dispose()
 
-	var wc state.WorldContext
 	wss, err := m.trc.GetWorldSnapshot(result, vh)
-	if err == nil {
-		ws, err := state.WorldStateFromSnapshot(wss)
-		if err != nil {
+	if err != nil {
+		return nil, err
+	}
+	if len(aos) > 0 {
+		if wss, err = m.overrideWorldSnapshot(wss, bi, aos); err != nil {
 			return nil, err
 		}
-		wc = state.NewWorldContext(ws, bi, nil, m.plt)
-	} else {
+	}
+	ws, err := state.WorldStateFromSnapshot(wss)
+	if err != nil {
 		return nil, err
 	}
+	wc := state.NewWorldContext(ws, bi, nil, m.plt)
 	ctx := contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery)
 	ctx.SetTransactionInfo(&state.TransactionInfo{
 		Group:     module.TransactionGroupNormal,
@@ -612,6 +612,7 @@ type Chain struct {
 	DepositTerm        *common.HexInt64  `json:"depositTerm"`
 	DepositIssueRate   *common.HexInt64  `json:"depositIssueRate"`
 	FeeSharingEnabled  *common.HexInt16  `json:"feeSharingEnabled"`
+	EnabledEETypes     *string           `json:"enabledEETypes"`
 }
 
 func (s *ChainScore) Install(param []byte) error {
@@ -710,6 +711,15 @@ func (s *ChainScore) Install(param []byte) error {
 		}
 	}
 
+	if chain.EnabledEETypes != nil {
+		if _, err := state.ParseEETypes(*chain.EnabledEETypes); err != nil {
+			return scoreresult.IllegalFormatError.Wrapf(err, "InvalidEnabledEETypes(%s)", *chain.EnabledEETypes)
+		}
+		if err := scoredb.NewVarDB(as, state.VarEnabledEETypes).Set(*chain.EnabledEETypes); err != nil {
+			return err
+		}
+	}
+
 	price := chain.Fee
 	if err := scoredb.NewVarDB(as, state.VarStepPrice).Set(&price.StepPrice.Int); err != nil {
 		return err
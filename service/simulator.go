@@ -0,0 +1,226 @@
+/*
+ * Copyright 2022 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"math/big"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+// AccountOverride replaces a part of the account for simulation.
+// Nil or empty fields are not overridden.
+type AccountOverride struct {
+	Address module.Address
+
+	// Balance replaces the balance of the account.
+	Balance *big.Int
+
+	// Code replaces the contract of the account with the code of
+	// ContentType without calling its install or update method.
+	Code        []byte
+	ContentType string
+
+	// Storage sets the values for the raw keys in the storage of the
+	// account. An empty value deletes the key.
+	Storage map[string][]byte
+}
+
+// Simulator executes calls and transactions on the state with
+// overrides which are never committed.
+type Simulator interface {
+	// CallWithOverride handles read-only contract API call on the
+	// state with overrides.
+	CallWithOverride(result []byte, vl module.ValidatorList, js []byte,
+		bi module.BlockInfo, ao []*AccountOverride) (interface{}, error)
+
+	// ExecuteTransactionWithOverride executes the transaction on the
+	// state with overrides.
+	ExecuteTransactionWithOverride(result []byte, vh []byte, js []byte,
+		bi module.BlockInfo, ao []*AccountOverride) (module.Receipt, error)
+
+	// SimulateTransactions executes the transactions in order on the state
+	// with overrides, and returns their receipts. Each transaction sees
+	// the changes made by the previous ones. If ti is not nil, the
+	// transactions are traced with it, and its Range and TraceBlock are
+	// set by the simulator.
+	SimulateTransactions(result []byte, vh []byte, txs [][]byte,
+		bi module.BlockInfo, ao []*AccountOverride, ti *module.TraceInfo) ([]module.Receipt, error)
+}
+
+func SimulatorOf(c module.Chain) (Simulator, error) {
+	mgr := managerOf(c)
+	if mgr == nil {
+		return nil, errors.UnsupportedError.New("NoSimulator")
+	}
+	return mgr, nil
+}
+
+func applyAccountOverrides(ctx contract.Context, aos []*AccountOverride) error {
+	for _, ao := range aos {
+		if ao == nil || ao.Address == nil {
+			return scoreresult.InvalidParameterError.New("NoAddressForOverride")
+		}
+		if len(ao.Code) > 0 {
+			if err := contract.OverrideContract(ctx, ao.Address, ao.Code, ao.ContentType); err != nil {
+				return err
+			}
+		}
+		as := ctx.GetAccountState(ao.Address.ID())
+		if ao.Balance != nil {
+			if ao.Balance.Sign() < 0 {
+				return scoreresult.InvalidParameterError.Errorf(
+					"NegativeBalance(addr=%s,balance=%s)", ao.Address, ao.Balance)
+			}
+			as.SetBalance(ao.Balance)
+		}
+		for k, v := range ao.Storage {
+			var err error
+			if len(v) == 0 {
+				_, err = as.DeleteValue([]byte(k))
+			} else {
+				_, err = as.SetValue([]byte(k), v)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// overrideWorldSnapshot returns the snapshot of the world state with
+// overrides. The world state is never flushed, so it's discarded with
+// the snapshot.
+func (m *manager) overrideWorldSnapshot(wss state.WorldSnapshot, bi module.BlockInfo, aos []*AccountOverride) (state.WorldSnapshot, error) {
+	ws, err := state.WorldStateFromSnapshot(wss)
+	if err != nil {
+		return nil, err
+	}
+	wc := state.NewWorldContext(ws, bi, nil, m.plt)
+	ctx := contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery)
+	if err := applyAccountOverrides(ctx, aos); err != nil {
+		return nil, err
+	}
+	return ws.GetSnapshot(), nil
+}
+
+// simulationTraceBlock provides receipts of simulated transactions for
+// the tracer.
+type simulationTraceBlock struct {
+	receipts []module.Receipt
+}
+
+func (b *simulationTraceBlock) ID() []byte {
+	return nil
+}
+
+func (b *simulationTraceBlock) GetReceipt(txIndex int) module.Receipt {
+	if txIndex < 0 || txIndex >= len(b.receipts) {
+		return nil
+	}
+	return b.receipts[txIndex]
+}
+
+func (m *manager) SimulateTransactions(
+	result []byte, vh []byte, txs [][]byte, bi module.BlockInfo,
+	aos []*AccountOverride, ti *module.TraceInfo,
+) ([]module.Receipt, error) {
+	txos := make([]transaction.Transaction, len(txs))
+	for i, js := range txs {
+		tx, err := transaction.NewTransactionFromJSON(js)
+		if err != nil {
+			return nil, scoreresult.InvalidParameterError.Wrapf(err, "InvalidTransaction(idx=%d)", i)
+		}
+		if err := tx.Verify(); err != nil && !transaction.InvalidSignatureError.Equals(err) {
+			return nil, scoreresult.InvalidParameterError.Wrapf(err, "InvalidTransaction(idx=%d)", i)
+		}
+		txos[i] = tx
+	}
+
+	wss, err := m.trc.GetWorldSnapshot(result, vh)
+	if err != nil {
+		return nil, err
+	}
+	if len(aos) > 0 {
+		if wss, err = m.overrideWorldSnapshot(wss, bi, aos); err != nil {
+			return nil, err
+		}
+	}
+	ws, err := state.WorldStateFromSnapshot(wss)
+	if err != nil {
+		return nil, err
+	}
+
+	tb := &simulationTraceBlock{
+		receipts: make([]module.Receipt, 0, len(txos)),
+	}
+	if ti != nil {
+		ti.Range = module.TraceRangeBlock
+		ti.TraceBlock = tb
+	}
+	wc := state.NewWorldContext(ws, bi, nil, m.plt)
+	ctx := contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, ti, eeproxy.ForQuery)
+	for i, tx := range txos {
+		if err := m.simulateTransaction(ctx, i, tx, tb); err != nil {
+			return nil, err
+		}
+	}
+	return tb.receipts, nil
+}
+
+// simulateTransaction executes the transaction and appends its receipt
+// to the trace block.
+func (m *manager) simulateTransaction(ctx contract.Context, idx int, tx transaction.Transaction, tb *simulationTraceBlock) error {
+	txh, err := tx.GetHandler(m.cm)
+	if err != nil {
+		return err
+	}
+	defer txh.Dispose()
+
+	txInfo := &state.TransactionInfo{
+		Group:     module.TransactionGroupNormal,
+		Index:     int32(idx),
+		Hash:      tx.ID(),
+		From:      tx.From(),
+		Timestamp: tx.Timestamp(),
+		Nonce:     tx.Nonce(),
+	}
+	ctx.SetTransactionInfo(txInfo)
+	ctx.UpdateSystemInfo()
+	wcs := ctx.GetSnapshot()
+
+	tlog := ctx.GetTraceLogger(module.EPhaseTransaction)
+	tlog.OnTransactionStart(idx, tx.ID())
+	rct, err := txh.Execute(ctx, wcs, true)
+	if err != nil {
+		return err
+	}
+	if err := m.plt.OnTransactionEnd(ctx, m.log, rct); err != nil {
+		return err
+	}
+	// the tracer gets the receipt through the trace block
+	tb.receipts = append(tb.receipts, rct)
+	tlog.OnTransactionEnd(idx, tx.ID(), txInfo.From, ctx.Treasury(), ctx.Revision(), rct)
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright 2022 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+type testSimulatorPlatform struct {
+	testPlatform
+}
+
+func (p *testSimulatorPlatform) ToRevision(value int) module.Revision {
+	return module.LatestRevision
+}
+
+func (p *testSimulatorPlatform) OnTransactionEnd(wc state.WorldContext, logger log.Logger, rct txresult.Receipt) error {
+	return nil
+}
+
+func TestManager_OverrideWorldSnapshot(t *testing.T) {
+	addr := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	ws := state.NewWorldState(db.NewMapDB(), nil, nil, nil, nil)
+	as := ws.GetAccountState(addr.ID())
+	as.SetBalance(big.NewInt(100))
+	_, err := as.SetValue([]byte("key1"), []byte("value1"))
+	assert.NoError(t, err)
+	_, err = as.SetValue([]byte("key2"), []byte("value2"))
+	assert.NoError(t, err)
+	wss := ws.GetSnapshot()
+
+	m := &manager{log: log.New(), plt: &testSimulatorPlatform{}}
+	bi := common.NewBlockInfo(10, 1000)
+	wss2, err := m.overrideWorldSnapshot(wss, bi, []*AccountOverride{{
+		Address: addr,
+		Balance: big.NewInt(200),
+		Storage: map[string][]byte{
+			"key1": []byte("value3"),
+			"key2": nil,
+		},
+	}})
+	assert.NoError(t, err)
+
+	ass := wss2.GetAccountSnapshot(addr.ID())
+	assert.Equal(t, big.NewInt(200), ass.GetBalance())
+	v, err := ass.GetValue([]byte("key1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value3"), v)
+	v, err = ass.GetValue([]byte("key2"))
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	// the original snapshot is not changed
+	ass = wss.GetAccountSnapshot(addr.ID())
+	assert.Equal(t, big.NewInt(100), ass.GetBalance())
+	v, err = ass.GetValue([]byte("key1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value1"), v)
+
+	_, err = m.overrideWorldSnapshot(wss, bi, []*AccountOverride{{
+		Address: addr,
+		Balance: big.NewInt(-1),
+	}})
+	assert.True(t, scoreresult.InvalidParameterError.Equals(err))
+
+	_, err = m.overrideWorldSnapshot(wss, bi, []*AccountOverride{{
+		Address:     addr,
+		Code:        []byte("code"),
+		ContentType: state.CTAppJava,
+	}})
+	assert.True(t, scoreresult.InvalidParameterError.Equals(err))
+}
+
+func TestSimulationTraceBlock_GetReceipt(t *testing.T) {
+	tb := &simulationTraceBlock{}
+	assert.Nil(t, tb.ID())
+	assert.Nil(t, tb.GetReceipt(0))
+	assert.Nil(t, tb.GetReceipt(-1))
+}
+
+func newTestTransferJSON(t *testing.T, from module.Wallet, to module.Address, value int64, nonce int64) []byte {
+	tx := map[string]interface{}{
+		"version":   "0x3",
+		"from":      from.Address(),
+		"to":        to,
+		"value":     fmt.Sprintf("%#x", value),
+		"stepLimit": "0x100000",
+		"timestamp": "0x5ba1b2c3d4e5f",
+		"nid":       "0x1",
+		"nonce":     fmt.Sprintf("%#x", nonce),
+	}
+	js, err := json.Marshal(tx)
+	assert.NoError(t, err)
+	return js
+}
+
+func TestManager_SimulateTransactions(t *testing.T) {
+	dbase := db.NewMapDB()
+	w1, w2 := wallet.New(), wallet.New()
+	addr3 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000003")
+
+	// only the first account has balance
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+	ws.GetAccountState(w1.Address().ID()).SetBalance(big.NewInt(1000))
+	sys := ws.GetAccountState(state.SystemID)
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarStepPrice).Set(0))
+	assert.NoError(t, ws.GetSnapshot().Flush())
+	result := (&transitionResult{StateHash: ws.GetSnapshot().StateHash()}).Bytes()
+
+	plt := &testSimulatorPlatform{}
+	cm, err := contract.NewContractManager(dbase, t.TempDir(), log.New())
+	assert.NoError(t, err)
+	m := &manager{
+		db:  dbase,
+		cm:  cm,
+		plt: plt,
+		trc: newTransitionResultCache(dbase, plt, 10, 10, log.New()),
+		log: log.New(),
+	}
+
+	// the second transaction spends the balance transferred by the first
+	txs := [][]byte{
+		newTestTransferJSON(t, w1, w2.Address(), 300, 1),
+		newTestTransferJSON(t, w2, addr3, 200, 2),
+	}
+	bi := common.NewBlockInfo(10, 1000)
+	rcts, err := m.SimulateTransactions(result, nil, txs, bi, nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, rcts, 2)
+	assert.Equal(t, module.StatusSuccess, rcts[0].Status())
+	assert.Equal(t, module.StatusSuccess, rcts[1].Status())
+
+	// without the first one, the second one fails
+	rcts, err = m.SimulateTransactions(result, nil, txs[1:], bi, nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, rcts, 1)
+	assert.Equal(t, module.StatusOutOfBalance, rcts[0].Status())
+
+	// nothing is committed
+	wss, err := m.trc.GetWorldSnapshot(result, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000), wss.GetAccountSnapshot(w1.Address().ID()).GetBalance())
+	assert.Nil(t, wss.GetAccountSnapshot(addr3.ID()))
+}
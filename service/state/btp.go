@@ -54,6 +54,7 @@ type BTPContext interface {
 	btp.StateView
 	Store() containerdb.BytesStoreState
 	BlockHeight() int64
+	Revision() module.Revision
 	GetValidatorState() ValidatorState
 	GetNetworkTypeIDs() ([]int64, error)
 	GetNetworkTypeIDByName(name string) int64
@@ -94,6 +95,13 @@ func (bc *btpContext) BlockHeight() int64 {
 	return bc.wc.BlockHeight()
 }
 
+func (bc *btpContext) Revision() module.Revision {
+	if bc.wc == nil {
+		return module.NoRevision
+	}
+	return bc.wc.Revision()
+}
+
 func (bc *btpContext) GetValidatorState() ValidatorState {
 	if bc.wc == nil {
 		return nil
@@ -442,7 +450,7 @@ func (bs *BTPStateImpl) OpenNetwork(
 	bc BTPContext, networkTypeName string, name string, owner module.Address,
 ) (ntid int64, nid int64, err error) {
 	mod := ntm.ForUID(networkTypeName)
-	if mod == nil {
+	if mod == nil || !ntm.IsEnabled(networkTypeName, bc.Revision()) {
 		err = scoreresult.InvalidParameterError.Errorf("Not supported BTP network type %s", networkTypeName)
 		return
 	}
@@ -578,7 +586,7 @@ func (bs *BTPStateImpl) HandleMessage(bc BTPContext, from module.Address, nid in
 
 func (bs *BTPStateImpl) SetPublicKey(bc BTPContext, from module.Address, name string, pubKey []byte) error {
 	dsa := ntm.DSAModuleForName(name)
-	if dsa == nil {
+	if dsa == nil || !ntm.IsEnabled(name, bc.Revision()) {
 		return scoreresult.InvalidParameterError.Errorf("Invalid name %s", name)
 	}
 	ownerDB := scoredb.NewDictDB(bc.Store(), PubKeyOwner, 1)
@@ -42,6 +42,7 @@ func (cs ContractStatus) String() string {
 const (
 	CTAppZip    = "application/zip"
 	CTAppJava   = "application/java"
+	CTAppWasm   = "application/wasm"
 	CTAppSystem = "application/x.score.system"
 )
 
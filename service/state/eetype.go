@@ -14,6 +14,7 @@ const (
 	NullEE   EEType = ""
 	PythonEE EEType = "python"
 	JavaEE   EEType = "java"
+	WasmEE   EEType = "wasm"
 	SystemEE EEType = "system"
 )
 
@@ -25,11 +26,13 @@ var (
 	installMethods = map[EEType]string{
 		PythonEE: "on_install",
 		JavaEE:   "<init>",
+		WasmEE:   "on_install",
 		SystemEE: "<Install>",
 	}
 	updateMethods = map[EEType]string{
 		PythonEE: "on_update",
 		JavaEE:   "<init>",
+		WasmEE:   "on_update",
 		SystemEE: "<Update>",
 	}
 	allowUpdateFromTo = map[EEType]map[EEType]bool{
@@ -40,6 +43,9 @@ var (
 		JavaEE: {
 			JavaEE: true,
 		},
+		WasmEE: {
+			WasmEE: true,
+		},
 	}
 	needAudit = map[EEType]bool{
 		PythonEE: true,
@@ -104,6 +110,8 @@ func EETypeFromContentType(ct string) (EEType, bool) {
 		return PythonEE, true
 	case CTAppJava:
 		return JavaEE, true
+	case CTAppWasm:
+		return WasmEE, true
 	case CTAppSystem:
 		return SystemEE, true
 	default:
@@ -121,7 +129,7 @@ func MustEETypeFromContentType(ct string) EEType {
 
 func ValidateEEType(et EEType) bool {
 	switch et {
-	case PythonEE, JavaEE, SystemEE:
+	case PythonEE, JavaEE, WasmEE, SystemEE:
 		return true
 	default:
 		return false
@@ -145,6 +153,14 @@ func (ts allEETypes) String() string {
 
 var AllEETypes EETypes = allEETypes{}
 
+// DefaultEETypes are enabled if the chain doesn't configure them.
+// WasmEE needs to be enabled explicitly.
+var DefaultEETypes EETypes = EETypeFilter{
+	PythonEE: true,
+	JavaEE:   true,
+	SystemEE: true,
+}
+
 type EETypeFilter map[EEType]bool
 
 func (ets EETypeFilter) Contains(et EEType) bool {
@@ -0,0 +1,38 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEETypes_Wasm(t *testing.T) {
+	// chains should enable wasm explicitly
+	assert.False(t, DefaultEETypes.Contains(WasmEE))
+	assert.True(t, DefaultEETypes.Contains(PythonEE))
+	assert.True(t, DefaultEETypes.Contains(JavaEE))
+
+	ets, err := ParseEETypes("python,java,wasm")
+	assert.NoError(t, err)
+	assert.True(t, ets.Contains(WasmEE))
+	assert.Equal(t, "java,python,wasm", ets.String())
+
+	_, err = ParseEETypes("python,unknown")
+	assert.Error(t, err)
+}
@@ -0,0 +1,96 @@
+package state
+
+import (
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/trie"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+)
+
+// AccountProver is implemented by WorldSnapshot able to provide
+// the proof of the account.
+type AccountProver interface {
+	// GetAccountProof returns the proof of the account in the world
+	// state. The last node of the proof includes the account.
+	// If there is no account for the id, it returns the proof of the
+	// exclusion, the path to the node where the key diverges.
+	GetAccountProof(id []byte) [][]byte
+}
+
+// StorageProver is implemented by AccountSnapshot able to provide
+// the proof of the value in the storage.
+type StorageProver interface {
+	StorageHash() []byte
+
+	// GetStorageProof returns the proof of the value for the key in the
+	// storage. The last node of the proof includes the value.
+	// If there is no value for the key, it returns the proof of the
+	// exclusion. It returns nil if the storage is empty.
+	GetStorageProof(key []byte) [][]byte
+}
+
+func (ws *worldSnapshotImpl) GetAccountProof(id []byte) [][]byte {
+	return ws.accounts.GetProofOrExclusion(addressIDToKey(id))
+}
+
+func (s *accountSnapshotImpl) StorageHash() []byte {
+	if s.store == nil {
+		return nil
+	}
+	return s.store.(trie.Immutable).Hash()
+}
+
+func (s *accountSnapshotImpl) GetStorageProof(key []byte) [][]byte {
+	if s.store == nil {
+		return nil
+	}
+	return s.store.(trie.Immutable).GetProofOrExclusion(key)
+}
+
+// ProveAccount returns the account of the id proven by the proof against
+// the state hash. The returned account doesn't have its storage, so use
+// ProveStorage with its storage hash for the values in the storage.
+// It returns nil if the proof is the proof of the exclusion.
+func ProveAccount(stateHash []byte, id []byte, proof [][]byte) (AccountSnapshot, error) {
+	if len(stateHash) == 0 || len(proof) == 0 {
+		return nil, errors.IllegalArgumentError.New("EmptyStateHashOrProof")
+	}
+	accounts := trie_manager.NewImmutableForObject(db.NewMapDB(), stateHash, AccountType)
+	obj, err := accounts.Prove(addressIDToKey(id), proof)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidAccountProof(id=%x)", id)
+	}
+	ass, ok := obj.(*accountSnapshotImpl)
+	if !ok || ass == nil {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidAccountProof(id=%x)", id)
+	}
+	return ass, nil
+}
+
+// ProveStorage returns the value for the key proven by the proof against
+// the storage hash of the account. It returns nil if the proof is the proof
+// of the exclusion, or if the storage is empty (no storage hash) and the
+// proof is empty.
+func ProveStorage(storageHash []byte, key []byte, proof [][]byte) ([]byte, error) {
+	if len(storageHash) == 0 {
+		if len(proof) != 0 {
+			return nil, errors.IllegalArgumentError.Errorf("InvalidStorageProof(key=%x)", key)
+		}
+		return nil, nil
+	}
+	if len(proof) == 0 {
+		return nil, errors.IllegalArgumentError.New("EmptyProof")
+	}
+	store := trie_manager.NewImmutable(db.NewMapDB(), storageHash)
+	value, err := store.Prove(key, proof)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidStorageProof(key=%x)", key)
+	}
+	return value, nil
+}
@@ -0,0 +1,109 @@
+package state
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+)
+
+func TestProveAccountAndStorage(t *testing.T) {
+	database := db.NewMapDB()
+	ws := NewWorldState(database, nil, nil, nil, nil)
+	for i := 0; i < 20; i++ {
+		as := ws.GetAccountState([]byte(fmt.Sprintf("account%d", i)))
+		as.SetBalance(big.NewInt(int64(i + 1)))
+	}
+	id := []byte("account7")
+	as := ws.GetAccountState(id)
+	for i := 0; i < 20; i++ {
+		_, err := as.SetValue([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i)))
+		assert.NoError(t, err)
+	}
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+
+	prover, ok := wss.(AccountProver)
+	assert.True(t, ok)
+	proof := prover.GetAccountProof(id)
+	assert.NotEmpty(t, proof)
+
+	ass, err := ProveAccount(wss.StateHash(), id, proof)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(8), ass.GetBalance())
+
+	_, err = ProveAccount(wss.StateHash(), []byte("account8"), proof)
+	assert.Error(t, err)
+	_, err = ProveAccount(wss.StateHash(), id, proof[1:])
+	assert.Error(t, err)
+
+	sp, ok := wss.GetAccountSnapshot(id).(StorageProver)
+	assert.True(t, ok)
+	storageHash := ass.(StorageProver).StorageHash()
+	assert.Equal(t, sp.StorageHash(), storageHash)
+
+	key := []byte("key3")
+	sproof := sp.GetStorageProof(key)
+	assert.NotEmpty(t, sproof)
+
+	value, err := ProveStorage(storageHash, key, sproof)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value3"), value)
+
+	_, err = ProveStorage(wss.StateHash(), key, sproof)
+	assert.Error(t, err)
+}
+
+func TestProveExclusion(t *testing.T) {
+	database := db.NewMapDB()
+	ws := NewWorldState(database, nil, nil, nil, nil)
+	for i := 0; i < 20; i++ {
+		as := ws.GetAccountState([]byte(fmt.Sprintf("account%d", i)))
+		as.SetBalance(big.NewInt(int64(i + 1)))
+	}
+	id := []byte("account7")
+	as := ws.GetAccountState(id)
+	for i := 0; i < 20; i++ {
+		_, err := as.SetValue([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i)))
+		assert.NoError(t, err)
+	}
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+	prover := wss.(AccountProver)
+
+	// absent account
+	unknown := []byte("unknown")
+	proof := prover.GetAccountProof(unknown)
+	assert.NotEmpty(t, proof)
+	ass, err := ProveAccount(wss.StateHash(), unknown, proof)
+	assert.NoError(t, err)
+	assert.Nil(t, ass)
+	_, err = ProveAccount(wss.StateHash(), id, proof)
+	assert.Error(t, err)
+
+	// absent value
+	sp := wss.GetAccountSnapshot(id).(StorageProver)
+	key := []byte("key20")
+	sproof := sp.GetStorageProof(key)
+	assert.NotEmpty(t, sproof)
+	value, err := ProveStorage(sp.StorageHash(), key, sproof)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+	_, err = ProveStorage(sp.StorageHash(), key, sproof[:len(sproof)-1])
+	assert.Error(t, err)
+	_, err = ProveStorage(wss.StateHash(), key, sproof)
+	assert.Error(t, err)
+
+	// account without storage
+	sp = wss.GetAccountSnapshot([]byte("account8")).(StorageProver)
+	assert.Empty(t, sp.StorageHash())
+	assert.Nil(t, sp.GetStorageProof(key))
+	value, err = ProveStorage(sp.StorageHash(), key, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+	_, err = ProveStorage(sp.StorageHash(), key, sproof)
+	assert.Error(t, err)
+}
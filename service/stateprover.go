@@ -0,0 +1,84 @@
+/*
+ * Copyright 2022 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// StateProver provides proofs of the world state for light clients.
+type StateProver interface {
+	// GetProofForState returns the proof of the account in the world state
+	// of the result, followed by proofs of the values for the keys in the
+	// storage of the account. Proofs of the exclusion are returned for
+	// the absent account and values, and proofs of the values are empty
+	// if the account or its storage doesn't exist.
+	GetProofForState(result []byte, addr module.Address, keys [][]byte) ([][][]byte, error)
+}
+
+func StateProverOf(c module.Chain) (StateProver, error) {
+	mgr := managerOf(c)
+	if mgr == nil {
+		return nil, errors.UnsupportedError.New("NoStateProver")
+	}
+	return mgr, nil
+}
+
+func (m *manager) GetProofForState(result []byte, addr module.Address, keys [][]byte) ([][][]byte, error) {
+	wss, err := m.trc.GetWorldSnapshot(result, nil)
+	if err != nil {
+		return nil, err
+	}
+	ap, ok := wss.(state.AccountProver)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("NoAccountProver(type=%T)", wss)
+	}
+	proof := ap.GetAccountProof(addr.ID())
+	if proof == nil {
+		return nil, errors.InvalidStateError.Errorf("NoAccountProof(addr=%s)", addr)
+	}
+	proofs := make([][][]byte, 0, len(keys)+1)
+	proofs = append(proofs, proof)
+	if len(keys) == 0 {
+		return proofs, nil
+	}
+
+	ass := wss.GetAccountSnapshot(addr.ID())
+	if ass == nil {
+		for range keys {
+			proofs = append(proofs, [][]byte{})
+		}
+		return proofs, nil
+	}
+	sp, ok := ass.(state.StorageProver)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("NoStorageProver(type=%T)", ass)
+	}
+	for _, key := range keys {
+		proof := sp.GetStorageProof(key)
+		if proof == nil {
+			if len(sp.StorageHash()) != 0 {
+				return nil, errors.InvalidStateError.Errorf("NoStorageProof(addr=%s,key=%#x)", addr, key)
+			}
+			proof = [][]byte{}
+		}
+		proofs = append(proofs, proof)
+	}
+	return proofs, nil
+}
@@ -0,0 +1,219 @@
+package trace
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+type callNode struct {
+	parent *callNode
+	depth  int
+	info   *module.TraceCallInfo
+	done   bool
+	status error
+	steps  *big.Int
+	result interface{}
+	events []module.EventLog
+	calls  []*callNode
+}
+
+func (n *callNode) toJSON() map[string]interface{} {
+	jso := make(map[string]interface{})
+	if info := n.info; info != nil {
+		if info.From != nil {
+			jso["from"] = info.From
+		}
+		if info.To != nil {
+			jso["to"] = info.To
+		}
+		if info.Value != nil {
+			jso["value"] = &common.HexInt{Int: *info.Value}
+		}
+		if len(info.Method) > 0 {
+			jso["method"] = info.Method
+		}
+		if info.Params != nil {
+			jso["params"] = info.Params
+		}
+	}
+	if n.done {
+		if n.status == nil {
+			jso["status"] = "0x1"
+			if n.result != nil {
+				jso["result"] = n.result
+			}
+		} else {
+			jso["status"] = "0x0"
+			status, _ := scoreresult.StatusOf(n.status)
+			jso["failure"] = map[string]interface{}{
+				"code":    status,
+				"message": n.status.Error(),
+			}
+		}
+		if n.steps != nil {
+			jso["stepUsed"] = &common.HexInt{Int: *n.steps}
+		}
+	}
+	if len(n.events) > 0 {
+		jso["eventLogs"] = n.events
+	}
+	if len(n.calls) > 0 {
+		calls := make([]interface{}, len(n.calls))
+		for i, c := range n.calls {
+			calls[i] = c.toJSON()
+		}
+		jso["calls"] = calls
+	}
+	return jso
+}
+
+type callTreeTx struct {
+	index     int
+	hash      []byte
+	isBlockTx bool
+	root      *callNode
+}
+
+func (t *callTreeTx) toJSON() map[string]interface{} {
+	prefix := "0x"
+	if t.isBlockTx {
+		prefix = "bx"
+	}
+	calls := make([]interface{}, len(t.root.calls))
+	for i, c := range t.root.calls {
+		calls[i] = c.toJSON()
+	}
+	return map[string]interface{}{
+		"txIndex": fmt.Sprintf("%#x", t.index),
+		"txHash":  prefix + hex.EncodeToString(t.hash),
+		"calls":   calls,
+	}
+}
+
+// CallTracer builds trees of calls made by transactions with frame
+// notifications.
+type CallTracer struct {
+	txs []*callTreeTx
+	cur *callNode
+}
+
+func (ct *CallTracer) getCurrentTx() (*callTreeTx, error) {
+	if len(ct.txs) == 0 {
+		return nil, errors.InvalidStateError.New("No transaction")
+	}
+	return ct.txs[len(ct.txs)-1], nil
+}
+
+func (ct *CallTracer) OnTransactionStart(txIndex int, txHash []byte, isBlockTx bool) error {
+	if ct.cur != nil {
+		return errors.InvalidStateError.Errorf(
+			"Invalid curFrame: txIndex=%d txHash=%#x", txIndex, txHash)
+	}
+	root := &callNode{}
+	ct.txs = append(ct.txs, &callTreeTx{
+		index:     txIndex,
+		hash:      txHash,
+		isBlockTx: isBlockTx,
+		root:      root,
+	})
+	ct.cur = root
+	return nil
+}
+
+func (ct *CallTracer) OnTransactionReset() error {
+	tx, err := ct.getCurrentTx()
+	if err != nil {
+		return err
+	}
+	tx.root = &callNode{}
+	ct.cur = tx.root
+	return nil
+}
+
+func (ct *CallTracer) OnTransactionEnd(txIndex int, txHash []byte) error {
+	tx, err := ct.getCurrentTx()
+	if err != nil {
+		return err
+	}
+	if tx.index != txIndex {
+		return errors.InvalidStateError.Errorf(
+			"Invalid txIndex: curTxIndex=%d txIndex=%d", tx.index, txIndex)
+	}
+	// frames may not be exited on critical failures like timeout.
+	ct.cur = nil
+	return nil
+}
+
+func (ct *CallTracer) OnFrameEnter() error {
+	if ct.cur == nil {
+		return errors.InvalidStateError.New("CallTracer Not Ready")
+	}
+	node := &callNode{
+		parent: ct.cur,
+		depth:  ct.cur.depth + 1,
+	}
+	ct.cur.calls = append(ct.cur.calls, node)
+	ct.cur = node
+	return nil
+}
+
+func (ct *CallTracer) OnFrameExit(success bool) error {
+	if ct.cur == nil {
+		return errors.InvalidStateError.New("curFrame Not Ready")
+	}
+	if ct.cur.depth <= 0 {
+		return errors.InvalidStateError.Errorf("Invalid frameDepth: %d", ct.cur.depth)
+	}
+	ct.cur = ct.cur.parent
+	return nil
+}
+
+func (ct *CallTracer) OnCallStart(info *module.TraceCallInfo) error {
+	if ct.cur == nil || ct.cur.depth <= 0 {
+		return errors.InvalidStateError.New("NoFrameForCall")
+	}
+	ct.cur.info = info
+	return nil
+}
+
+func (ct *CallTracer) OnCallEnd(status error, stepUsed *big.Int, result interface{}) error {
+	if ct.cur == nil || ct.cur.depth <= 0 {
+		return errors.InvalidStateError.New("NoFrameForCall")
+	}
+	ct.cur.done = true
+	ct.cur.status = status
+	if stepUsed != nil {
+		ct.cur.steps = new(big.Int).Set(stepUsed)
+	}
+	ct.cur.result = result
+	return nil
+}
+
+func (ct *CallTracer) OnEvent(log module.EventLog) error {
+	if ct.cur == nil {
+		return errors.InvalidStateError.New("CallTracer Not Ready")
+	}
+	ct.cur.events = append(ct.cur.events, log)
+	return nil
+}
+
+// ToJSON returns call trees of transactions.
+func (ct *CallTracer) ToJSON() []map[string]interface{} {
+	jso := make([]map[string]interface{}, len(ct.txs))
+	for i, tx := range ct.txs {
+		jso[i] = tx.toJSON()
+	}
+	return jso
+}
+
+func NewCallTracer(capacity int) *CallTracer {
+	return &CallTracer{
+		txs: make([]*callTreeTx, 0, capacity),
+	}
+}
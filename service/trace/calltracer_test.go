@@ -0,0 +1,101 @@
+package trace
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+func TestCallTracer_Nested(t *testing.T) {
+	ct := NewCallTracer(1)
+	txHash := newRandomHash(32)
+	eoa := common.MustNewAddressFromString("hx100")
+	score1 := common.MustNewAddressFromString("cx101")
+	score2 := common.MustNewAddressFromString("cx102")
+
+	assert.NoError(t, ct.OnTransactionStart(0, txHash, false))
+
+	assert.NoError(t, ct.OnFrameEnter())
+	assert.NoError(t, ct.OnCallStart(&module.TraceCallInfo{
+		From:   eoa,
+		To:     score1,
+		Value:  big.NewInt(0),
+		Method: "deposit",
+		Params: map[string]interface{}{"amount": "0x10"},
+	}))
+
+	assert.NoError(t, ct.OnFrameEnter())
+	assert.NoError(t, ct.OnCallStart(&module.TraceCallInfo{
+		From:   score1,
+		To:     score2,
+		Value:  big.NewInt(0x10),
+		Method: "stake",
+	}))
+	assert.NoError(t, ct.OnEvent(txresult.NewEventLog(score2, [][]byte{[]byte("Staked(int)")}, [][]byte{{0x10}})))
+	assert.NoError(t, ct.OnCallEnd(scoreresult.ErrInvalidParameter, big.NewInt(100), nil))
+	assert.NoError(t, ct.OnFrameExit(false))
+
+	assert.NoError(t, ct.OnCallEnd(nil, big.NewInt(300), "0x1"))
+	assert.NoError(t, ct.OnFrameExit(true))
+
+	assert.NoError(t, ct.OnTransactionEnd(0, txHash))
+
+	jso := ct.ToJSON()
+	assert.Len(t, jso, 1)
+	bs, err := json.Marshal(jso[0]["calls"])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+		{
+			"from": "hx0000000000000000000000000000000000000100",
+			"to": "cx0000000000000000000000000000000000000101",
+			"value": "0x0",
+			"method": "deposit",
+			"params": {"amount": "0x10"},
+			"status": "0x1",
+			"result": "0x1",
+			"stepUsed": "0x12c",
+			"calls": [
+				{
+					"from": "cx0000000000000000000000000000000000000101",
+					"to": "cx0000000000000000000000000000000000000102",
+					"value": "0x10",
+					"method": "stake",
+					"status": "0x0",
+					"failure": {"code": 6, "message": "InvalidParameter"},
+					"stepUsed": "0x64",
+					"eventLogs": [
+						{
+							"scoreAddress": "cx0000000000000000000000000000000000000102",
+							"indexed": ["Staked(int)"],
+							"data": ["0x10"]
+						}
+					]
+				}
+			]
+		}
+	]`, string(bs))
+}
+
+func TestCallTracer_InvalidState(t *testing.T) {
+	ct := NewCallTracer(1)
+	assert.Error(t, ct.OnFrameEnter())
+	assert.Error(t, ct.OnCallStart(&module.TraceCallInfo{}))
+
+	txHash := newRandomHash(32)
+	assert.NoError(t, ct.OnTransactionStart(0, txHash, false))
+	assert.Error(t, ct.OnFrameExit(true))
+	assert.Error(t, ct.OnCallEnd(nil, big.NewInt(0), nil))
+	assert.Error(t, ct.OnTransactionStart(1, txHash, false))
+
+	assert.NoError(t, ct.OnFrameEnter())
+	assert.NoError(t, ct.OnTransactionReset())
+	assert.NoError(t, ct.OnTransactionEnd(0, txHash))
+	assert.Empty(t, ct.ToJSON()[0]["calls"])
+}
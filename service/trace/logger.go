@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/txresult"
@@ -106,7 +108,7 @@ func (l *Logger) OnTransactionStart(txIndex int, txHash []byte) {
 	}
 
 	isBlockTx := txHash == nil
-	if isBlockTx && traceMode == module.TraceModeBalanceChange && l.traceBlock != nil {
+	if isBlockTx && l.traceBlock != nil {
 		txHash = l.traceBlock.ID()
 	}
 
@@ -206,6 +208,39 @@ func (l *Logger) OnFrameExit(success bool, stepUsed *big.Int) {
 	}
 }
 
+func (l *Logger) callTraceCallback() module.CallTraceCallback {
+	if l.TraceMode() != module.TraceModeCallTree {
+		return nil
+	}
+	cb, _ := l.cb.(module.CallTraceCallback)
+	return cb
+}
+
+func (l *Logger) OnCallStart(info *module.TraceCallInfo) {
+	if cb := l.callTraceCallback(); cb != nil {
+		if err := cb.OnCallStart(info); err != nil {
+			l.Warnf("OnCallStart() error: err=%#v", err)
+		}
+	}
+}
+
+func (l *Logger) OnCallEnd(status error, stepUsed *big.Int, result *codec.TypedObj) {
+	if cb := l.callTraceCallback(); cb != nil {
+		obj, _ := common.DecodeAnyForJSON(result)
+		if err := cb.OnCallEnd(status, stepUsed, obj); err != nil {
+			l.Warnf("OnCallEnd() error: err=%#v", err)
+		}
+	}
+}
+
+func (l *Logger) OnEvent(addr module.Address, indexed, data [][]byte) {
+	if cb := l.callTraceCallback(); cb != nil {
+		if err := cb.OnEvent(txresult.NewEventLog(addr, indexed, data)); err != nil {
+			l.Warnf("OnEvent() error: err=%#v", err)
+		}
+	}
+}
+
 func (l *Logger) OnBalanceChange(opType module.OpType, from, to module.Address, amount *big.Int) {
 	if l.TraceMode() == module.TraceModeNone {
 		return
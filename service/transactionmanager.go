@@ -29,6 +29,9 @@ type TransactionManager struct {
 	callback func()
 
 	txWaiters map[hashValue][]chan<- interface{}
+
+	listeners []*txListener
+	drops     txDropHistory
 }
 
 func (m *TransactionManager) getTxPool(g module.TransactionGroup) *TransactionPool {
@@ -133,6 +136,7 @@ func (m *TransactionManager) OnTxDrops(drops []TxDrop) {
 	defer m.lock.Unlock()
 
 	for _, drop := range drops {
+		m.drops.add(drop)
 		ws := m.removeWaitersInLock(drop.ID)
 		for _, c := range ws {
 			c <- drop.Err
@@ -211,6 +215,9 @@ func (m *TransactionManager) addInLock(tx transaction.Transaction, direct bool)
 	if err := pool.Add(tx, direct); err != nil {
 		return err
 	}
+	for _, l := range m.listeners {
+		l.cb(tx)
+	}
 	if m.callback != nil {
 		cb := m.callback
 		m.callback = nil
@@ -248,6 +255,46 @@ func (m *TransactionManager) SetPoolCapacityMonitor(pcm PoolCapacityMonitor) {
 	m.normalTxPool.SetPoolCapacityMonitor(pcm)
 }
 
+func (m *TransactionManager) AddTxListener(cb TxListener) func() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	l := &txListener{cb: cb}
+	m.listeners = append(m.listeners, l)
+	return func() {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+
+		for i, v := range m.listeners {
+			if v == l {
+				m.listeners = append(m.listeners[:i:i], m.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (m *TransactionManager) TxPoolStatus() []*TxPoolStatus {
+	pools := []*TransactionPool{m.patchTxPool, m.normalTxPool}
+	status := make([]*TxPoolStatus, 0, len(pools))
+	for _, p := range pools {
+		status = append(status, &TxPoolStatus{
+			Group:   p.group,
+			Size:    p.Size(),
+			Used:    p.Used(),
+			Senders: p.Senders(),
+		})
+	}
+	return status
+}
+
+func (m *TransactionManager) TxDropStatus() *TxDropStatus {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.drops.status()
+}
+
 func NewTransactionManager(nid int, tsc *TxTimestampChecker, ptp *TransactionPool, ntp *TransactionPool, tim TXIDManager, logger log.Logger) *TransactionManager {
 	txm := &TransactionManager{
 		nid:          nid,
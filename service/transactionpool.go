@@ -292,6 +292,20 @@ func (tp *TransactionPool) Used() int {
 	return tp.list.Len()
 }
 
+// Senders returns number of transactions in the pool for each sender.
+func (tp *TransactionPool) Senders() map[string]int {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	senders := make(map[string]int)
+	for e := tp.list.Front(); e != nil; e = e.Next() {
+		if from := e.Value().From(); from != nil {
+			senders[from.String()] += 1
+		}
+	}
+	return senders
+}
+
 func (tp *TransactionPool) SetTxManager(txm TxWaiterManager) {
 	tp.mutex.Lock()
 	defer tp.mutex.Unlock()
@@ -49,3 +49,24 @@ func TestTransactionPool_Add(t *testing.T) {
 		t.Error("Fail to add transaction with valid network ID")
 	}
 }
+
+func TestTransactionPool_Senders(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	logger := log.New()
+	lm, err := txlocator.NewManager(dbase, logger)
+	assert.NoError(t, err)
+	tim, _ := NewTXIDManager(lm, tsc, nil)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 5000, tim, &mockMonitor{}, logger)
+
+	addr1 := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	addr2 := common.MustNewAddressFromString("hx2222222222222222222222222222222222222222")
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx1"), addr1, 1), true))
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx2"), addr1, 2), true))
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx3"), addr2, 3), true))
+
+	assert.Equal(t, map[string]int{
+		addr1.String(): 2,
+		addr2.String(): 1,
+	}, pool.Senders())
+}
@@ -920,6 +920,10 @@ func (t *transition) executeTxs(l module.TransactionList, ctx contract.Context,
 		// it will skip skippable transactions
 		return t.executeTxsSequential(l, ctx, rctBuf)
 	}
+	if t.ti != nil {
+		// trace callbacks expect transactions to be executed in order
+		return t.executeTxsSequential(l, ctx, rctBuf)
+	}
 	if cc := t.chain.ConcurrencyLevel(); cc > 1 {
 		return t.executeTxsConcurrent(cc, l, ctx, rctBuf)
 	}
@@ -150,3 +150,11 @@ func BTPDigestHashFromResult(result []byte) ([]byte, error) {
 	}
 	return r.BTPData, nil
 }
+
+func ExtensionDataFromResult(result []byte) ([]byte, error) {
+	r, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return nil, err
+	}
+	return r.ExtensionData, nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2022 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	configTxDropHistorySize = 32
+)
+
+// TxListener is called with the transaction accepted into the pool.
+// It's called while the transaction manager is locked, so it must not block.
+type TxListener func(tx module.Transaction)
+
+type TxPoolStatus struct {
+	Group   module.TransactionGroup
+	Size    int
+	Used    int
+	Senders map[string]int
+}
+
+type TxDropStatus struct {
+	Reasons map[errors.Code]int
+	Recent  []TxDrop
+}
+
+// TxPoolInspector provides status of the transaction pools and
+// transactions accepted into them.
+type TxPoolInspector interface {
+	// AddTxListener registers the listener. It returns the function
+	// removing the listener.
+	AddTxListener(cb TxListener) func()
+
+	// TxPoolStatus returns status of the patch and normal pools.
+	TxPoolStatus() []*TxPoolStatus
+
+	// TxDropStatus returns number of dropped transactions for each error
+	// code and recently dropped transactions (oldest first).
+	TxDropStatus() *TxDropStatus
+}
+
+func TxPoolInspectorOf(c module.Chain) (TxPoolInspector, error) {
+	mgr := managerOf(c)
+	if mgr == nil {
+		return nil, errors.UnsupportedError.New("NoTxPoolInspector")
+	}
+	return mgr.tm, nil
+}
+
+type txListener struct {
+	cb TxListener
+}
+
+type txDropHistory struct {
+	reasons map[errors.Code]int
+	recent  []TxDrop
+	next    int
+}
+
+func (h *txDropHistory) add(d TxDrop) {
+	if h.reasons == nil {
+		h.reasons = make(map[errors.Code]int)
+	}
+	h.reasons[errors.CodeOf(d.Err)] += 1
+	if len(h.recent) < configTxDropHistorySize {
+		h.recent = append(h.recent, d)
+	} else {
+		h.recent[h.next] = d
+	}
+	h.next = (h.next + 1) % configTxDropHistorySize
+}
+
+func (h *txDropHistory) status() *TxDropStatus {
+	reasons := make(map[errors.Code]int, len(h.reasons))
+	for c, n := range h.reasons {
+		reasons[c] = n
+	}
+	recent := make([]TxDrop, 0, len(h.recent))
+	if len(h.recent) < configTxDropHistorySize {
+		recent = append(recent, h.recent...)
+	} else {
+		recent = append(recent, h.recent[h.next:]...)
+		recent = append(recent, h.recent[:h.next]...)
+	}
+	return &TxDropStatus{
+		Reasons: reasons,
+		Recent:  recent,
+	}
+}
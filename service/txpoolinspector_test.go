@@ -0,0 +1,93 @@
+/*
+ * Copyright 2022 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/txlocator"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestTxDropHistory(t *testing.T) {
+	var h txDropHistory
+	for i := 0; i < configTxDropHistorySize+2; i++ {
+		var err error
+		if i%2 == 0 {
+			err = ExpiredTransactionError.New("Expired")
+		} else {
+			err = errors.InvalidStateError.New("AlreadyProcessed")
+		}
+		h.add(TxDrop{ID: []byte(fmt.Sprint(i)), Err: err})
+	}
+	s := h.status()
+	assert.Equal(t, map[errors.Code]int{
+		ExpiredTransactionError:  configTxDropHistorySize/2 + 1,
+		errors.InvalidStateError: configTxDropHistorySize/2 + 1,
+	}, s.Reasons)
+	assert.Len(t, s.Recent, configTxDropHistorySize)
+	assert.Equal(t, []byte("2"), s.Recent[0].ID)
+	assert.Equal(t, []byte(fmt.Sprint(configTxDropHistorySize+1)), s.Recent[configTxDropHistorySize-1].ID)
+}
+
+func TestTransactionManager_TxPoolInspector(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	logger := log.New()
+	lm, err := txlocator.NewManager(dbase, logger)
+	assert.NoError(t, err)
+	tim, _ := NewTXIDManager(lm, tsc, nil)
+	ptp := NewTransactionPool(module.TransactionGroupPatch, 10, tim, &mockMonitor{}, logger)
+	ntp := NewTransactionPool(module.TransactionGroupNormal, 100, tim, &mockMonitor{}, logger)
+	tm := NewTransactionManager(1, tsc, ptp, ntp, tim, logger)
+
+	var txs []module.Transaction
+	remove := tm.AddTxListener(func(tx module.Transaction) {
+		txs = append(txs, tx)
+	})
+
+	addr := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	tx1 := newMockTransaction([]byte("tx1"), addr, 1)
+	assert.NoError(t, tm.Add(tx1, true, true))
+	assert.Equal(t, []module.Transaction{tx1}, txs)
+
+	remove()
+	tx2 := newMockTransaction([]byte("tx2"), addr, 2)
+	assert.NoError(t, tm.Add(tx2, true, true))
+	assert.Len(t, txs, 1)
+
+	status := tm.TxPoolStatus()
+	assert.Len(t, status, 2)
+	assert.Equal(t, module.TransactionGroupPatch, status[0].Group)
+	assert.Equal(t, 0, status[0].Used)
+	assert.Equal(t, module.TransactionGroupNormal, status[1].Group)
+	assert.Equal(t, 100, status[1].Size)
+	assert.Equal(t, 2, status[1].Used)
+	assert.Equal(t, map[string]int{addr.String(): 2}, status[1].Senders)
+
+	tm.OnTxDrops([]TxDrop{{tx1.ID(), ErrExpiredTransaction}})
+	ds := tm.TxDropStatus()
+	assert.Equal(t, map[errors.Code]int{ExpiredTransactionError: 1}, ds.Reasons)
+	assert.Equal(t, []TxDrop{{tx1.ID(), ErrExpiredTransaction}}, ds.Recent)
+}
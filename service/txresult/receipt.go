@@ -87,6 +87,14 @@ func (log *eventLog) Data() [][]byte {
 	return log.eventLogData.Data
 }
 
+func NewEventLog(addr module.Address, indexed, data [][]byte) module.EventLog {
+	log := new(eventLog)
+	log.eventLogData.Addr.Set(addr)
+	log.eventLogData.Indexed = indexed
+	log.eventLogData.Data = data
+	return log
+}
+
 func (log *eventLog) MarshalJSON() ([]byte, error) {
 	jso := log.ToJSON(module.JSONVersionLast)
 	return json.Marshal(jso)
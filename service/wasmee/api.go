@@ -0,0 +1,175 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wasmee
+
+import (
+	"encoding/json"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+const (
+	// APISection is the name of the custom section including API of
+	// the contract in JSON, same format as the result of icx_getScoreApi.
+	APISection = "icon:api"
+
+	InstallMethod = "on_install"
+	UpdateMethod  = "on_update"
+	FallbackName  = "fallback"
+)
+
+type apiParam struct {
+	Name    string          `json:"name"`
+	Type    string          `json:"type"`
+	Indexed string          `json:"indexed,omitempty"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+type apiOutput struct {
+	Type string `json:"type"`
+}
+
+type apiMethod struct {
+	Type     string      `json:"type"`
+	Name     string      `json:"name"`
+	Inputs   []apiParam  `json:"inputs"`
+	Outputs  []apiOutput `json:"outputs"`
+	ReadOnly string      `json:"readonly,omitempty"`
+	Payable  string      `json:"payable,omitempty"`
+}
+
+func isTrue(s string) bool {
+	return s == "0x1"
+}
+
+func dataTypeOf(s string) (scoreapi.DataType, error) {
+	t := scoreapi.DataTypeOf(s)
+	if t == scoreapi.Unknown || t.Tag() == scoreapi.TStruct {
+		return scoreapi.Unknown, scoreresult.InvalidPackageError.Errorf(
+			"UnsupportedType(type=%s)", s)
+	}
+	return t, nil
+}
+
+// bytesOf returns bytes representation of the typed object used for
+// event logs and default values of the parameters.
+func bytesOf(obj *codec.TypedObj) ([]byte, error) {
+	switch obj.Type {
+	case codec.TypeNil:
+		return nil, nil
+	case codec.TypeString:
+		return []byte(obj.Object.(string)), nil
+	case codec.TypeBytes, codec.TypeBool, common.TypeInt, common.TypeAddress:
+		return obj.Object.([]byte), nil
+	default:
+		return nil, scoreresult.InvalidParameterError.Errorf(
+			"InvalidValueType(type=%d)", obj.Type)
+	}
+}
+
+func (m *apiMethod) toMethod() (*scoreapi.Method, error) {
+	method := &scoreapi.Method{
+		Name: m.Name,
+	}
+	switch m.Type {
+	case "function":
+		if m.Name != InstallMethod && m.Name != UpdateMethod {
+			method.Flags |= scoreapi.FlagExternal
+		}
+		if isTrue(m.ReadOnly) {
+			method.Flags |= scoreapi.FlagReadOnly
+		}
+	case "fallback":
+		method.Type = scoreapi.Fallback
+		method.Name = FallbackName
+	case "eventlog":
+		method.Type = scoreapi.Event
+	default:
+		return nil, scoreresult.InvalidPackageError.Errorf(
+			"InvalidMethodType(name=%s,type=%s)", m.Name, m.Type)
+	}
+	if isTrue(m.Payable) {
+		method.Flags |= scoreapi.FlagPayable
+	}
+
+	optional := false
+	for _, input := range m.Inputs {
+		t, err := dataTypeOf(input.Type)
+		if err != nil {
+			return nil, err
+		}
+		param := scoreapi.Parameter{Name: input.Name, Type: t}
+		if method.Type == scoreapi.Event {
+			if isTrue(input.Indexed) {
+				if method.Indexed < len(method.Inputs) {
+					return nil, scoreresult.InvalidPackageError.Errorf(
+						"IndexedAfterData(name=%s)", m.Name)
+				}
+				method.Indexed += 1
+			}
+		} else if input.Default != nil {
+			obj, err := t.ConvertJSONToTypedObj(input.Default, nil, true)
+			if err != nil {
+				return nil, scoreresult.InvalidPackageError.Wrapf(err,
+					"InvalidDefault(name=%s,param=%s)", m.Name, input.Name)
+			}
+			if param.Default, err = bytesOf(obj); err != nil {
+				return nil, scoreresult.InvalidPackageError.Wrapf(err,
+					"InvalidDefault(name=%s,param=%s)", m.Name, input.Name)
+			}
+			optional = true
+		} else {
+			if optional {
+				return nil, scoreresult.InvalidPackageError.Errorf(
+					"RequiredAfterOptional(name=%s)", m.Name)
+			}
+			method.Indexed += 1
+		}
+		method.Inputs = append(method.Inputs, param)
+	}
+	if method.Type != scoreapi.Event {
+		for _, output := range m.Outputs {
+			t := scoreapi.DataTypeOf(output.Type)
+			if t == scoreapi.Unknown {
+				return nil, scoreresult.InvalidPackageError.Errorf(
+					"InvalidOutputType(name=%s,type=%s)", m.Name, output.Type)
+			}
+			method.Outputs = append(method.Outputs, t)
+		}
+	}
+	return method, nil
+}
+
+// parseAPI parses API of the contract in JSON.
+func parseAPI(bs []byte) ([]*scoreapi.Method, error) {
+	var methods []apiMethod
+	if err := json.Unmarshal(bs, &methods); err != nil {
+		return nil, scoreresult.InvalidPackageError.Wrap(err, "InvalidAPIFormat")
+	}
+	res := make([]*scoreapi.Method, 0, len(methods))
+	for i := range methods {
+		method, err := methods[i].toMethod()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, method)
+	}
+	return res, nil
+}
@@ -0,0 +1,313 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wasmee
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+// HostModule is the name of the module of the functions provided to
+// the contract.
+const HostModule = "env"
+
+type invocationKey struct{}
+
+type invocation struct {
+	host     Host
+	contract *contract
+	params   []byte
+	result   []byte
+	ret      []byte
+	err      error
+
+	steps api.MutableGlobal
+	given int64
+}
+
+func invocationOf(ctx context.Context) *invocation {
+	return ctx.Value(invocationKey{}).(*invocation)
+}
+
+// enter returns the invocation after charging the steps used by the
+// instructions to the host, because the function of the host may use
+// the steps left.
+func enter(ctx context.Context) *invocation {
+	inv := invocationOf(ctx)
+	inv.check(inv.syncSteps())
+	return inv
+}
+
+// leave gives the steps left in the host to the instructions again.
+func (inv *invocation) leave() {
+	inv.resetSteps()
+}
+
+// syncSteps charges the steps used by the instructions since the last
+// synchronization to the host.
+func (inv *invocation) syncSteps() error {
+	left := int64(inv.steps.Get())
+	used := inv.given - left
+	inv.given = left
+	if used > 0 {
+		return inv.host.DeductSteps(used)
+	}
+	return nil
+}
+
+func (inv *invocation) resetSteps() {
+	inv.given = inv.host.StepsLeft()
+	inv.steps.Set(uint64(inv.given))
+}
+
+// fail stops the execution of the contract with the error.
+func (inv *invocation) fail(err error) {
+	inv.err = err
+	panic(err)
+}
+
+func (inv *invocation) read(m api.Module, ptr, size uint32) []byte {
+	bs, ok := m.Memory().Read(ptr, size)
+	if !ok {
+		inv.fail(scoreresult.UnknownFailureError.Errorf(
+			"InvalidMemoryAccess(ptr=%d,size=%d)", ptr, size))
+	}
+	return append([]byte{}, bs...)
+}
+
+// setReturn keeps the data to be returned, and writes it to the buffer
+// if the buffer is large enough. It returns the size of the data, so the
+// contract may read it with read_return with larger buffer.
+func (inv *invocation) setReturn(m api.Module, ptr, size uint32, data []byte) int32 {
+	inv.ret = data
+	return inv.writeReturn(m, ptr, size)
+}
+
+func (inv *invocation) writeReturn(m api.Module, ptr, size uint32) int32 {
+	if inv.ret == nil {
+		return -1
+	}
+	if len(inv.ret) <= int(size) {
+		if !m.Memory().Write(ptr, inv.ret) {
+			inv.fail(scoreresult.UnknownFailureError.Errorf(
+				"InvalidMemoryAccess(ptr=%d,size=%d)", ptr, size))
+		}
+	}
+	return int32(len(inv.ret))
+}
+
+func (inv *invocation) check(err error) {
+	if err != nil {
+		inv.fail(err)
+	}
+}
+
+func (inv *invocation) eventOf(bs []byte) ([][]byte, [][]byte) {
+	var values []json.RawMessage
+	if err := json.Unmarshal(bs, &values); err != nil || len(values) == 0 {
+		inv.fail(scoreresult.InvalidParameterError.New("InvalidEventFormat"))
+	}
+	var sig string
+	if err := json.Unmarshal(values[0], &sig); err != nil {
+		inv.fail(scoreresult.InvalidParameterError.Wrap(err, "InvalidEventSignature"))
+	}
+	m := inv.contract.info.GetMethod(sig)
+	if m == nil || !m.IsEvent() {
+		inv.fail(scoreresult.InvalidParameterError.Errorf("EventNotFound(sig=%s)", sig))
+	}
+	if len(values)-1 != len(m.Inputs) {
+		inv.fail(scoreresult.InvalidParameterError.Errorf(
+			"InvalidEventArguments(sig=%s,exp=%d,real=%d)", sig, len(m.Inputs), len(values)-1))
+	}
+	indexed := [][]byte{[]byte(sig)}
+	var data [][]byte
+	for i, input := range m.Inputs {
+		obj, err := input.Type.ConvertJSONToTypedObj(values[i+1], input.Fields, true)
+		inv.check(err)
+		bs, err := bytesOf(obj)
+		inv.check(err)
+		if i < m.Indexed {
+			indexed = append(indexed, bs)
+		} else {
+			data = append(data, bs)
+		}
+	}
+	return indexed, data
+}
+
+type callParam struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+type callRequest struct {
+	To     common.Address `json:"to"`
+	Value  common.HexInt  `json:"value"`
+	Method string         `json:"method"`
+	Params []callParam    `json:"params"`
+}
+
+func (inv *invocation) call(bs []byte) []byte {
+	var req callRequest
+	if err := json.Unmarshal(bs, &req); err != nil {
+		inv.fail(scoreresult.InvalidParameterError.Wrap(err, "InvalidCallFormat"))
+	}
+	params := make([]interface{}, len(req.Params))
+	for i, p := range req.Params {
+		t := scoreapi.DataTypeOf(p.Type)
+		if t == scoreapi.Unknown {
+			inv.fail(scoreresult.InvalidParameterError.Errorf(
+				"InvalidParamType(type=%s)", p.Type))
+		}
+		obj, err := t.ConvertJSONToTypedObj(p.Value, nil, true)
+		inv.check(err)
+		params[i] = obj
+	}
+	paramObj, err := common.EncodeAny(params)
+	inv.check(err)
+	result, err := inv.host.Call(&req.To, req.Value.Value(), req.Method, paramObj)
+	inv.check(err)
+	jso, err := common.DecodeAnyForJSON(result)
+	inv.check(err)
+	ret, err := json.Marshal(jso)
+	inv.check(err)
+	return ret
+}
+
+func revertStatusOf(code int32) module.Status {
+	s := module.StatusReverted + module.Status(code)
+	if code < 0 || s > module.StatusLimit {
+		return module.StatusReverted
+	}
+	return s
+}
+
+// instantiateHostModule registers the functions provided to the contract.
+// Functions returning data return the size of the data, and write it to
+// the buffer only if the buffer is large enough. Then the contract may
+// get the data with read_return.
+func instantiateHostModule(ctx context.Context, rt wazero.Runtime) error {
+	_, err := rt.NewHostModuleBuilder(HostModule).
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, ptr, size uint32) int32 {
+			inv := enter(ctx)
+			defer inv.leave()
+			return inv.writeReturn(m, ptr, size)
+		}).
+		Export("read_return").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, ptr, size uint32) int32 {
+			inv := enter(ctx)
+			defer inv.leave()
+			return inv.setReturn(m, ptr, size, inv.params)
+		}).
+		Export("get_params").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, ptr, size uint32) {
+			inv := enter(ctx)
+			defer inv.leave()
+			inv.result = inv.read(m, ptr, size)
+		}).
+		Export("set_result").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, ptr, size uint32) int32 {
+			inv := enter(ctx)
+			defer inv.leave()
+			info, err := json.Marshal(inv.host.GetInfo())
+			inv.check(err)
+			return inv.setReturn(m, ptr, size, info)
+		}).
+		Export("get_info").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, kptr, ksize, ptr, size uint32) int32 {
+			inv := enter(ctx)
+			defer inv.leave()
+			value, err := inv.host.GetValue(inv.read(m, kptr, ksize))
+			inv.check(err)
+			return inv.setReturn(m, ptr, size, value)
+		}).
+		Export("get_value").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, kptr, ksize, vptr, vsize uint32) {
+			inv := enter(ctx)
+			defer inv.leave()
+			inv.check(inv.host.SetValue(inv.read(m, kptr, ksize), inv.read(m, vptr, vsize)))
+		}).
+		Export("set_value").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, kptr, ksize uint32) {
+			inv := enter(ctx)
+			defer inv.leave()
+			inv.check(inv.host.DeleteValue(inv.read(m, kptr, ksize)))
+		}).
+		Export("delete_value").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, aptr, asize, ptr, size uint32) int32 {
+			inv := enter(ctx)
+			defer inv.leave()
+			addr := new(common.Address)
+			if err := addr.SetStringStrict(string(inv.read(m, aptr, asize))); err != nil {
+				inv.fail(scoreresult.InvalidParameterError.Wrap(err, "InvalidAddress"))
+			}
+			balance, err := inv.host.GetBalance(addr)
+			inv.check(err)
+			value, err := json.Marshal(common.NewHexInt(0).SetValue(balance))
+			inv.check(err)
+			return inv.setReturn(m, ptr, size, value)
+		}).
+		Export("get_balance").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, ptr, size uint32) {
+			inv := enter(ctx)
+			defer inv.leave()
+			indexed, data := inv.eventOf(inv.read(m, ptr, size))
+			inv.check(inv.host.OnEvent(indexed, data))
+		}).
+		Export("emit_event").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, ptr, size, rptr, rsize uint32) int32 {
+			inv := enter(ctx)
+			defer inv.leave()
+			result := inv.call(inv.read(m, ptr, size))
+			return inv.setReturn(m, rptr, rsize, result)
+		}).
+		Export("call").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, code int32, ptr, size uint32) {
+			inv := enter(ctx)
+			defer inv.leave()
+			inv.fail(scoreresult.New(revertStatusOf(code), string(inv.read(m, ptr, size))))
+		}).
+		Export("revert").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, ptr, size uint32) {
+			inv := enter(ctx)
+			defer inv.leave()
+			inv.host.Log(string(inv.read(m, ptr, size)))
+		}).
+		Export("log").
+		Instantiate(ctx)
+	return err
+}
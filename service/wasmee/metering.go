@@ -0,0 +1,516 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wasmee
+
+import (
+	"bytes"
+
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+// StepsGlobal is the name of the global exported by the instrumented
+// module for the steps left for the execution.
+const StepsGlobal = "icon:steps"
+
+const (
+	secCustom    = 0
+	secImport    = 2
+	secGlobal    = 6
+	secExport    = 7
+	secCode      = 10
+	secDataCount = 12
+
+	importFunc   = 0
+	importTable  = 1
+	importMemory = 2
+	importGlobal = 3
+
+	exportGlobal = 3
+
+	typeI64       = 0x7e
+	typeEmptyType = 0x40
+)
+
+const (
+	opUnreachable = 0x00
+	opBlock       = 0x02
+	opLoop        = 0x03
+	opIf          = 0x04
+	opElse        = 0x05
+	opEnd         = 0x0b
+	opBr          = 0x0c
+	opBrIf        = 0x0d
+	opBrTable     = 0x0e
+	opCall        = 0x10
+	opCallInd     = 0x11
+	opSelectT     = 0x1c
+	opLocalGet    = 0x20
+	opGlobalGet   = 0x23
+	opGlobalSet   = 0x24
+	opTableGet    = 0x25
+	opTableSet    = 0x26
+	opI32Load     = 0x28
+	opF32Load     = 0x2a
+	opF64Load     = 0x2b
+	opF32Store    = 0x38
+	opF64Store    = 0x39
+	opI64Store32  = 0x3e
+	opMemorySize  = 0x3f
+	opMemoryGrow  = 0x40
+	opI32Const    = 0x41
+	opI64Const    = 0x42
+	opF32Const    = 0x43
+	opF64Const    = 0x44
+	opI32Eqz      = 0x45
+	opI64LtS      = 0x53
+	opF32Eq       = 0x5b
+	opF64Ge       = 0x66
+	opI64Sub      = 0x7d
+	opF32Abs      = 0x8b
+	opF64CopySign = 0xa6
+	opI32TruncF32 = 0xa8
+	opI32TruncF64 = 0xab
+	opI64TruncF32 = 0xae
+	opF64FromI64  = 0xbf
+	opI64Extend32 = 0xc4
+	opRefNull     = 0xd0
+	opRefIsNull   = 0xd1
+	opRefFunc     = 0xd2
+	opPrefixFC    = 0xfc
+)
+
+var wasmHeader = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+// sectionOrder returns the order of the known section, which is different
+// from the ID for the data count section.
+func sectionOrder(id byte) int {
+	switch {
+	case id == secDataCount:
+		return secCode
+	case id >= secCode:
+		return int(id) + 1
+	default:
+		return int(id)
+	}
+}
+
+func isValueType(b byte) bool {
+	switch b {
+	case 0x7f, 0x7e, 0x7d, 0x7c, 0x7b, 0x70, 0x6f:
+		return true
+	default:
+		return false
+	}
+}
+
+type wasmReader struct {
+	buf []byte
+	pos int
+	err error
+}
+
+func (r *wasmReader) fail() {
+	if r.err == nil {
+		r.err = scoreresult.InvalidPackageError.Errorf("InvalidModule(pos=%d)", r.pos)
+	}
+	r.pos = len(r.buf)
+}
+
+func (r *wasmReader) eof() bool {
+	return r.pos >= len(r.buf)
+}
+
+func (r *wasmReader) byte() byte {
+	if r.eof() {
+		r.fail()
+		return 0
+	}
+	b := r.buf[r.pos]
+	r.pos += 1
+	return b
+}
+
+func (r *wasmReader) bytes(n int) []byte {
+	if n < 0 || r.pos+n > len(r.buf) {
+		r.fail()
+		return nil
+	}
+	bs := r.buf[r.pos : r.pos+n]
+	r.pos += n
+	return bs
+}
+
+func (r *wasmReader) u32() uint32 {
+	var v uint32
+	for shift := 0; shift < 35; shift += 7 {
+		b := r.byte()
+		v |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return v
+		}
+	}
+	r.fail()
+	return 0
+}
+
+// skipLEB skips the integer in LEB128 without decoding it.
+func (r *wasmReader) skipLEB() {
+	for i := 0; i < 10; i++ {
+		if r.byte()&0x80 == 0 {
+			return
+		}
+	}
+	r.fail()
+}
+
+func (r *wasmReader) name() string {
+	return string(r.bytes(int(r.u32())))
+}
+
+func (r *wasmReader) limits() {
+	if flag := r.byte(); flag&1 != 0 {
+		r.u32()
+	}
+	r.u32()
+}
+
+func (r *wasmReader) blockType() {
+	if r.eof() {
+		r.fail()
+		return
+	}
+	if b := r.buf[r.pos]; b == typeEmptyType || isValueType(b) {
+		r.pos += 1
+		return
+	}
+	r.skipLEB()
+}
+
+func appendU32(bs []byte, v uint32) []byte {
+	for v >= 0x80 {
+		bs = append(bs, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(bs, byte(v))
+}
+
+func appendS64(bs []byte, v int64) []byte {
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			return append(bs, b)
+		}
+		bs = append(bs, b|0x80)
+	}
+}
+
+func appendSection(bs []byte, id byte, data []byte) []byte {
+	bs = append(bs, id)
+	bs = appendU32(bs, uint32(len(data)))
+	return append(bs, data...)
+}
+
+// appendCharge appends instructions deducting the steps from the global,
+// and trapping if the steps are not enough.
+func appendCharge(bs []byte, global uint32, steps int) []byte {
+	bs = appendU32(append(bs, opGlobalGet), global)
+	bs = appendS64(append(bs, opI64Const), int64(steps))
+	bs = append(bs, opI64Sub)
+	bs = appendU32(append(bs, opGlobalSet), global)
+	bs = appendU32(append(bs, opGlobalGet), global)
+	bs = append(bs, opI64Const, 0, opI64LtS)
+	return append(bs, opIf, typeEmptyType, opUnreachable, opEnd)
+}
+
+func countImportedGlobals(data []byte) (uint32, error) {
+	r := &wasmReader{buf: data}
+	var globals uint32
+	for n := r.u32(); n > 0 && r.err == nil; n-- {
+		r.name()
+		r.name()
+		switch r.byte() {
+		case importFunc:
+			r.u32()
+		case importTable:
+			r.byte()
+			r.limits()
+		case importMemory:
+			r.limits()
+		case importGlobal:
+			r.bytes(2)
+			globals += 1
+		default:
+			r.fail()
+		}
+	}
+	return globals, r.err
+}
+
+func addGlobal(data []byte) []byte {
+	r := &wasmReader{buf: data}
+	n := r.u32()
+	bs := appendU32(nil, n+1)
+	bs = append(bs, data[r.pos:]...)
+	// (global (mut i64) (i64.const 0))
+	return append(bs, typeI64, 1, opI64Const, 0, opEnd)
+}
+
+func addExport(data []byte, global uint32) ([]byte, error) {
+	r := &wasmReader{buf: data}
+	n := r.u32()
+	entries := r.pos
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		if r.name() == StepsGlobal {
+			return nil, scoreresult.InvalidPackageError.Errorf(
+				"ReservedExport(name=%s)", StepsGlobal)
+		}
+		r.byte()
+		r.u32()
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	bs := appendU32(nil, n+1)
+	bs = append(bs, data[entries:]...)
+	bs = appendU32(bs, uint32(len(StepsGlobal)))
+	bs = append(bs, StepsGlobal...)
+	bs = append(bs, exportGlobal)
+	return appendU32(bs, global), nil
+}
+
+// isFloatOp returns true for the instructions using floating-point numbers.
+// NaN bit patterns of their results may differ among engines and platforms,
+// so they are not supported.
+func isFloatOp(op byte) bool {
+	switch {
+	case op == opF32Load || op == opF64Load,
+		op == opF32Store || op == opF64Store,
+		op == opF32Const || op == opF64Const,
+		op >= opF32Eq && op <= opF64Ge,
+		op >= opF32Abs && op <= opF64CopySign,
+		op >= opI32TruncF32 && op <= opI32TruncF64,
+		op >= opI64TruncF32 && op <= opF64FromI64:
+		return true
+	default:
+		return false
+	}
+}
+
+// skipImmediates skips immediates of the instruction. It returns false
+// for the instructions not supported.
+func (r *wasmReader) skipImmediates(op byte, global uint32) bool {
+	switch {
+	case isFloatOp(op):
+		return false
+	case op == opBlock || op == opLoop || op == opIf:
+		r.blockType()
+	case op == opBr || op == opBrIf || op == opCall || op == opRefFunc:
+		r.u32()
+	case op == opBrTable:
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			r.u32()
+		}
+		r.u32()
+	case op == opCallInd:
+		r.u32()
+		r.u32()
+	case op == opSelectT:
+		r.bytes(int(r.u32()))
+	case op == opGlobalGet || op == opGlobalSet:
+		// the global for steps is only for the metering.
+		if r.u32() >= global {
+			return false
+		}
+	case op >= opLocalGet && op <= opTableSet:
+		r.u32()
+	case op >= opI32Load && op <= opI64Store32:
+		r.u32()
+		r.u32()
+	case op == opMemorySize || op == opMemoryGrow:
+		r.u32()
+	case op == opI32Const || op == opI64Const:
+		r.skipLEB()
+	case op == opRefNull:
+		r.byte()
+	case op == opPrefixFC:
+		return r.skipPrefixFC()
+	case op <= 0x01 || op == opElse || op == opEnd || op == 0x0f,
+		op == 0x1a || op == 0x1b || op == opRefIsNull,
+		op >= opI32Eqz && op <= opI64Extend32:
+	default:
+		return false
+	}
+	return true
+}
+
+// skipPrefixFC skips immediates of the instruction with the prefix 0xfc.
+// Saturating truncations of floating-point numbers (0-7) are not supported.
+func (r *wasmReader) skipPrefixFC() bool {
+	switch sub := r.u32(); {
+	case sub == 8:
+		r.u32()
+		r.u32()
+	case sub == 9 || sub == 11 || sub == 13 || sub >= 15 && sub <= 17:
+		r.u32()
+	case sub == 10 || sub == 12 || sub == 14:
+		r.u32()
+		r.u32()
+	default:
+		return false
+	}
+	return true
+}
+
+// instrumentBody inserts the charge of steps at the start of each block of
+// instructions executed sequentially. A block ends with the instruction
+// changing or joining the flow of the execution. Each instruction costs
+// one step.
+func instrumentBody(body []byte, global uint32) ([]byte, error) {
+	r := &wasmReader{buf: body}
+	for n := r.u32(); n > 0 && r.err == nil; n-- {
+		r.u32()
+		r.byte()
+	}
+	bs := append([]byte{}, body[:r.pos]...)
+	start, count := r.pos, 0
+	for !r.eof() {
+		op := r.byte()
+		if !r.skipImmediates(op, global) {
+			return nil, scoreresult.InvalidPackageError.Errorf(
+				"UnsupportedInstruction(op=%#x,pos=%d)", op, r.pos)
+		}
+		count += 1
+		switch op {
+		case opBlock, opLoop, opIf, opElse, opEnd, opBrIf:
+		default:
+			if !r.eof() {
+				continue
+			}
+		}
+		bs = appendCharge(bs, global, count)
+		bs = append(bs, body[start:r.pos]...)
+		start, count = r.pos, 0
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return bs, nil
+}
+
+func instrumentCode(data []byte, global uint32) ([]byte, error) {
+	r := &wasmReader{buf: data}
+	n := r.u32()
+	bs := appendU32(nil, n)
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		body := r.bytes(int(r.u32()))
+		if r.err != nil {
+			break
+		}
+		nb, err := instrumentBody(body, global)
+		if err != nil {
+			return nil, err
+		}
+		bs = appendU32(bs, uint32(len(nb)))
+		bs = append(bs, nb...)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return bs, nil
+}
+
+// instrument injects the metering of steps to the module. The steps left
+// for the execution are kept in the mutable global exported as StepsGlobal.
+// The module traps before the block of instructions if the steps are not
+// enough for it. So the execution is stopped deterministically regardless
+// of the performance of the node. Instructions using floating-point numbers
+// are rejected, because their results may differ among nodes.
+func instrument(code []byte) ([]byte, error) {
+	if len(code) < len(wasmHeader) || !bytes.Equal(code[:len(wasmHeader)], wasmHeader) {
+		return nil, scoreresult.InvalidPackageError.New("InvalidHeader")
+	}
+	type section struct {
+		id   byte
+		data []byte
+	}
+	var sections []section
+	var global uint32
+	var hasGlobal, hasExport bool
+	r := &wasmReader{buf: code, pos: len(wasmHeader)}
+	for !r.eof() {
+		s := section{id: r.byte()}
+		s.data = r.bytes(int(r.u32()))
+		if r.err != nil {
+			return nil, r.err
+		}
+		switch s.id {
+		case secImport:
+			n, err := countImportedGlobals(s.data)
+			if err != nil {
+				return nil, err
+			}
+			global += n
+		case secGlobal:
+			sr := &wasmReader{buf: s.data}
+			global += sr.u32()
+			if sr.err != nil {
+				return nil, sr.err
+			}
+			hasGlobal = true
+		case secExport:
+			hasExport = true
+		}
+		sections = append(sections, s)
+	}
+
+	bs := append([]byte{}, wasmHeader...)
+	addMissing := func(order int) {
+		if !hasGlobal && order > secGlobal {
+			bs = appendSection(bs, secGlobal, addGlobal(appendU32(nil, 0)))
+			hasGlobal = true
+		}
+		if !hasExport && order > secExport {
+			data, _ := addExport(appendU32(nil, 0), global)
+			bs = appendSection(bs, secExport, data)
+			hasExport = true
+		}
+	}
+	for _, s := range sections {
+		if s.id != secCustom {
+			addMissing(sectionOrder(s.id))
+		}
+		data := s.data
+		switch s.id {
+		case secGlobal:
+			data = addGlobal(data)
+		case secExport:
+			var err error
+			if data, err = addExport(data, global); err != nil {
+				return nil, err
+			}
+		case secCode:
+			var err error
+			if data, err = instrumentCode(data, global); err != nil {
+				return nil, err
+			}
+		}
+		bs = appendSection(bs, s.id, data)
+	}
+	addMissing(secDataCount + 1)
+	return bs, nil
+}
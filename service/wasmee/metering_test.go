@@ -0,0 +1,79 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wasmee
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+func moduleWithBody(body ...byte) []byte {
+	code := append([]byte{}, wasmHeader...)
+	code = appendSection(code, 1, []byte{1, 0x60, 0, 0})
+	code = appendSection(code, 3, []byte{1, 0})
+	data := appendU32([]byte{1}, uint32(len(body)+1))
+	data = append(append(data, 0), body...)
+	return appendSection(code, secCode, data)
+}
+
+func TestInstrument(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   []byte
+		status module.Status
+	}{
+		{"InvalidHeader", []byte("invalid"), module.StatusInvalidPackage},
+		{"Valid", moduleWithBody(opLoop, typeEmptyType, opBr, 0, opEnd, opEnd), module.StatusSuccess},
+		{"StepsGlobal", moduleWithBody(opGlobalGet, 0, 0x1a, opEnd), module.StatusInvalidPackage},
+		{"SIMD", moduleWithBody(0xfd, 0x0c, opEnd), module.StatusInvalidPackage},
+		{"FloatConst", moduleWithBody(opF32Const, 0, 0, 0, 0, 0x1a, opEnd), module.StatusInvalidPackage},
+		{"FloatLoad", moduleWithBody(opI32Const, 0, opF64Load, 3, 0, 0x1a, opEnd), module.StatusInvalidPackage},
+		{"FloatArith", moduleWithBody(opI32Const, 0, opI32Const, 0, 0x92, 0x1a, opEnd), module.StatusInvalidPackage},
+		{"FloatFromInt", moduleWithBody(opI64Const, 0, opF64FromI64, 0x1a, opEnd), module.StatusInvalidPackage},
+		{"FloatTruncSat", moduleWithBody(opI32Const, 0, opPrefixFC, 0, 0x1a, opEnd), module.StatusInvalidPackage},
+		{"Truncated", moduleWithBody(opI32Const), module.StatusInvalidPackage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := instrument(tt.code)
+			status, _ := scoreresult.StatusOf(err)
+			assert.Equal(t, tt.status, status)
+		})
+	}
+}
+
+func TestInstrumentBody(t *testing.T) {
+	// (loop (br_if 0 (i32.const 1))) (nop)
+	body := []byte{0, opLoop, typeEmptyType, opI32Const, 1, opBrIf, 0, opEnd, 0x01, opEnd}
+	bs, err := instrumentBody(body, 3)
+	assert.NoError(t, err)
+
+	var exp []byte
+	exp = appendCharge(append(exp, 0), 3, 1)
+	exp = append(exp, opLoop, typeEmptyType)
+	exp = appendCharge(exp, 3, 2)
+	exp = append(exp, opI32Const, 1, opBrIf, 0)
+	exp = appendCharge(exp, 3, 1)
+	exp = append(exp, opEnd)
+	exp = appendCharge(exp, 3, 2)
+	exp = append(exp, 0x01, opEnd)
+	assert.Equal(t, exp, bs)
+}
@@ -0,0 +1,243 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wasmee
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+const (
+	// CodeFile is the name of the file storing the code of the contract
+	// in the directory prepared by the contract manager.
+	CodeFile = "code.wasm"
+
+	// memoryLimitPages limits the memory of a contract instance to 16MB.
+	memoryLimitPages = 256
+)
+
+// Host provides the environment of the execution to the contract.
+type Host interface {
+	// StepsLeft returns the steps available for the execution.
+	StepsLeft() int64
+	// DeductSteps charges the steps used by the instructions.
+	DeductSteps(steps int64) error
+
+	GetValue(key []byte) ([]byte, error)
+	SetValue(key []byte, value []byte) error
+	DeleteValue(key []byte) error
+	GetBalance(addr module.Address) (*big.Int, error)
+	GetInfo() interface{}
+	OnEvent(indexed, data [][]byte) error
+	Call(to module.Address, value *big.Int, method string, params *codec.TypedObj) (*codec.TypedObj, error)
+	Log(msg string)
+}
+
+type contract struct {
+	compiled wazero.CompiledModule
+	info     *scoreapi.Info
+}
+
+// Runtime executes contracts in WebAssembly in the process.
+type Runtime struct {
+	lock      sync.Mutex
+	runtime   wazero.Runtime
+	contracts map[string]*contract
+}
+
+func (r *Runtime) load(ctx context.Context, path string) (*contract, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if c, ok := r.contracts[path]; ok {
+		return c, nil
+	}
+	code, err := os.ReadFile(filepath.Join(path, CodeFile))
+	if err != nil {
+		return nil, errors.CriticalIOError.Wrapf(err, "FailToReadCode(path=%s)", path)
+	}
+	code, err = instrument(code)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := r.runtime.CompileModule(ctx, code)
+	if err != nil {
+		return nil, scoreresult.InvalidPackageError.Wrap(err, "InvalidModule")
+	}
+	info, err := apiOf(compiled)
+	if err != nil {
+		_ = compiled.Close(ctx)
+		return nil, err
+	}
+	c := &contract{compiled: compiled, info: info}
+	r.contracts[path] = c
+	return c, nil
+}
+
+func apiOf(compiled wazero.CompiledModule) (*scoreapi.Info, error) {
+	var methods []*scoreapi.Method
+	for _, section := range compiled.CustomSections() {
+		if section.Name() == APISection {
+			if ms, err := parseAPI(section.Data()); err != nil {
+				return nil, err
+			} else {
+				methods = ms
+			}
+		}
+	}
+	if methods == nil {
+		return nil, scoreresult.InvalidPackageError.New("NoAPISection")
+	}
+	exports := compiled.ExportedFunctions()
+	for _, method := range methods {
+		if method.IsEvent() {
+			continue
+		}
+		def, ok := exports[method.Name]
+		if !ok {
+			return nil, scoreresult.InvalidPackageError.Errorf(
+				"NoExportedFunction(name=%s)", method.Name)
+		}
+		if len(def.ParamTypes()) != 0 || len(def.ResultTypes()) != 0 {
+			return nil, scoreresult.InvalidPackageError.Errorf(
+				"InvalidFunctionType(name=%s)", method.Name)
+		}
+	}
+	return scoreapi.NewInfo(methods), nil
+}
+
+// GetAPI returns API of the contract stored in the path.
+func (r *Runtime) GetAPI(ctx context.Context, path string) (*scoreapi.Info, error) {
+	c, err := r.load(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return c.info, nil
+}
+
+// Invoke executes the method of the contract stored in the path.
+// Parameters are passed to the contract in JSON, and the result set by
+// the contract is converted to the type of the output of the method.
+// The execution is stopped only by running out of steps given by the host,
+// so the result is same on every node.
+func (r *Runtime) Invoke(ctx context.Context, host Host, path string, method string, params *codec.TypedObj) (*codec.TypedObj, error) {
+	c, err := r.load(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	m := c.info.GetMethod(method)
+	if m == nil && method == FallbackName {
+		m = c.info.GetMethod(scoreapi.FallbackMethodName)
+	}
+	if m == nil || !m.IsCallable() {
+		return nil, scoreresult.MethodNotFoundError.Errorf("MethodNotFound(%s)", method)
+	}
+
+	inv := &invocation{host: host, contract: c}
+	if inv.params, err = paramsToJSON(params); err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, invocationKey{}, inv)
+	mod, err := r.runtime.InstantiateModule(ctx, c.compiled,
+		wazero.NewModuleConfig().WithName("").WithStartFunctions())
+	if err != nil {
+		if inv.err != nil {
+			return nil, inv.err
+		}
+		return nil, scoreresult.InvalidInstanceError.Wrap(err, "FailToInstantiate")
+	}
+	defer mod.Close(ctx)
+
+	steps, ok := mod.ExportedGlobal(StepsGlobal).(api.MutableGlobal)
+	if !ok {
+		return nil, scoreresult.InvalidInstanceError.New("NoStepsGlobal")
+	}
+	inv.steps = steps
+	inv.resetSteps()
+	if _, err := mod.ExportedFunction(m.Name).Call(ctx); err != nil {
+		if inv.err != nil {
+			return nil, inv.err
+		}
+		if err := inv.syncSteps(); err != nil {
+			return nil, err
+		}
+		return nil, scoreresult.UnknownFailureError.Wrap(err, "Trap")
+	}
+	if err := inv.syncSteps(); err != nil {
+		return nil, err
+	}
+	return resultOf(m, inv.result)
+}
+
+func (r *Runtime) Close() error {
+	return r.runtime.Close(context.Background())
+}
+
+func paramsToJSON(params *codec.TypedObj) ([]byte, error) {
+	if params == nil {
+		return []byte("[]"), nil
+	}
+	jso, err := common.DecodeAnyForJSON(params)
+	if err != nil {
+		return nil, scoreresult.InvalidParameterError.Wrap(err, "InvalidParams")
+	}
+	return json.Marshal(jso)
+}
+
+func resultOf(m *scoreapi.Method, result []byte) (*codec.TypedObj, error) {
+	if result == nil || len(m.Outputs) == 0 {
+		return codec.Nil, nil
+	}
+	t := m.Outputs[0]
+	if t.ListDepth() == 0 && t.Tag() != scoreapi.TList && t.Tag() != scoreapi.TDict {
+		return t.ConvertJSONToTypedObj(result, nil, true)
+	}
+	var value interface{}
+	if err := json.Unmarshal(result, &value); err != nil {
+		return nil, scoreresult.UnknownFailureError.Wrap(err, "InvalidResult")
+	}
+	return common.EncodeAny(value)
+}
+
+func NewRuntime(ctx context.Context) (*Runtime, error) {
+	rt := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithCoreFeatures(api.CoreFeaturesV2.SetEnabled(api.CoreFeatureSIMD, false)).
+		WithCustomSections(true).
+		WithMemoryLimitPages(memoryLimitPages))
+	if err := instantiateHostModule(ctx, rt); err != nil {
+		_ = rt.Close(ctx)
+		return nil, err
+	}
+	return &Runtime{
+		runtime:   rt,
+		contracts: make(map[string]*contract),
+	}, nil
+}
@@ -0,0 +1,211 @@
+package wasmee
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+type testEvent struct {
+	indexed [][]byte
+	data    [][]byte
+}
+
+type testHost struct {
+	limit  int64
+	used   int64
+	store  map[string][]byte
+	events []testEvent
+	called []string
+}
+
+func (h *testHost) StepsLeft() int64 {
+	return h.limit - h.used
+}
+
+func (h *testHost) DeductSteps(steps int64) error {
+	h.used += steps
+	if h.used > h.limit {
+		h.used = h.limit
+		return scoreresult.ErrOutOfStep
+	}
+	return nil
+}
+
+func (h *testHost) GetValue(key []byte) ([]byte, error) {
+	return h.store[string(key)], nil
+}
+
+func (h *testHost) SetValue(key []byte, value []byte) error {
+	h.store[string(key)] = value
+	return nil
+}
+
+func (h *testHost) DeleteValue(key []byte) error {
+	delete(h.store, string(key))
+	return nil
+}
+
+func (h *testHost) GetBalance(addr module.Address) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (h *testHost) GetInfo() interface{} {
+	return map[string]interface{}{}
+}
+
+func (h *testHost) OnEvent(indexed, data [][]byte) error {
+	h.events = append(h.events, testEvent{indexed, data})
+	return nil
+}
+
+func (h *testHost) Call(to module.Address, value *big.Int, method string, params *codec.TypedObj) (*codec.TypedObj, error) {
+	h.called = append(h.called, to.String()+"."+method)
+	return common.EncodeAny(common.NewHexInt(0x27))
+}
+
+func (h *testHost) Log(msg string) {
+}
+
+func prepareCounter(t *testing.T) string {
+	code, err := os.ReadFile(filepath.Join("testdata", "counter.wasm"))
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, CodeFile), code, 0644))
+	return dir
+}
+
+func TestRuntime_Counter(t *testing.T) {
+	ctx := context.Background()
+	rt, err := NewRuntime(ctx)
+	assert.NoError(t, err)
+	defer rt.Close()
+
+	path := prepareCounter(t)
+	info, err := rt.GetAPI(ctx, path)
+	assert.NoError(t, err)
+	assert.True(t, info.GetMethod("get").IsReadOnly())
+	assert.False(t, info.GetMethod(InstallMethod).IsExternal())
+	assert.True(t, info.GetMethod("Changed(int)").IsEvent())
+
+	host := &testHost{limit: 1000, store: make(map[string][]byte)}
+	_, err = rt.Invoke(ctx, host, path, InstallMethod, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("\"0x0\""), host.store["count"])
+	// 4 constants, call and end
+	assert.Equal(t, int64(6), host.used)
+
+	params := common.MustEncodeAny([]interface{}{common.NewHexInt(0x12)})
+	result, err := rt.Invoke(ctx, host, path, "set", params)
+	assert.NoError(t, err)
+	assert.Equal(t, codec.Nil, result)
+	assert.Equal(t, 1, len(host.events))
+	assert.Equal(t, [][]byte{[]byte("Changed(int)"), {0x12}}, host.events[0].indexed)
+	assert.Nil(t, host.events[0].data)
+
+	result, err = rt.Invoke(ctx, host, path, "get", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, common.MustEncodeAny(common.NewHexInt(0x12)), result)
+
+	result, err = rt.Invoke(ctx, host, path, "forward", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, common.MustEncodeAny(common.NewHexInt(0x27)), result)
+	assert.Equal(t, []string{"cx0000000000000000000000000000000000000001.get"}, host.called)
+
+	_, err = rt.Invoke(ctx, host, path, "fail", nil)
+	status, _ := scoreresult.StatusOf(err)
+	assert.Equal(t, module.StatusReverted+7, status)
+	assert.Contains(t, err.Error(), "not allowed")
+
+	_, err = rt.Invoke(ctx, host, path, "unknown", nil)
+	assert.Error(t, err)
+}
+
+func TestRuntime_OutOfStep(t *testing.T) {
+	ctx := context.Background()
+	rt, err := NewRuntime(ctx)
+	assert.NoError(t, err)
+	defer rt.Close()
+
+	code, err := os.ReadFile(filepath.Join("testdata", "loop.wasm"))
+	assert.NoError(t, err)
+	path := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(path, CodeFile), code, 0644))
+
+	host := &testHost{limit: 10000, store: make(map[string][]byte)}
+	_, err = rt.Invoke(ctx, host, path, "run", nil)
+	status, _ := scoreresult.StatusOf(err)
+	assert.Equal(t, module.StatusOutOfStep, status)
+	assert.Equal(t, int64(10000), host.used)
+}
+
+func TestRuntime_InvalidModule(t *testing.T) {
+	ctx := context.Background()
+	rt, err := NewRuntime(ctx)
+	assert.NoError(t, err)
+	defer rt.Close()
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, CodeFile), []byte("invalid"), 0644))
+	_, err = rt.GetAPI(ctx, dir)
+	status, _ := scoreresult.StatusOf(err)
+	assert.Equal(t, module.StatusInvalidPackage, status)
+}
+
+func TestRuntime_FloatModule(t *testing.T) {
+	ctx := context.Background()
+	rt, err := NewRuntime(ctx)
+	assert.NoError(t, err)
+	defer rt.Close()
+
+	code, err := os.ReadFile(filepath.Join("testdata", "float.wasm"))
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, CodeFile), code, 0644))
+	_, err = rt.GetAPI(ctx, dir)
+	status, _ := scoreresult.StatusOf(err)
+	assert.Equal(t, module.StatusInvalidPackage, status)
+	assert.Contains(t, err.Error(), "UnsupportedInstruction")
+}
+
+func TestParseAPI(t *testing.T) {
+	methods, err := parseAPI([]byte(`[
+		{"type":"function","name":"transfer","inputs":[
+			{"name":"to","type":"Address"},
+			{"name":"data","type":"bytes","default":null}
+		],"outputs":[]},
+		{"type":"fallback","name":"fallback","inputs":[],"payable":"0x1"},
+		{"type":"eventlog","name":"Transfer","inputs":[
+			{"name":"to","type":"Address","indexed":"0x1"},
+			{"name":"value","type":"int"}
+		]}
+	]`))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(methods))
+	assert.Equal(t, 1, methods[0].Indexed)
+	assert.True(t, methods[0].IsExternal())
+	assert.True(t, methods[1].IsFallback())
+	assert.True(t, methods[1].IsPayable())
+	assert.Equal(t, "Transfer(Address,int)", methods[2].Signature())
+	assert.Equal(t, 1, methods[2].Indexed)
+
+	_, err = parseAPI([]byte(`[{"type":"function","name":"f","inputs":[
+		{"name":"a","type":"int","default":"0x1"},
+		{"name":"b","type":"int"}
+	],"outputs":[]}]`))
+	assert.Error(t, err)
+
+	_, err = parseAPI([]byte(`[{"type":"function","name":"f","inputs":[
+		{"name":"a","type":"unknown"}
+	],"outputs":[]}]`))
+	assert.Error(t, err)
+}
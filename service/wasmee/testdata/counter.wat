@@ -0,0 +1,42 @@
+;; Sample contract for the tests.
+;; counter.wasm is built from this file with the "icon:api" custom section
+;; including the content of counter.json.
+(module
+  (import "env" "get_params" (func $get_params (param i32 i32) (result i32)))
+  (import "env" "set_result" (func $set_result (param i32 i32)))
+  (import "env" "get_value" (func $get_value (param i32 i32 i32 i32) (result i32)))
+  (import "env" "set_value" (func $set_value (param i32 i32 i32 i32)))
+  (import "env" "emit_event" (func $emit_event (param i32 i32)))
+  (import "env" "revert" (func $revert (param i32 i32 i32)))
+  (import "env" "call" (func $call (param i32 i32 i32 i32) (result i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 0) "count")
+  (data (i32.const 16) "\"0x0\"")
+  (data (i32.const 64) "not allowed")
+  (data (i32.const 241) "[\"Changed(int)\",")
+  (data (i32.const 1024) "{\"to\":\"cx0000000000000000000000000000000000000001\",\"method\":\"get\",\"params\":[]}")
+
+  (func (export "on_install")
+    (call $set_value (i32.const 0) (i32.const 5) (i32.const 16) (i32.const 5)))
+
+  (func (export "get")
+    (call $set_result (i32.const 512)
+      (call $get_value (i32.const 0) (i32.const 5) (i32.const 512) (i32.const 128))))
+
+  ;; params are ["0x.."], so the value starts at 257 without brackets.
+  ;; the event is ["Changed(int)","0x.."] from 241 after restoring the comma.
+  (func (export "set")
+    (local $n i32)
+    (local.set $n (call $get_params (i32.const 256) (i32.const 128)))
+    (call $set_value (i32.const 0) (i32.const 5)
+      (i32.const 257) (i32.sub (local.get $n) (i32.const 2)))
+    (i32.store8 (i32.const 256) (i32.const 44))
+    (call $emit_event (i32.const 241) (i32.add (local.get $n) (i32.const 15))))
+
+  (func (export "fail")
+    (call $revert (i32.const 7) (i32.const 64) (i32.const 11)))
+
+  (func (export "forward")
+    (call $set_result (i32.const 768)
+      (call $call (i32.const 1024) (i32.const 78) (i32.const 768) (i32.const 128))))
+)
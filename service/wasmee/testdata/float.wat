@@ -0,0 +1,9 @@
+;; Sample contract using floating-point numbers, which must be rejected.
+;; float.wasm is built from this file with the "icon:api" custom section
+;; including the content of float.json.
+(module
+  (memory (export "memory") 1)
+
+  (func (export "run")
+    (drop (f64.div (f64.const 0) (f64.const 0))))
+)
@@ -0,0 +1,10 @@
+;; Sample contract running forever for the tests.
+;; loop.wasm is built from this file with the "icon:api" custom section
+;; including the content of loop.json.
+(module
+  (memory (export "memory") 1)
+
+  (func (export "run")
+    (loop $forever
+      (br $forever)))
+)
@@ -113,6 +113,18 @@ func (c *Chain) NephewsLimit() int {
 	panic("implement me")
 }
 
+func (c *Chain) PeerBanDuration() time.Duration {
+	panic("implement me")
+}
+
+func (c *Chain) PeerBanThreshold() int {
+	panic("implement me")
+}
+
+func (c *Chain) PeerPenaltyDecayTime() time.Duration {
+	panic("implement me")
+}
+
 func (c *Chain) ValidateTxOnSend() bool {
 	panic("implement me")
 }
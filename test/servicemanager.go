@@ -53,6 +53,7 @@ type ServiceManager struct {
 	nextBlockVersion int
 	pool             []module.Transaction
 	txWaiters        []func()
+	dsReports        [][]module.DoubleSignData
 }
 
 func NewServiceManager(
@@ -330,6 +331,22 @@ func (sm *ServiceManager) SendTransaction(result []byte, height int64, tx interf
 	return t.ID(), nil
 }
 
+func (sm *ServiceManager) SendDoubleSignReport(result []byte, vh []byte, data []module.DoubleSignData) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.dsReports = append(sm.dsReports, data)
+	return nil
+}
+
+// DoubleSignReports returns double sign reports sent to the manager.
+func (sm *ServiceManager) DoubleSignReports() [][]module.DoubleSignData {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return append([][]module.DoubleSignData(nil), sm.dsReports...)
+}
+
 func (sm *ServiceManager) ValidatorListFromHash(hash []byte) module.ValidatorList {
 	vl, err := state.ValidatorSnapshotFromHash(sm.dbase, hash)
 	if err != nil {